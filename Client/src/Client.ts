@@ -5,12 +5,18 @@ import {
   createRequestId,
   deconstructHeaders
 } from './headers';
-import { RequestObj, ResponseType } from './interfaces';
+import { RequestObj, RequestType, ResponseType } from './interfaces';
 import { unmarshal } from './unmarshal';
 import { clearTimeouts, logPacketInformation } from './utility';
 
 const ac = new AbortController();
 
+// Caps the byte array buffers a callback can be split into, the same as the server, so a malformed header cannot allocate more
+const MAX_BYTE_ARRAY_BUFFERS = 128;
+// How long the Request ID of a handled callback is kept to discard its retransmissions, the same as the Go client. The server
+// stops retransmitting a callback well before then.
+const HANDLED_CALLBACK_WINDOW = 60 * 1000;
+
 export class UDPClient {
   address: string;
   sendPort: number;
@@ -23,6 +29,8 @@ export class UDPClient {
   maxRetries: number;
   monitorMode: boolean;
   timer: any;
+  handledCallbacks: Map<string, number>;
+  pendingCallbacks: Map<string, { parts: Buffer[]; timer: NodeJS.Timeout }>;
 
   constructor(address: string, sendPort: number) {
     this.address = address; // IP Address of Server
@@ -36,6 +44,8 @@ export class UDPClient {
     this.maxRetries = 3;
     this.monitorMode = false;
     this.timer = [];
+    this.handledCallbacks = new Map(); // Times callbacks already received were received, by Request ID
+    this.pendingCallbacks = new Map(); // Byte array buffers of callbacks not fully received, by Request ID
   }
 
   private receiveResponse(buffer: Buffer) {
@@ -48,12 +58,96 @@ export class UDPClient {
       header.requestType,
       undefined
     );
-    const payload = unmarshal(buffer.subarray(26, 512), header.requestType);
+    let body = buffer.subarray(26, 512);
+
+    // The server retransmits callbacks until they are acked, so every callback is acked once all of its byte array
+    // buffers are received and retransmissions are ignored
+    if (header.requestType >= ResponseType.MonitorSeatUpdatesCallbackType) {
+      const now = Date.now();
+      this.handledCallbacks.forEach((receivedAt, requestId) => {
+        if (now - receivedAt > HANDLED_CALLBACK_WINDOW)
+          this.handledCallbacks.delete(requestId);
+      });
+      if (this.handledCallbacks.has(header.requestId)) {
+        this.ackCallback(header.requestId);
+        return;
+      }
+      const callbackBody = this.reassembleCallback(
+        header.requestId,
+        Number(header.byteArrayBufferNo),
+        Number(header.totalByteArrayBuffers),
+        body
+      );
+      if (callbackBody === undefined) return;
+      this.ackCallback(header.requestId);
+      this.handledCallbacks.set(header.requestId, now);
+      body = callbackBody;
+    }
+
+    const payload = unmarshal(body, header.requestType);
 
     if (typeof payload === 'string') console.log(payload);
     else return payload;
   }
 
+  // Callbacks larger than a byte array buffer are split the same way responses are, returns the full body once all of
+  // the byte array buffers are received. Callbacks not fully received within the retransmission timeout are discarded,
+  // the server sends them again in full if they are not acked.
+  private reassembleCallback(
+    callbackRequestId: string,
+    byteArrayBufferNo: number,
+    totalByteArrayBuffers: number,
+    body: Buffer
+  ) {
+    const pending = this.pendingCallbacks.get(callbackRequestId);
+    if (
+      totalByteArrayBuffers < 1 ||
+      totalByteArrayBuffers > MAX_BYTE_ARRAY_BUFFERS ||
+      byteArrayBufferNo < 1 ||
+      byteArrayBufferNo > totalByteArrayBuffers ||
+      (pending !== undefined && pending.parts.length !== totalByteArrayBuffers)
+    ) {
+      console.log(
+        `Discarding callback byte array buffer no. ${byteArrayBufferNo} out of ${totalByteArrayBuffers}`
+      );
+      return undefined;
+    }
+    if (totalByteArrayBuffers === 1) return body;
+
+    const parts = pending?.parts ?? new Array<Buffer>(totalByteArrayBuffers);
+    parts[byteArrayBufferNo - 1] = body;
+    if (pending === undefined) {
+      const timer = setTimeout(
+        () => this.pendingCallbacks.delete(callbackRequestId),
+        this.timeout
+      );
+      this.pendingCallbacks.set(callbackRequestId, { parts, timer });
+    }
+    for (let i = 0; i < totalByteArrayBuffers; i++) {
+      if (parts[i] === undefined) return undefined;
+    }
+    clearTimeout(this.pendingCallbacks.get(callbackRequestId)?.timer);
+    this.pendingCallbacks.delete(callbackRequestId);
+    return Buffer.concat(parts);
+  }
+
+  // Acks are one way, the server does not reply. The request ID is written as is, as the marshaller would send a numeric string as a number
+  private ackCallback(callbackRequestId: string) {
+    const header = constructHeaders(
+      RequestType.AckCallbackRequestType,
+      createRequestId(),
+      1,
+      1
+    );
+    const buffer = Buffer.concat(
+      [header, Buffer.from(callbackRequestId + '\0')],
+      512
+    );
+    this.client.send(buffer, this.sendPort, this.address, (err) => {
+      if (err) console.log(`Error sending ack: ${err}`);
+    });
+  }
+
   private constructHeaderWithPayload(
     payload: Buffer,
     requestType: number,
@@ -0,0 +1,864 @@
+// Code generated by rpcgen from flight_information_system.idl. DO NOT EDIT.
+
+import { MessageSchema } from '../marshal';
+
+export enum RequestType {
+  PingRequestType = 1,
+  GetFlightIdentifiersRequestType = 2,
+  GetFlightInformationRequestType = 3,
+  MakeSeatReservationRequestType = 4,
+  MonitorSeatUpdatesRequestType = 5,
+  UpdateFlightPriceRequestType = 6,
+  CreateFlightRequestType = 7,
+  CancelReservationRequestType = 8,
+  GetReservationRequestType = 9,
+  GetSeatMapRequestType = 10,
+  ReserveSpecificSeatsRequestType = 11,
+  HoldSeatsRequestType = 12,
+  ConfirmHoldRequestType = 13,
+  SearchFlightsRequestType = 14,
+  FindItinerariesRequestType = 15,
+  CreateScheduleRequestType = 16,
+  UpdateScheduleRequestType = 17,
+  CancelScheduleRequestType = 18,
+  UpdateFlightStatusRequestType = 19,
+  CancelFlightRequestType = 20,
+  MonitorPriceUpdatesRequestType = 21,
+  MonitorNewFlightsRequestType = 22,
+  MonitorFlightStatusRequestType = 23,
+  AckCallbackRequestType = 24,
+  GetUpdatesSinceRequestType = 25,
+  UnsubscribeRequestType = 26,
+  RenewSubscriptionRequestType = 27,
+  ListMySubscriptionsRequestType = 28
+}
+
+export enum ResponseType {
+  PingResponseType = 101,
+  GetFlightIdentifiersResponseType = 102,
+  GetFlightInformationResponseType = 103,
+  MakeSeatReservationResponseType = 104,
+  MonitorSeatUpdatesResponseType = 105,
+  UpdateFlightPriceResponseType = 106,
+  CreateFlightResponseType = 107,
+  CancelReservationResponseType = 108,
+  GetReservationResponseType = 109,
+  GetSeatMapResponseType = 110,
+  ReserveSpecificSeatsResponseType = 111,
+  HoldSeatsResponseType = 112,
+  ConfirmHoldResponseType = 113,
+  SearchFlightsResponseType = 114,
+  FindItinerariesResponseType = 115,
+  CreateScheduleResponseType = 116,
+  UpdateScheduleResponseType = 117,
+  CancelScheduleResponseType = 118,
+  UpdateFlightStatusResponseType = 119,
+  CancelFlightResponseType = 120,
+  MonitorPriceUpdatesResponseType = 121,
+  MonitorNewFlightsResponseType = 122,
+  MonitorFlightStatusResponseType = 123,
+  GetUpdatesSinceResponseType = 125,
+  UnsubscribeResponseType = 126,
+  RenewSubscriptionResponseType = 127,
+  ListMySubscriptionsResponseType = 128,
+  MonitorSeatUpdatesCallbackType = 201,
+  MonitorFlightStatusCallbackType = 202,
+  MonitorPriceUpdatesCallbackType = 203,
+  MonitorNewFlightsCallbackType = 204
+}
+
+// TimeFormatType is how times are rendered in responses, unix times are always returned
+export enum TimeFormatType {
+  UnixTimeFormat = 1,
+  // LocalTimeFormat additionally renders times in RFC 3339 in the local time of the airport, e.g. 2023-12-01T08:00:00+08:00
+  LocalTimeFormat = 2
+}
+
+// ResyncType is how the changes missed are returned by GetUpdatesSince
+export enum ResyncType {
+  // HistoryResync returns every change missed
+  HistoryResync = 1,
+  // SnapshotResync returns the current state of the flight, as some of the changes missed are no longer kept
+  SnapshotResync = 2
+}
+
+export type GetFlightIdentifiersRequest = {
+  SourceLocation: string;
+  DestinationLocation: string;
+};
+
+export type GetFlightIdentifiersResponse = {
+  FlightIdentifiers: number[];
+};
+
+export type GetFlightInformationRequest = {
+  FlightIdentifier: number;
+  TimeFormat: TimeFormatType;
+};
+
+// GetFlightInformationResponse LocalDepartureTime and LocalArrivalTime are only rendered if LocalTimeFormat is requested.
+// Status is the value of dao.FlightStatusType
+export type GetFlightInformationResponse = {
+  DepartureTime: bigint;
+  Airfare: number;
+  TotalAvailableSeats: number;
+  ArrivalTime: bigint;
+  DurationInSeconds: bigint;
+  SourceTimezone: string;
+  DestinationTimezone: string;
+  Aircraft: string;
+  LocalDepartureTime: string;
+  LocalArrivalTime: string;
+  Status: number;
+};
+
+export type MakeSeatReservationRequest = {
+  FlightIdentifier: number;
+  SeatsToReserve: number;
+};
+
+export type MakeSeatReservationResponse = {
+  BookingIdentifier: number;
+  SeatLabels: string[];
+};
+
+export type MonitorSeatUpdatesCallbackRequest = {
+  FlightIdentifier: number;
+  LengthOfMonitorIntervalInSeconds: bigint;
+};
+
+// MonitorSeatUpdatesCallbackResponse SequenceNumber is the sequence number of the change to the flight, a gap in the sequence
+// numbers received means changes were missed, see GetUpdatesSinceRequest
+export type MonitorSeatUpdatesCallbackResponse = {
+  TotalAvailableSeats: number;
+  SequenceNumber: bigint;
+  FlightIdentifier: number;
+};
+
+export type UpdateFlightPriceRequest = {
+  FlightIdentifier: number;
+  NewPrice: number;
+  TimeFormat: TimeFormatType;
+};
+
+// UpdateFlightPriceResponse LocalDepartureTime and LocalArrivalTime are only rendered if LocalTimeFormat is requested.
+// Status is the value of dao.FlightStatusType
+export type UpdateFlightPriceResponse = {
+  FlightIdentifier: number;
+  SourceLocation: string;
+  DestinationLocation: string;
+  DepartureTime: bigint;
+  Airfare: number;
+  TotalAvailableSeats: number;
+  ArrivalTime: bigint;
+  DurationInSeconds: bigint;
+  SourceTimezone: string;
+  DestinationTimezone: string;
+  Aircraft: string;
+  LocalDepartureTime: string;
+  LocalArrivalTime: string;
+  Status: number;
+};
+
+// CreateFlightRequest SourceTimezone and DestinationTimezone are IANA timezones, they default to the timezones of the
+// locations if they are known or UTC if they are left empty.
+export type CreateFlightRequest = {
+  SourceLocation: string;
+  DestinationLocation: string;
+  DepartureTime: bigint;
+  Airfare: number;
+  TotalAvailableSeats: number;
+  ArrivalTime: bigint;
+  SourceTimezone: string;
+  DestinationTimezone: string;
+  Aircraft: string;
+};
+
+export type CreateFlightResponse = {
+  FlightIdentifier: number;
+};
+
+export type CancelReservationRequest = {
+  BookingIdentifier: number;
+};
+
+export type CancelReservationResponse = {
+  FlightIdentifier: number;
+  SeatsCancelled: number;
+  TotalAvailableSeats: number;
+};
+
+export type GetReservationRequest = {
+  BookingIdentifier: number;
+};
+
+export type GetReservationResponse = {
+  BookingIdentifier: number;
+  FlightIdentifier: number;
+  SeatsReserved: number;
+  ClientAddress: string;
+  ReservationTime: bigint;
+  SeatLabels: string[];
+};
+
+export type GetSeatMapRequest = {
+  FlightIdentifier: number;
+};
+
+export type GetSeatMapResponse = {
+  FlightIdentifier: number;
+  Seats: SeatInformation[];
+};
+
+// SeatInformation is a single seat of the seat map. CabinClass and Status are the values of dao.CabinClassType and dao.SeatStatusType
+export type SeatInformation = {
+  SeatLabel: string;
+  CabinClass: number;
+  Status: number;
+};
+
+export type ReserveSpecificSeatsRequest = {
+  FlightIdentifier: number;
+  SeatLabels: string[];
+};
+
+export type ReserveSpecificSeatsResponse = {
+  BookingIdentifier: number;
+  SeatLabels: string[];
+};
+
+// HoldSeatsRequest holds SeatLabels if provided, else any SeatsToHold free seats
+export type HoldSeatsRequest = {
+  FlightIdentifier: number;
+  SeatsToHold: number;
+  SeatLabels: string[];
+  HoldDurationInSeconds: bigint;
+};
+
+export type HoldSeatsResponse = {
+  HoldToken: string;
+  SeatLabels: string[];
+  ExpiryTime: bigint;
+};
+
+export type ConfirmHoldRequest = {
+  HoldToken: string;
+};
+
+export type ConfirmHoldResponse = {
+  BookingIdentifier: number;
+  SeatLabels: string[];
+};
+
+// SearchFlightsRequest filters are not applied if left as 0 or empty. SortOrder is the value of database.FlightSortOrderType,
+// flights are sorted by flight identifier if it is 0. Limit defaults to 20 if it is 0.
+export type SearchFlightsRequest = {
+  SourceLocation: string;
+  DestinationLocation: string;
+  DepartureTimeFrom: bigint;
+  DepartureTimeTo: bigint;
+  MaxAirfare: number;
+  MinAvailableSeats: number;
+  SortOrder: number;
+  Offset: number;
+  Limit: number;
+};
+
+// SearchFlightsResponse contains the page of flights requested, TotalMatches is the number of flights across all pages
+export type SearchFlightsResponse = {
+  Flights: FlightInformation[];
+  TotalMatches: number;
+};
+
+// FlightInformation Status is the value of dao.FlightStatusType
+export type FlightInformation = {
+  FlightIdentifier: number;
+  SourceLocation: string;
+  DestinationLocation: string;
+  DepartureTime: bigint;
+  Airfare: number;
+  TotalAvailableSeats: number;
+  ArrivalTime: bigint;
+  DurationInSeconds: bigint;
+  Status: number;
+};
+
+// FindItinerariesRequest finds routes with up to MaxStops connections. RankBy is the value of routing.RankType, itineraries
+// are ranked by total airfare if it is 0. MinConnectionTimeInSeconds defaults to 1 hour and Limit to 10 if they are 0.
+export type FindItinerariesRequest = {
+  SourceLocation: string;
+  DestinationLocation: string;
+  MaxStops: number;
+  MinConnectionTimeInSeconds: bigint;
+  RankBy: number;
+  Limit: number;
+};
+
+export type FindItinerariesResponse = {
+  Itineraries: Itinerary[];
+};
+
+// Itinerary is a route of one or more flights, TotalDuration is the number of seconds from the departure of the first leg to the arrival of the last leg
+export type Itinerary = {
+  Legs: FlightInformation[];
+  TotalAirfare: number;
+  TotalDuration: bigint;
+};
+
+// CreateScheduleRequest DaysOfWeek is a bitmask of the days flights depart on, bit 0 is Sunday and bit 6 is Saturday.
+// LocalDepartureTime (e.g. 08:30) and the inclusive StartDate and EndDate (e.g. 2023-12-01) are in the source timezone.
+// Timezones default in the same way as CreateFlightRequest.
+export type CreateScheduleRequest = {
+  SourceLocation: string;
+  DestinationLocation: string;
+  SourceTimezone: string;
+  DestinationTimezone: string;
+  DaysOfWeek: number;
+  LocalDepartureTime: string;
+  DurationInSeconds: bigint;
+  StartDate: string;
+  EndDate: string;
+  Airfare: number;
+  TotalSeats: number;
+  Aircraft: string;
+};
+
+// CreateScheduleResponse contains the flight identifiers of every flight of the schedule in order of departure
+export type CreateScheduleResponse = {
+  ScheduleIdentifier: number;
+  FlightIdentifiers: number[];
+};
+
+// UpdateScheduleRequest fields left as 0 or empty are not updated
+export type UpdateScheduleRequest = {
+  ScheduleIdentifier: number;
+  LocalDepartureTime: string;
+  DurationInSeconds: bigint;
+  Airfare: number;
+  Aircraft: string;
+};
+
+// UpdateScheduleResponse future flights that have been booked or held are not updated
+export type UpdateScheduleResponse = {
+  FlightIdentifiersUpdated: number[];
+  FlightIdentifiersNotUpdated: number[];
+};
+
+export type CancelScheduleRequest = {
+  ScheduleIdentifier: number;
+};
+
+// CancelScheduleResponse future flights that have been booked or held are kept
+export type CancelScheduleResponse = {
+  FlightIdentifiersCancelled: number[];
+  FlightIdentifiersKept: number[];
+};
+
+// UpdateFlightStatusRequest Status is the value of dao.FlightStatusType. DepartureTime and ArrivalTime are the new times of
+// the flight, e.g. for a delay, and are left unchanged if 0. If only DepartureTime is provided the duration of the flight is kept.
+export type UpdateFlightStatusRequest = {
+  FlightIdentifier: number;
+  Status: number;
+  DepartureTime: bigint;
+  ArrivalTime: bigint;
+  TimeFormat: TimeFormatType;
+};
+
+// UpdateFlightStatusResponse LocalDepartureTime and LocalArrivalTime are only rendered if LocalTimeFormat is requested
+export type UpdateFlightStatusResponse = {
+  FlightIdentifier: number;
+  Status: number;
+  DepartureTime: bigint;
+  ArrivalTime: bigint;
+  LocalDepartureTime: string;
+  LocalArrivalTime: string;
+};
+
+export type CancelFlightRequest = {
+  FlightIdentifier: number;
+};
+
+export type MonitorFlightStatusCallbackRequest = {
+  FlightIdentifier: number;
+  LengthOfMonitorIntervalInSeconds: bigint;
+};
+
+// MonitorFlightStatusCallbackResponse Status is the value of dao.FlightStatusType
+export type MonitorFlightStatusCallbackResponse = {
+  FlightIdentifier: number;
+  Status: number;
+  DepartureTime: bigint;
+  ArrivalTime: bigint;
+  SequenceNumber: bigint;
+};
+
+export type MonitorPriceUpdatesCallbackRequest = {
+  FlightIdentifier: number;
+  LengthOfMonitorIntervalInSeconds: bigint;
+};
+
+export type MonitorPriceUpdatesCallbackResponse = {
+  FlightIdentifier: number;
+  Airfare: number;
+  SequenceNumber: bigint;
+};
+
+// MonitorNewFlightsCallbackRequest subscribes to flights created from SourceLocation to DestinationLocation, locations
+// are matched by any of their names in the same way as GetFlightIdentifiers
+export type MonitorNewFlightsCallbackRequest = {
+  SourceLocation: string;
+  DestinationLocation: string;
+  LengthOfMonitorIntervalInSeconds: bigint;
+};
+
+export type MonitorNewFlightsCallbackResponse = {
+  Flight: FlightInformation;
+};
+
+// AckCallbackRequest is sent by a subscriber for every callback received, RequestID is the requestID in the header of the callback
+export type AckCallbackRequest = {
+  RequestID: string;
+};
+
+// GetUpdatesSinceRequest gets the changes to a flight after SequenceNumber, the sequence number of the last callback received
+// for the flight. 0 gets every change kept. Sequence numbers are per UpdateType, the value of changelog.ChangeType of the
+// callbacks: 1 for seats, 2 for airfare and 3 for status.
+export type GetUpdatesSinceRequest = {
+  FlightIdentifier: number;
+  SequenceNumber: bigint;
+  UpdateType: number;
+};
+
+// GetUpdatesSinceResponse Updates are the changes missed in order if ResyncType is HistoryResync, else Updates only
+// contains the current state of the flight with the latest sequence number
+export type GetUpdatesSinceResponse = {
+  ResyncType: ResyncType;
+  Updates: FlightUpdate[];
+};
+
+// FlightUpdate is the state of a flight right after a change. UpdateType is the value of changelog.ChangeType, 0 for a snapshot.
+// Status is the value of dao.FlightStatusType
+export type FlightUpdate = {
+  SequenceNumber: bigint;
+  UpdateType: number;
+  TotalAvailableSeats: number;
+  Airfare: number;
+  Status: number;
+  DepartureTime: bigint;
+  ArrivalTime: bigint;
+};
+
+// MonitorSeatUpdatesResponse SubscriptionIdentifier is used to unsubscribe or renew the subscription, the same for every
+// Monitor RPC. Subscribing again to the same flight or route renews the existing subscription.
+export type MonitorSeatUpdatesResponse = {
+  SubscriptionIdentifier: bigint;
+};
+
+export type MonitorFlightStatusResponse = {
+  SubscriptionIdentifier: bigint;
+};
+
+export type MonitorPriceUpdatesResponse = {
+  SubscriptionIdentifier: bigint;
+};
+
+export type MonitorNewFlightsResponse = {
+  SubscriptionIdentifier: bigint;
+};
+
+// UnsubscribeRequest stops the callbacks of a subscription before it expires
+export type UnsubscribeRequest = {
+  SubscriptionIdentifier: bigint;
+};
+
+// RenewSubscriptionRequest makes a subscription expire LengthOfMonitorIntervalInSeconds from now instead
+export type RenewSubscriptionRequest = {
+  SubscriptionIdentifier: bigint;
+  LengthOfMonitorIntervalInSeconds: bigint;
+};
+
+export type RenewSubscriptionResponse = {
+  Subscription: SubscriptionInformation;
+};
+
+// ListMySubscriptionsResponse lists the subscriptions of the IP:Port of the request that have not expired
+export type ListMySubscriptionsResponse = {
+  Subscriptions: SubscriptionInformation[];
+};
+
+// SubscriptionInformation CallbackType is the ResponseType of the callbacks of the subscription, Item is the flight identifier
+// or route subscribed to in JSON and ExpiryTime is in unix seconds
+export type SubscriptionInformation = {
+  SubscriptionIdentifier: bigint;
+  CallbackType: number;
+  Item: string;
+  ExpiryTime: bigint;
+};
+
+export const GetFlightIdentifiersRequestSchema: MessageSchema = [
+  ['SourceLocation', 'string'],
+  ['DestinationLocation', 'string']
+];
+
+export const GetFlightIdentifiersResponseSchema: MessageSchema = [
+  ['FlightIdentifiers', { array: 'int32' }]
+];
+
+export const GetFlightInformationRequestSchema: MessageSchema = [
+  ['FlightIdentifier', 'int32'],
+  ['TimeFormat', 'uint8']
+];
+
+export const GetFlightInformationResponseSchema: MessageSchema = [
+  ['DepartureTime', 'int64'],
+  ['Airfare', 'float64'],
+  ['TotalAvailableSeats', 'int32'],
+  ['ArrivalTime', 'int64'],
+  ['DurationInSeconds', 'int64'],
+  ['SourceTimezone', 'string'],
+  ['DestinationTimezone', 'string'],
+  ['Aircraft', 'string'],
+  ['LocalDepartureTime', 'string'],
+  ['LocalArrivalTime', 'string'],
+  ['Status', 'uint8']
+];
+
+export const MakeSeatReservationRequestSchema: MessageSchema = [
+  ['FlightIdentifier', 'int32'],
+  ['SeatsToReserve', 'int32']
+];
+
+export const MakeSeatReservationResponseSchema: MessageSchema = [
+  ['BookingIdentifier', 'int32'],
+  ['SeatLabels', { array: 'string' }]
+];
+
+export const MonitorSeatUpdatesCallbackRequestSchema: MessageSchema = [
+  ['FlightIdentifier', 'int32'],
+  ['LengthOfMonitorIntervalInSeconds', 'int64']
+];
+
+export const MonitorSeatUpdatesCallbackResponseSchema: MessageSchema = [
+  ['TotalAvailableSeats', 'int32'],
+  ['SequenceNumber', 'int64'],
+  ['FlightIdentifier', 'int32']
+];
+
+export const UpdateFlightPriceRequestSchema: MessageSchema = [
+  ['FlightIdentifier', 'int32'],
+  ['NewPrice', 'float64'],
+  ['TimeFormat', 'uint8']
+];
+
+export const UpdateFlightPriceResponseSchema: MessageSchema = [
+  ['FlightIdentifier', 'int32'],
+  ['SourceLocation', 'string'],
+  ['DestinationLocation', 'string'],
+  ['DepartureTime', 'int64'],
+  ['Airfare', 'float64'],
+  ['TotalAvailableSeats', 'int32'],
+  ['ArrivalTime', 'int64'],
+  ['DurationInSeconds', 'int64'],
+  ['SourceTimezone', 'string'],
+  ['DestinationTimezone', 'string'],
+  ['Aircraft', 'string'],
+  ['LocalDepartureTime', 'string'],
+  ['LocalArrivalTime', 'string'],
+  ['Status', 'uint8']
+];
+
+export const CreateFlightRequestSchema: MessageSchema = [
+  ['SourceLocation', 'string'],
+  ['DestinationLocation', 'string'],
+  ['DepartureTime', 'int64'],
+  ['Airfare', 'float64'],
+  ['TotalAvailableSeats', 'int32'],
+  ['ArrivalTime', 'int64'],
+  ['SourceTimezone', 'string'],
+  ['DestinationTimezone', 'string'],
+  ['Aircraft', 'string']
+];
+
+export const CreateFlightResponseSchema: MessageSchema = [
+  ['FlightIdentifier', 'int32']
+];
+
+export const CancelReservationRequestSchema: MessageSchema = [
+  ['BookingIdentifier', 'int32']
+];
+
+export const CancelReservationResponseSchema: MessageSchema = [
+  ['FlightIdentifier', 'int32'],
+  ['SeatsCancelled', 'int32'],
+  ['TotalAvailableSeats', 'int32']
+];
+
+export const GetReservationRequestSchema: MessageSchema = [
+  ['BookingIdentifier', 'int32']
+];
+
+export const GetReservationResponseSchema: MessageSchema = [
+  ['BookingIdentifier', 'int32'],
+  ['FlightIdentifier', 'int32'],
+  ['SeatsReserved', 'int32'],
+  ['ClientAddress', 'string'],
+  ['ReservationTime', 'int64'],
+  ['SeatLabels', { array: 'string' }]
+];
+
+export const GetSeatMapRequestSchema: MessageSchema = [
+  ['FlightIdentifier', 'int32']
+];
+
+export const SeatInformationSchema: MessageSchema = [
+  ['SeatLabel', 'string'],
+  ['CabinClass', 'uint8'],
+  ['Status', 'uint8']
+];
+
+export const GetSeatMapResponseSchema: MessageSchema = [
+  ['FlightIdentifier', 'int32'],
+  ['Seats', { array: { message: SeatInformationSchema } }]
+];
+
+export const ReserveSpecificSeatsRequestSchema: MessageSchema = [
+  ['FlightIdentifier', 'int32'],
+  ['SeatLabels', { array: 'string' }]
+];
+
+export const ReserveSpecificSeatsResponseSchema: MessageSchema = [
+  ['BookingIdentifier', 'int32'],
+  ['SeatLabels', { array: 'string' }]
+];
+
+export const HoldSeatsRequestSchema: MessageSchema = [
+  ['FlightIdentifier', 'int32'],
+  ['SeatsToHold', 'int32'],
+  ['SeatLabels', { array: 'string' }],
+  ['HoldDurationInSeconds', 'int64']
+];
+
+export const HoldSeatsResponseSchema: MessageSchema = [
+  ['HoldToken', 'string'],
+  ['SeatLabels', { array: 'string' }],
+  ['ExpiryTime', 'int64']
+];
+
+export const ConfirmHoldRequestSchema: MessageSchema = [
+  ['HoldToken', 'string']
+];
+
+export const ConfirmHoldResponseSchema: MessageSchema = [
+  ['BookingIdentifier', 'int32'],
+  ['SeatLabels', { array: 'string' }]
+];
+
+export const SearchFlightsRequestSchema: MessageSchema = [
+  ['SourceLocation', 'string'],
+  ['DestinationLocation', 'string'],
+  ['DepartureTimeFrom', 'int64'],
+  ['DepartureTimeTo', 'int64'],
+  ['MaxAirfare', 'float64'],
+  ['MinAvailableSeats', 'int32'],
+  ['SortOrder', 'uint8'],
+  ['Offset', 'int32'],
+  ['Limit', 'int32']
+];
+
+export const FlightInformationSchema: MessageSchema = [
+  ['FlightIdentifier', 'int32'],
+  ['SourceLocation', 'string'],
+  ['DestinationLocation', 'string'],
+  ['DepartureTime', 'int64'],
+  ['Airfare', 'float64'],
+  ['TotalAvailableSeats', 'int32'],
+  ['ArrivalTime', 'int64'],
+  ['DurationInSeconds', 'int64'],
+  ['Status', 'uint8']
+];
+
+export const SearchFlightsResponseSchema: MessageSchema = [
+  ['Flights', { array: { message: FlightInformationSchema } }],
+  ['TotalMatches', 'int32']
+];
+
+export const FindItinerariesRequestSchema: MessageSchema = [
+  ['SourceLocation', 'string'],
+  ['DestinationLocation', 'string'],
+  ['MaxStops', 'int32'],
+  ['MinConnectionTimeInSeconds', 'int64'],
+  ['RankBy', 'uint8'],
+  ['Limit', 'int32']
+];
+
+export const ItinerarySchema: MessageSchema = [
+  ['Legs', { array: { message: FlightInformationSchema } }],
+  ['TotalAirfare', 'float64'],
+  ['TotalDuration', 'int64']
+];
+
+export const FindItinerariesResponseSchema: MessageSchema = [
+  ['Itineraries', { array: { message: ItinerarySchema } }]
+];
+
+export const CreateScheduleRequestSchema: MessageSchema = [
+  ['SourceLocation', 'string'],
+  ['DestinationLocation', 'string'],
+  ['SourceTimezone', 'string'],
+  ['DestinationTimezone', 'string'],
+  ['DaysOfWeek', 'uint8'],
+  ['LocalDepartureTime', 'string'],
+  ['DurationInSeconds', 'int64'],
+  ['StartDate', 'string'],
+  ['EndDate', 'string'],
+  ['Airfare', 'float64'],
+  ['TotalSeats', 'int32'],
+  ['Aircraft', 'string']
+];
+
+export const CreateScheduleResponseSchema: MessageSchema = [
+  ['ScheduleIdentifier', 'int32'],
+  ['FlightIdentifiers', { array: 'int32' }]
+];
+
+export const UpdateScheduleRequestSchema: MessageSchema = [
+  ['ScheduleIdentifier', 'int32'],
+  ['LocalDepartureTime', 'string'],
+  ['DurationInSeconds', 'int64'],
+  ['Airfare', 'float64'],
+  ['Aircraft', 'string']
+];
+
+export const UpdateScheduleResponseSchema: MessageSchema = [
+  ['FlightIdentifiersUpdated', { array: 'int32' }],
+  ['FlightIdentifiersNotUpdated', { array: 'int32' }]
+];
+
+export const CancelScheduleRequestSchema: MessageSchema = [
+  ['ScheduleIdentifier', 'int32']
+];
+
+export const CancelScheduleResponseSchema: MessageSchema = [
+  ['FlightIdentifiersCancelled', { array: 'int32' }],
+  ['FlightIdentifiersKept', { array: 'int32' }]
+];
+
+export const UpdateFlightStatusRequestSchema: MessageSchema = [
+  ['FlightIdentifier', 'int32'],
+  ['Status', 'uint8'],
+  ['DepartureTime', 'int64'],
+  ['ArrivalTime', 'int64'],
+  ['TimeFormat', 'uint8']
+];
+
+export const UpdateFlightStatusResponseSchema: MessageSchema = [
+  ['FlightIdentifier', 'int32'],
+  ['Status', 'uint8'],
+  ['DepartureTime', 'int64'],
+  ['ArrivalTime', 'int64'],
+  ['LocalDepartureTime', 'string'],
+  ['LocalArrivalTime', 'string']
+];
+
+export const CancelFlightRequestSchema: MessageSchema = [
+  ['FlightIdentifier', 'int32']
+];
+
+export const MonitorFlightStatusCallbackRequestSchema: MessageSchema = [
+  ['FlightIdentifier', 'int32'],
+  ['LengthOfMonitorIntervalInSeconds', 'int64']
+];
+
+export const MonitorFlightStatusCallbackResponseSchema: MessageSchema = [
+  ['FlightIdentifier', 'int32'],
+  ['Status', 'uint8'],
+  ['DepartureTime', 'int64'],
+  ['ArrivalTime', 'int64'],
+  ['SequenceNumber', 'int64']
+];
+
+export const MonitorPriceUpdatesCallbackRequestSchema: MessageSchema = [
+  ['FlightIdentifier', 'int32'],
+  ['LengthOfMonitorIntervalInSeconds', 'int64']
+];
+
+export const MonitorPriceUpdatesCallbackResponseSchema: MessageSchema = [
+  ['FlightIdentifier', 'int32'],
+  ['Airfare', 'float64'],
+  ['SequenceNumber', 'int64']
+];
+
+export const MonitorNewFlightsCallbackRequestSchema: MessageSchema = [
+  ['SourceLocation', 'string'],
+  ['DestinationLocation', 'string'],
+  ['LengthOfMonitorIntervalInSeconds', 'int64']
+];
+
+export const MonitorNewFlightsCallbackResponseSchema: MessageSchema = [
+  ['Flight', { message: FlightInformationSchema }]
+];
+
+export const AckCallbackRequestSchema: MessageSchema = [
+  ['RequestID', 'string']
+];
+
+export const GetUpdatesSinceRequestSchema: MessageSchema = [
+  ['FlightIdentifier', 'int32'],
+  ['SequenceNumber', 'int64'],
+  ['UpdateType', 'uint8']
+];
+
+export const FlightUpdateSchema: MessageSchema = [
+  ['SequenceNumber', 'int64'],
+  ['UpdateType', 'uint8'],
+  ['TotalAvailableSeats', 'int32'],
+  ['Airfare', 'float64'],
+  ['Status', 'uint8'],
+  ['DepartureTime', 'int64'],
+  ['ArrivalTime', 'int64']
+];
+
+export const GetUpdatesSinceResponseSchema: MessageSchema = [
+  ['ResyncType', 'uint8'],
+  ['Updates', { array: { message: FlightUpdateSchema } }]
+];
+
+export const MonitorSeatUpdatesResponseSchema: MessageSchema = [
+  ['SubscriptionIdentifier', 'int64']
+];
+
+export const MonitorFlightStatusResponseSchema: MessageSchema = [
+  ['SubscriptionIdentifier', 'int64']
+];
+
+export const MonitorPriceUpdatesResponseSchema: MessageSchema = [
+  ['SubscriptionIdentifier', 'int64']
+];
+
+export const MonitorNewFlightsResponseSchema: MessageSchema = [
+  ['SubscriptionIdentifier', 'int64']
+];
+
+export const UnsubscribeRequestSchema: MessageSchema = [
+  ['SubscriptionIdentifier', 'int64']
+];
+
+export const RenewSubscriptionRequestSchema: MessageSchema = [
+  ['SubscriptionIdentifier', 'int64'],
+  ['LengthOfMonitorIntervalInSeconds', 'int64']
+];
+
+export const SubscriptionInformationSchema: MessageSchema = [
+  ['SubscriptionIdentifier', 'int64'],
+  ['CallbackType', 'uint8'],
+  ['Item', 'string'],
+  ['ExpiryTime', 'int64']
+];
+
+export const RenewSubscriptionResponseSchema: MessageSchema = [
+  ['Subscription', { message: SubscriptionInformationSchema }]
+];
+
+export const ListMySubscriptionsResponseSchema: MessageSchema = [
+  ['Subscriptions', { array: { message: SubscriptionInformationSchema } }]
+];
@@ -0,0 +1,299 @@
+// Code generated by rpcgen from flight_information_system.idl. DO NOT EDIT.
+
+import { Buffer } from 'buffer';
+import { UDPClient } from '../Client';
+import { marshalMessage } from '../marshal';
+import {
+  RequestType,
+  GetFlightIdentifiersRequest,
+  GetFlightIdentifiersRequestSchema,
+  GetFlightInformationRequest,
+  GetFlightInformationRequestSchema,
+  MakeSeatReservationRequest,
+  MakeSeatReservationRequestSchema,
+  MonitorSeatUpdatesCallbackRequest,
+  MonitorSeatUpdatesCallbackRequestSchema,
+  UpdateFlightPriceRequest,
+  UpdateFlightPriceRequestSchema,
+  CreateFlightRequest,
+  CreateFlightRequestSchema,
+  CancelReservationRequest,
+  CancelReservationRequestSchema,
+  GetReservationRequest,
+  GetReservationRequestSchema,
+  GetSeatMapRequest,
+  GetSeatMapRequestSchema,
+  ReserveSpecificSeatsRequest,
+  ReserveSpecificSeatsRequestSchema,
+  HoldSeatsRequest,
+  HoldSeatsRequestSchema,
+  ConfirmHoldRequest,
+  ConfirmHoldRequestSchema,
+  SearchFlightsRequest,
+  SearchFlightsRequestSchema,
+  FindItinerariesRequest,
+  FindItinerariesRequestSchema,
+  CreateScheduleRequest,
+  CreateScheduleRequestSchema,
+  UpdateScheduleRequest,
+  UpdateScheduleRequestSchema,
+  CancelScheduleRequest,
+  CancelScheduleRequestSchema,
+  UpdateFlightStatusRequest,
+  UpdateFlightStatusRequestSchema,
+  CancelFlightRequest,
+  CancelFlightRequestSchema,
+  MonitorPriceUpdatesCallbackRequest,
+  MonitorPriceUpdatesCallbackRequestSchema,
+  MonitorNewFlightsCallbackRequest,
+  MonitorNewFlightsCallbackRequestSchema,
+  MonitorFlightStatusCallbackRequest,
+  MonitorFlightStatusCallbackRequestSchema,
+  GetUpdatesSinceRequest,
+  GetUpdatesSinceRequestSchema,
+  UnsubscribeRequest,
+  UnsubscribeRequestSchema,
+  RenewSubscriptionRequest,
+  RenewSubscriptionRequestSchema
+} from './dto';
+
+const ip = process.env.IP || 'localhost';
+
+// sendRequest sends the payload in a single byte array buffer, callbacks are listened to for monitorTimeOut seconds
+function sendRequest(
+  requestType: RequestType,
+  payload: Buffer,
+  monitorTimeOut?: number
+) {
+  const client = new UDPClient(ip, 8080);
+  if (monitorTimeOut !== undefined) {
+    client.monitorTimeOut = monitorTimeOut;
+  }
+  return client.sendRequests({
+    payload: payload,
+    requestType: requestType,
+    byteArrayBufferNo: 1,
+    totalByteArrayBuffers: 1
+  });
+}
+
+// Ping tests connectivity to the server
+export function ping() {
+  return sendRequest(RequestType.PingRequestType, Buffer.alloc(0));
+}
+
+// GetFlightIdentifiers gets all flight identifiers for a source and destination location
+export function getFlightIdentifiers(req: GetFlightIdentifiersRequest) {
+  return sendRequest(
+    RequestType.GetFlightIdentifiersRequestType,
+    marshalMessage(GetFlightIdentifiersRequestSchema, req)
+  );
+}
+
+// GetFlightInformation gets Airfare, DepartureTime and TotalAvailableSeats of a flight
+export function getFlightInformation(req: GetFlightInformationRequest) {
+  return sendRequest(
+    RequestType.GetFlightInformationRequestType,
+    marshalMessage(GetFlightInformationRequestSchema, req)
+  );
+}
+
+// MakeSeatReservation makes a reservation for a flight identifier and returns the booking identifier of the reservation
+export function makeSeatReservation(req: MakeSeatReservationRequest) {
+  return sendRequest(
+    RequestType.MakeSeatReservationRequestType,
+    marshalMessage(MakeSeatReservationRequestSchema, req)
+  );
+}
+
+// MonitorSeatUpdates subscribes to changes in seats of a flight for the interval requested. onUpdate is called from the
+// client's read goroutine for every callback received until the interval expires or the subscription is cancelled with
+// Unsubscribe, the subscription identifier is returned for that.
+export function monitorSeatUpdates(req: MonitorSeatUpdatesCallbackRequest) {
+  return sendRequest(
+    RequestType.MonitorSeatUpdatesRequestType,
+    marshalMessage(MonitorSeatUpdatesCallbackRequestSchema, req),
+    Number(req.LengthOfMonitorIntervalInSeconds)
+  );
+}
+
+// UpdateFlightPrice updates the airfare of a flight and returns the updated flight
+export function updateFlightPrice(req: UpdateFlightPriceRequest) {
+  return sendRequest(
+    RequestType.UpdateFlightPriceRequestType,
+    marshalMessage(UpdateFlightPriceRequestSchema, req)
+  );
+}
+
+// CreateFlight creates a flight and returns the flight identifier of the new flight
+export function createFlight(req: CreateFlightRequest) {
+  return sendRequest(
+    RequestType.CreateFlightRequestType,
+    marshalMessage(CreateFlightRequestSchema, req)
+  );
+}
+
+// CancelReservation cancels a reservation and restores the seats reserved to the flight
+export function cancelReservation(req: CancelReservationRequest) {
+  return sendRequest(
+    RequestType.CancelReservationRequestType,
+    marshalMessage(CancelReservationRequestSchema, req)
+  );
+}
+
+// GetReservation gets the details of a reservation based on its booking identifier
+export function getReservation(req: GetReservationRequest) {
+  return sendRequest(
+    RequestType.GetReservationRequestType,
+    marshalMessage(GetReservationRequestSchema, req)
+  );
+}
+
+// GetSeatMap gets every seat of a flight with its cabin class and whether it is free, held or booked
+export function getSeatMap(req: GetSeatMapRequest) {
+  return sendRequest(
+    RequestType.GetSeatMapRequestType,
+    marshalMessage(GetSeatMapRequestSchema, req)
+  );
+}
+
+// ReserveSpecificSeats books all the seats requested or none of them. If any seat is unavailable, a
+// *custom_errors.SeatsUnavailableError listing the unavailable seats is returned.
+export function reserveSpecificSeats(req: ReserveSpecificSeatsRequest) {
+  return sendRequest(
+    RequestType.ReserveSpecificSeatsRequestType,
+    marshalMessage(ReserveSpecificSeatsRequestSchema, req)
+  );
+}
+
+// HoldSeats holds seats on a flight for HoldDurationInSeconds. The seats are booked only once the hold token returned is
+// confirmed with ConfirmHold, else they are released when the hold expires.
+export function holdSeats(req: HoldSeatsRequest) {
+  return sendRequest(
+    RequestType.HoldSeatsRequestType,
+    marshalMessage(HoldSeatsRequestSchema, req)
+  );
+}
+
+// ConfirmHold books the seats of a hold under a new reservation, *custom_errors.NoSuchHoldTokenError is returned if the
+// hold has expired or was made by another client.
+export function confirmHold(req: ConfirmHoldRequest) {
+  return sendRequest(
+    RequestType.ConfirmHoldRequestType,
+    marshalMessage(ConfirmHoldRequestSchema, req)
+  );
+}
+
+// SearchFlights gets a page of flights matching the filters requested, see dto.SearchFlightsRequest for the defaults
+export function searchFlights(req: SearchFlightsRequest) {
+  return sendRequest(
+    RequestType.SearchFlightsRequestType,
+    marshalMessage(SearchFlightsRequestSchema, req)
+  );
+}
+
+// FindItineraries finds routes from source to destination with connections, see dto.FindItinerariesRequest for the defaults
+export function findItineraries(req: FindItinerariesRequest) {
+  return sendRequest(
+    RequestType.FindItinerariesRequestType,
+    marshalMessage(FindItinerariesRequestSchema, req)
+  );
+}
+
+// CreateSchedule creates a recurring flight and returns the flight identifier of every flight of the schedule
+export function createSchedule(req: CreateScheduleRequest) {
+  return sendRequest(
+    RequestType.CreateScheduleRequestType,
+    marshalMessage(CreateScheduleRequestSchema, req)
+  );
+}
+
+// UpdateSchedule updates a schedule along with its future flights that have not been booked
+export function updateSchedule(req: UpdateScheduleRequest) {
+  return sendRequest(
+    RequestType.UpdateScheduleRequestType,
+    marshalMessage(UpdateScheduleRequestSchema, req)
+  );
+}
+
+// CancelSchedule cancels a schedule along with its future flights that have not been booked
+export function cancelSchedule(req: CancelScheduleRequest) {
+  return sendRequest(
+    RequestType.CancelScheduleRequestType,
+    marshalMessage(CancelScheduleRequestSchema, req)
+  );
+}
+
+// UpdateFlightStatus updates the status of a flight and returns the updated flight
+export function updateFlightStatus(req: UpdateFlightStatusRequest) {
+  return sendRequest(
+    RequestType.UpdateFlightStatusRequestType,
+    marshalMessage(UpdateFlightStatusRequestSchema, req)
+  );
+}
+
+// CancelFlight cancels a flight, no more seats can be reserved or held on it
+export function cancelFlight(req: CancelFlightRequest) {
+  return sendRequest(
+    RequestType.CancelFlightRequestType,
+    marshalMessage(CancelFlightRequestSchema, req)
+  );
+}
+
+// MonitorPriceUpdates subscribes to changes in the airfare of a flight for the interval requested in the same way as MonitorSeatUpdates
+export function monitorPriceUpdates(req: MonitorPriceUpdatesCallbackRequest) {
+  return sendRequest(
+    RequestType.MonitorPriceUpdatesRequestType,
+    marshalMessage(MonitorPriceUpdatesCallbackRequestSchema, req),
+    Number(req.LengthOfMonitorIntervalInSeconds)
+  );
+}
+
+// MonitorNewFlights subscribes to flights created on a route for the interval requested in the same way as MonitorSeatUpdates
+export function monitorNewFlights(req: MonitorNewFlightsCallbackRequest) {
+  return sendRequest(
+    RequestType.MonitorNewFlightsRequestType,
+    marshalMessage(MonitorNewFlightsCallbackRequestSchema, req),
+    Number(req.LengthOfMonitorIntervalInSeconds)
+  );
+}
+
+// MonitorFlightStatus subscribes to changes in the status of a flight, e.g. delays and cancellations, for the interval
+// requested in the same way as MonitorSeatUpdates
+export function monitorFlightStatus(req: MonitorFlightStatusCallbackRequest) {
+  return sendRequest(
+    RequestType.MonitorFlightStatusRequestType,
+    marshalMessage(MonitorFlightStatusCallbackRequestSchema, req),
+    Number(req.LengthOfMonitorIntervalInSeconds)
+  );
+}
+
+// GetUpdatesSince gets the changes to a flight after the sequence number of the last callback received for it. Callbacks
+// carry a sequence number per flight, a gap in them means some were missed and should be fetched with this.
+export function getUpdatesSince(req: GetUpdatesSinceRequest) {
+  return sendRequest(
+    RequestType.GetUpdatesSinceRequestType,
+    marshalMessage(GetUpdatesSinceRequestSchema, req)
+  );
+}
+
+// Unsubscribe stops the callbacks of a subscription before it expires
+export function unsubscribe(req: UnsubscribeRequest) {
+  return sendRequest(
+    RequestType.UnsubscribeRequestType,
+    marshalMessage(UnsubscribeRequestSchema, req)
+  );
+}
+
+// RenewSubscription makes a subscription expire after the interval requested from now instead
+export function renewSubscription(req: RenewSubscriptionRequest) {
+  return sendRequest(
+    RequestType.RenewSubscriptionRequestType,
+    marshalMessage(RenewSubscriptionRequestSchema, req)
+  );
+}
+
+// ListMySubscriptions lists the subscriptions of this client on the server that have not expired
+export function listMySubscriptions() {
+  return sendRequest(RequestType.ListMySubscriptionsRequestType, Buffer.alloc(0));
+}
@@ -27,7 +27,8 @@ export enum RequestType {
   MakeSeatReservationRequestType = 4,
   MonitorSeatUpdatesRequestType = 5,
   UpdateFlightPriceRequestType = 6,
-  CreateFlightRequestType = 7
+  CreateFlightRequestType = 7,
+  AckCallbackRequestType = 24
 }
 
 export enum ResponseType {
@@ -38,7 +39,8 @@ export enum ResponseType {
   MonitorSeatUpdatesResponseType = 105,
   UpdateFlightPriceResponseType = 106,
   CreateFlightResponseType = 107,
-  MonitorSeatUpdatesCallbackType = 201
+  MonitorSeatUpdatesCallbackType = 201,
+  MonitorFlightStatusCallbackType = 202
 }
 
 export type GetFlightIdentifiersRequest = {
@@ -74,6 +76,13 @@ export type MonitorSeatUpdatesCallbackResponse = {
   TotalAvailableSeats: number;
 };
 
+export type MonitorFlightStatusCallbackResponse = {
+  FlightIdentifier: number;
+  Status: number;
+  DepartureTime: bigint;
+  ArrivalTime: bigint;
+};
+
 export type UpdateFlightPriceRequest = {
   FlightIdentifier: number;
   NewPrice: number;
@@ -94,6 +103,7 @@ export type CreateFlightRequest = {
   DepartureTime: bigint;
   Airfare: string;
   TotalAvailableSeats: number;
+  ArrivalTime: bigint;
 };
 
 export type CreateFlightResponse = {
@@ -44,3 +44,56 @@ export function marshal(data: any): any {
   let requestBuffer = toByteArray(data);
   return requestBuffer;
 }
+
+// FieldType is the wire type of a field in a MessageSchema, as generated from the IDL of the server.
+export type FieldType =
+  | 'uint8'
+  | 'int32'
+  | 'int64'
+  | 'float64'
+  | 'string'
+  | { array: FieldType }
+  | { message: MessageSchema };
+
+// MessageSchema lists the fields of a message in the order the server declares them.
+export type MessageSchema = [string, FieldType][];
+
+// Marshal a field by its wire type instead of guessing it from the value, undefined is marshalled as the zero value.
+function fieldToByteArray(type: FieldType, value: any): Buffer {
+  let buffer;
+
+  if (typeof type === 'object' && 'array' in type) {
+    const elements: any[] = value ?? [];
+    buffer = Buffer.alloc(8);
+    buffer.writeBigInt64LE(BigInt(elements.length));
+    const bytes = elements.map((e) => fieldToByteArray(type.array, e));
+    return Buffer.concat([buffer, ...bytes]);
+  } else if (typeof type === 'object') {
+    return marshalMessage(type.message, value ?? {});
+  }
+
+  switch (type) {
+    case 'uint8':
+      return Buffer.from([Number(value ?? 0)]);
+    case 'int32':
+      buffer = Buffer.alloc(4);
+      buffer.writeInt32LE(Number(value ?? 0));
+      return buffer;
+    case 'int64':
+      buffer = Buffer.alloc(8);
+      buffer.writeBigInt64LE(BigInt(value ?? 0));
+      return buffer;
+    case 'float64':
+      buffer = Buffer.alloc(8);
+      buffer.writeDoubleLE(Number(value ?? 0));
+      return buffer;
+    case 'string':
+      return Buffer.from((value ?? '') + '\0');
+  }
+}
+
+// Marshal data with the fields of the schema, in the positional format of the server.
+export function marshalMessage(schema: MessageSchema, data: any): Buffer {
+  const bytes = schema.map(([name, type]) => fieldToByteArray(type, data[name]));
+  return Buffer.concat(bytes);
+}
@@ -22,6 +22,14 @@ export function unmarshal(buffer: Buffer, requestType: number) {
   }
 }
 
+// Flight statuses by the value of dao.FlightStatusType on the server
+const FLIGHT_STATUSES: Record<number, string> = {
+  1: 'Scheduled',
+  2: 'Delayed',
+  3: 'Cancelled',
+  4: 'Departed'
+};
+
 // Based on the response type that is part of the response header
 function determineResponseType(buffer: Buffer, responseType: number) {
   let totalAvailableSeats: number, airfare: string, flightIdentifier: number;
@@ -82,6 +90,16 @@ function determineResponseType(buffer: Buffer, responseType: number) {
       );
       break;
 
+    case ResponseType.MonitorFlightStatusCallbackType:
+      flightIdentifier = buffer.readInt32LE();
+      const flightStatus = FLIGHT_STATUSES[buffer.readUInt8(4)] ?? 'Unknown';
+      departureTime = convertToDateTime(buffer.readBigInt64LE(5));
+      const arrivalTime = convertToDateTime(buffer.readBigInt64LE(13));
+      console.log(
+        `Flight Identifier ${flightIdentifier} is now ${flightStatus}, departing at ${departureTime} and arriving at ${arrivalTime}.`
+      );
+      break;
+
     case ResponseType.UpdateFlightPriceResponseType:
       flightIdentifier = buffer.readInt32LE();
       let curLen = 4;
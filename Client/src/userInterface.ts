@@ -152,6 +152,9 @@ async function q6() {
   const totalAvailableSeats = Number(
     await rl.question("Input the Total Available Seats of your Flight\n")
   );
+  const arrivalTime = Number(
+    await rl.question("Input your Arrival Time\n")
+  );
 
   const dto = {
     SourceLocation: sourceLocation,
@@ -159,6 +162,7 @@ async function q6() {
     DepartureTime: BigInt(departureTime),
     Airfare: airfare,
     TotalAvailableSeats: totalAvailableSeats,
+    ArrivalTime: BigInt(arrivalTime),
   };
 
   await createFlightRequest(dto);
@@ -179,6 +183,9 @@ async function q7() {
   const totalAvailableSeats = Number(
     await rl.question("Input the Total Available Seats of your Flight\n")
   );
+  const arrivalTime = Number(
+    await rl.question("Input your Arrival Time\n")
+  );
 
   const dto = {
     SourceLocation: sourceLocation,
@@ -186,6 +193,7 @@ async function q7() {
     DepartureTime: BigInt(departureTime),
     Airfare: airfare,
     TotalAvailableSeats: totalAvailableSeats,
+    ArrivalTime: BigInt(arrivalTime),
   };
 
   await createFlightWithRequestLost(dto);
@@ -206,6 +214,9 @@ async function q8() {
   const totalAvailableSeats = Number(
     await rl.question("Input the Total Available Seats of your Flight\n")
   );
+  const arrivalTime = Number(
+    await rl.question("Input your Arrival Time\n")
+  );
 
   const dto = {
     SourceLocation: sourceLocation,
@@ -213,6 +224,7 @@ async function q8() {
     DepartureTime: BigInt(departureTime),
     Airfare: airfare,
     TotalAvailableSeats: totalAvailableSeats,
+    ArrivalTime: BigInt(arrivalTime),
   };
 
   await createFlightWithResponseLost(dto);
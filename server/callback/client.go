@@ -2,85 +2,156 @@ package callback
 
 import (
 	"context"
+	"math/rand"
+	"sync"
 	"time"
 
 	"github.com/cyiafn/flight_information_system/server/dto"
 	"github.com/cyiafn/flight_information_system/server/dto/status_code"
 	"github.com/cyiafn/flight_information_system/server/logs"
-	"github.com/cyiafn/flight_information_system/server/net"
 	"github.com/cyiafn/flight_information_system/server/server"
 	"github.com/cyiafn/flight_information_system/server/utils"
-	"github.com/cyiafn/flight_information_system/server/utils/bytes"
 	"github.com/cyiafn/flight_information_system/server/utils/collections"
 	"github.com/cyiafn/flight_information_system/server/utils/predicates"
 	"github.com/cyiafn/flight_information_system/server/utils/rpc"
 	"github.com/cyiafn/flight_information_system/server/utils/worker_pools"
-	"github.com/teris-io/shortid"
 )
 
 // Client is a callback client designed to handle generic subscribers and notifying of those subscribers.
+// This is CONCURRENT-SAFE
 type Client[T comparable] struct {
-	// NotifiableClients are the set of IP:Port addresses for each item subscribed.
+	sync.RWMutex
+	// NotifiableClients are the set of IP:Port addresses for each item subscribed, items with no subscribers left are deleted.
 	NotifiableClients map[T]*collections.Set[string]
+	// CallbackType is the type of the callbacks subscribers are notified with
+	CallbackType dto.ResponseType
+	// subscriptionIdentifiers are the subscription identifiers of each subscriber of each item
+	subscriptionIdentifiers map[subscriber[T]]int64
+	// schemaVersions are the schema versions each subscriber subscribed in, which it is notified in
+	schemaVersions map[subscriber[T]]rpc.SchemaVersion
+}
+
+// subscriber is a subscriber (IP:Port) of an item
+type subscriber[T comparable] struct {
+	Item T
+	Addr string
 }
 
 // NewClient is an instantiate for the Client.
-func NewClient[T comparable]() *Client[T] {
+func NewClient[T comparable](callbackType dto.ResponseType) *Client[T] {
 	return &Client[T]{
-		NotifiableClients: make(map[T]*collections.Set[string]),
+		NotifiableClients:       make(map[T]*collections.Set[string]),
+		CallbackType:            callbackType,
+		subscriptionIdentifiers: make(map[subscriber[T]]int64),
+		schemaVersions:          make(map[subscriber[T]]rpc.SchemaVersion),
 	}
 }
 
+const (
+	// requestIDLength is the fixed length of the requestID in the header of a callback
+	requestIDLength = 9
+	// requestIDAlphabet are the characters used in requestIDs, the same as the clients
+	requestIDAlphabet = "1234567890abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+)
+
 // workerPoolJob is a request object designed to store the necessary details for the job.
 type workerPoolJob struct {
-	// Payload to deliver to subscriber
-	Payload []byte
+	// Payload to deliver to subscriber, split into byte array buffers
+	Payload [][]byte
 	// IP:Port of subscriber
 	Addr string
 }
 
-// Subscribe subscribes a client to be notified on change of a particular item with an expiry duration defined.
+// Subscribe subscribes a client to be notified on change of a particular item with an expiry duration defined, returns the
+// subscription identifier. Subscribing again to the same item renews the existing subscription instead.
 // note that IP addresses are propagated through the program in the context object.
-func (c *Client[T]) Subscribe(ctx context.Context, item T, expireDuration time.Duration) {
+func (c *Client[T]) Subscribe(ctx context.Context, item T, expireDuration time.Duration) int64 {
 	// gets the IP address from the ctx
 	addr := server.GetIPAddr(ctx)
+	key := subscriber[T]{Item: item, Addr: addr}
+
+	c.Lock()
+	defer c.Unlock()
+	c.schemaVersions[key] = server.GetSchemaVersion(ctx)
+
+	// the subscription may have expired just before it is renewed, in which case we subscribe again
+	if subscriptionIdentifier, ok := c.subscriptionIdentifiers[key]; ok {
+		if _, err := subscriptions.Renew(addr, subscriptionIdentifier, expireDuration); err == nil {
+			logs.Info("Client: %s has renewed subscription %v to item: %s", addr, subscriptionIdentifier, utils.DumpJSON(item))
+			return subscriptionIdentifier
+		}
+	}
 
 	// If the item to subscribe to doesn't exist yet, we need to allocate memory for a new set at that key.
 	if _, ok := c.NotifiableClients[item]; !ok {
 		c.NotifiableClients[item] = collections.NewSet[string]()
 	}
 
-	// Adds the client to that set to be subscribed. We don't care if it replaces.
+	// Adds the client to that set to be subscribed.
 	c.NotifiableClients[item].MustAdd(addr)
-	logs.Info("Client: %s has successfully been subscribed to item: %s", addr, utils.DumpJSON(item))
-	// Runs a go routine that removes that user from the subscription list once they have expired.
-	// Note that we can do this as goroutines are extremely cheap and only take up a minimal amount of memory.
-	// A good optimization will be to continue to store this data, and only clean it up after 5 minutes or so, with a condition
-	// that notify will not deliver changes to that particular user if already expired.
-	go c.cleanup(item, addr, expireDuration)
+	// The subscription removes the user from the subscription list once they have expired or unsubscribed. It may expire
+	// before Add returns, so the registry passes in the subscription identifier.
+	sub := subscriptions.Add(c.CallbackType, addr, utils.DumpJSON(item), expireDuration, func(subscriptionIdentifier int64) {
+		logs.Info("removing address: %s for item: %s from subscription", addr, utils.DumpJSON(item))
+		c.remove(key, subscriptionIdentifier)
+	})
+	c.subscriptionIdentifiers[key] = sub.SubscriptionIdentifier
+	logs.Info("Client: %s has successfully been subscribed to item: %s with subscription %v", addr, utils.DumpJSON(item), sub.SubscriptionIdentifier)
+	return sub.SubscriptionIdentifier
 }
 
-// cleanup simply blocks and removes the users from the subscription once their subscription duration ends.
-func (c *Client[T]) cleanup(item T, addr string, expireDuration time.Duration) {
-	// timer
-	timer := time.NewTimer(expireDuration)
+// remove removes the subscriber from the subscription of the item, unless the subscriber has subscribed again since
+func (c *Client[T]) remove(key subscriber[T], subscriptionIdentifier int64) {
+	c.Lock()
+	defer c.Unlock()
+	if c.subscriptionIdentifiers[key] != subscriptionIdentifier {
+		return
+	}
+	delete(c.subscriptionIdentifiers, key)
+	delete(c.schemaVersions, key)
+	subscribers := c.NotifiableClients[key.Item]
+	subscribers.MustRemove(key.Addr)
+	if subscribers.Len() == 0 {
+		delete(c.NotifiableClients, key.Item)
+	}
+}
 
-	// on timer finish
-	<-timer.C
-	logs.Info("removing address: %s for item: %s from subscription", addr, utils.DumpJSON(item))
-	// removes person from subscription
-	c.NotifiableClients[item].MustRemove(addr)
+// drop removes a subscriber that did not ack a callback along with its subscription
+func (c *Client[T]) drop(key subscriber[T]) {
+	c.RLock()
+	subscriptionIdentifier, ok := c.subscriptionIdentifiers[key]
+	c.RUnlock()
+	if !ok {
+		return
+	}
+	subscriptions.Remove(subscriptionIdentifier)
+	c.remove(key, subscriptionIdentifier)
+}
+
+// Len is the number of subscribers across every item
+func (c *Client[T]) Len() int {
+	c.RLock()
+	defer c.RUnlock()
+	return len(c.subscriptionIdentifiers)
 }
 
 // Notify notifies all subscribers for that particular item
 func (c *Client[T]) Notify(item T, respType dto.ResponseType, payload any, err error) error {
-	// if that item does not exist in the map, we don't do anything
-	if _, ok := c.NotifiableClients[item]; !ok {
-		return nil
+	// the subscribers are copied so that subscriptions can change while the callbacks are sent
+	c.RLock()
+	clients, ok := c.NotifiableClients[item]
+	var addrs []string
+	if ok {
+		addrs = clients.ToList()
+	}
+	versions := make(map[string]rpc.SchemaVersion, len(addrs))
+	for _, addr := range addrs {
+		versions[addr] = c.schemaVersions[subscriber[T]{Item: item, Addr: addr}]
 	}
+	c.RUnlock()
 
 	// if there is no clients for that item, we don't do anything
-	if clients := c.NotifiableClients[item]; clients.Len() == 0 {
+	if len(addrs) == 0 {
 		logs.Info("no client to notify")
 		return nil
 	}
@@ -88,25 +159,27 @@ func (c *Client[T]) Notify(item T, respType dto.ResponseType, payload any, err e
 	// wrap it in the default response wrapper
 	wrappedResp := &dto.Response{StatusCode: status_code.GetStatusCode(err), Data: payload}
 
-	// marshal response into bytes
-	respBody, err := rpc.Marshal(wrappedResp)
-	if err != nil {
-		logs.Warn("unable to marshal payload for callback, err: %v", err)
-		respBody, _ = rpc.Marshal(&dto.Response{
-			StatusCode: status_code.Success,
-			Data:       nil,
-		})
+	// the payload is split into byte array buffers with headers the same way responses are, so callbacks can be of any size.
+	// The same requestID is used for every subscriber, each subscriber acks it separately.
+	requestID := newCallbackRequestID()
+	// subscribers are notified in the schema version they subscribed in, so the payload is marshalled once per version
+	fullPayloads := make(map[rpc.SchemaVersion][][]byte)
+	for _, version := range versions {
+		if _, ok := fullPayloads[version]; ok {
+			continue
+		}
+		fullPayloads[version] = marshalCallback(wrappedResp, respType, version, requestID)
+		logs.Info("Response Callback in schema version %v: %s, in %v byte array buffers, sending to: %v", version, utils.DumpJSON(wrappedResp), len(fullPayloads[version]), addrs)
 	}
 
-	// add the headers to the payload, over here, as per our use case, we assume that it will fit in a 512 buffer.
-	fullPayload := c.addHeaders(respType, respBody)
-	logs.Info("Response Callback: %v, sending to: %v", fullPayload, c.NotifiableClients[item].ToList())
-
-	// We spawn max of 10 workers (limit resource usage) for a worker pool pattern to concurrently send the callback to users
+	// We spawn max of 10 workers (limit resource usage) for a worker pool pattern to concurrently send the callback to users.
+	// Callbacks are retransmitted until they are acked, subscribers that never ack are removed from the subscription.
 	load := worker_pools.Load(func(job workerPoolJob) error {
-		return net.SendData(job.Payload, job.Addr)
+		return deliveries.Deliver(requestID, job.Addr, job.Payload, func() {
+			c.drop(subscriber[T]{Item: item, Addr: job.Addr})
+		})
 	},
-		makeWorkerPoolJobs(c.NotifiableClients[item].ToList(), fullPayload),
+		makeWorkerPoolJobs(addrs, versions, fullPayloads),
 		10,
 	)
 
@@ -123,46 +196,37 @@ func (c *Client[T]) Notify(item T, respType dto.ResponseType, payload any, err e
 	return nil
 }
 
-// makeWorkerPoolJobs simply creates the worker pool jobs
-func makeWorkerPoolJobs(addrs []string, payload []byte) []workerPoolJob {
+// marshalCallback marshals the callback in the schema version and splits it into byte array buffers
+func marshalCallback(wrappedResp *dto.Response, respType dto.ResponseType, version rpc.SchemaVersion, requestID string) [][]byte {
+	respBody, err := rpc.MarshalSchema(wrappedResp, version)
+	if err != nil {
+		logs.Warn("unable to marshal payload for callback, err: %v", err)
+		respBody, _ = rpc.MarshalSchema(&dto.Response{
+			StatusCode: status_code.Success,
+			Data:       nil,
+		}, version)
+	}
+	return server.SplitPayload(respType, version, []byte(requestID), respBody)
+}
+
+// makeWorkerPoolJobs simply creates the worker pool jobs, with the payload in the schema version of each subscriber
+func makeWorkerPoolJobs(addrs []string, versions map[string]rpc.SchemaVersion, payloads map[rpc.SchemaVersion][][]byte) []workerPoolJob {
 	jobs := make([]workerPoolJob, len(addrs))
 	for i, v := range addrs {
 		jobs[i] = workerPoolJob{
-			Payload: payload,
+			Payload: payloads[versions[v]],
 			Addr:    v,
 		}
 	}
 	return jobs
 }
 
-// addHeaders adds the responseType, requestID, byteBufferArrayNo and totalByteBufferArray to header. For the sake of simplicity, we assumed
-// that all callbacks will only use max of 1 byte array buffer (512 bytes - headers)
-func (c *Client[T]) addHeaders(respType dto.ResponseType, body []byte) []byte {
-	body = c.addTotalByteBufferArrayToHeader(body, 1)
-	body = c.addByteBufferArrayNoToHeader(body, 1)
-	body = c.addRequestID(body)
-	body = c.addResponseTypeHeader(respType, body)
-	return body
-}
-
-// addByteBufferArrayNoToHeader appends the byteBufferArray number to the header
-func (c *Client[T]) addByteBufferArrayNoToHeader(response []byte, byteBufferArrayNo int64) []byte {
-	return append(bytes.Int64ToBytes(byteBufferArrayNo), response...)
-}
-
-// addTotalByteBufferArrayToHeader appends the total byteBufferArray number to the header
-func (c *Client[T]) addTotalByteBufferArrayToHeader(response []byte, totalByteBufferArrays int64) []byte {
-	return append(bytes.Int64ToBytes(totalByteBufferArrays), response...)
-}
-
-// addResponseTypeHeader appends the responseType to the header
-func (c *Client[T]) addResponseTypeHeader(respType dto.ResponseType, body []byte) []byte {
-	return append([]byte{uint8(respType)}, body...)
-}
-
-// addRequestID appends the requestID to the header
-func (c *Client[T]) addRequestID(body []byte) []byte {
-	// this generates a random 9 character string guaranteed for uniqueness until 2050.
-	reqID := shortid.MustGenerate()
-	return append([]byte(reqID), body...)
+// newCallbackRequestID generates a random requestID of a callback, which subscribers ack and use to discard
+// retransmissions. Note that shortid cannot be used here as the IDs it generates are not always 9 characters long.
+func newCallbackRequestID() string {
+	requestID := make([]byte, requestIDLength)
+	for i := range requestID {
+		requestID[i] = requestIDAlphabet[rand.Intn(len(requestIDAlphabet))]
+	}
+	return string(requestID)
 }
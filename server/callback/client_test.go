@@ -0,0 +1,17 @@
+package callback
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCallbackRequestID(t *testing.T) {
+	requestIDs := make(map[string]bool)
+	for i := 0; i < 10000; i++ {
+		requestID := newCallbackRequestID()
+		assert.Len(t, requestID, requestIDLength)
+		assert.False(t, requestIDs[requestID], "duplicate requestID: %s", requestID)
+		requestIDs[requestID] = true
+	}
+}
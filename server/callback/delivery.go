@@ -0,0 +1,150 @@
+package callback
+
+import (
+	"sync"
+	"time"
+
+	"github.com/cyiafn/flight_information_system/server/logs"
+	"github.com/cyiafn/flight_information_system/server/server"
+)
+
+/**
+Callbacks are sent over UDP, so they may be lost on the way to the subscriber. Every callback sent is kept in an
+outstanding-message table keyed by subscriber and callback requestID until the subscriber acks it. If no ack arrives before
+the retransmission timeout, the exact same payload (with the same requestID) is sent again so that the subscriber can tell
+it is a retransmission. A subscriber that has not acked after the maximum number of retransmissions is assumed to be gone
+and is dropped.
+*/
+
+const (
+	// defaultRetransmissionTimeout is how long we wait for an ack before retransmitting a callback
+	defaultRetransmissionTimeout = 1 * time.Second
+	// defaultMaxRetransmissions is the number of retransmissions before dropping the subscriber
+	defaultMaxRetransmissions = 3
+)
+
+// deliveries is the outstanding-message table shared by every callback client, as acks do not carry the callback type
+var deliveries *deliveryTracker
+
+func init() {
+	// the sender is looked up on every send as the server only decides it on boot
+	deliveries = newDeliveryTracker(func(payload []byte, addr string) error {
+		return server.GetCallbackSender().SendTo(payload, addr)
+	}, defaultRetransmissionTimeout, defaultMaxRetransmissions)
+}
+
+// Ack acknowledges the callback with the requestID sent to the subscriber at addr (IP:Port), returns false if the callback
+// is not outstanding, e.g. it has already been acked
+func Ack(addr string, requestID string) bool {
+	return deliveries.Ack(addr, requestID)
+}
+
+// deliveryKey identifies a callback sent to a subscriber, the same callback is sent to every subscriber of an item
+type deliveryKey struct {
+	Addr      string
+	RequestID string
+}
+
+// outstandingMessage is a callback that has not been acked by the subscriber yet
+type outstandingMessage struct {
+	// Payload are the byte array buffers of the callback, each a full datagram including the headers
+	Payload [][]byte
+	// Retransmissions is the number of times the payload has been sent again
+	Retransmissions int
+	// OnDrop is called if the subscriber never acks
+	OnDrop func()
+	// timer fires when the retransmission timeout is up
+	timer *time.Timer
+}
+
+// deliveryTracker keeps track of callbacks until they are acked, retransmitting them on timeout.
+// This is CONCURRENT-SAFE
+type deliveryTracker struct {
+	sync.Mutex
+	// outstanding are the callbacks not acked yet
+	outstanding map[deliveryKey]*outstandingMessage
+	// send sends a payload to an IP:Port
+	send                  func(payload []byte, addr string) error
+	retransmissionTimeout time.Duration
+	maxRetransmissions    int
+}
+
+// newDeliveryTracker instantiates an empty deliveryTracker
+func newDeliveryTracker(send func(payload []byte, addr string) error, retransmissionTimeout time.Duration, maxRetransmissions int) *deliveryTracker {
+	return &deliveryTracker{
+		outstanding:           make(map[deliveryKey]*outstandingMessage),
+		send:                  send,
+		retransmissionTimeout: retransmissionTimeout,
+		maxRetransmissions:    maxRetransmissions,
+	}
+}
+
+// Deliver sends the callback to the subscriber and retransmits it until it is acked. onDrop is called if the subscriber
+// never acks. The error returned is that of the first transmission only, the callback is retransmitted regardless.
+func (d *deliveryTracker) Deliver(requestID string, addr string, payload [][]byte, onDrop func()) error {
+	key := deliveryKey{Addr: addr, RequestID: requestID}
+	msg := &outstandingMessage{Payload: payload, OnDrop: onDrop}
+
+	d.Lock()
+	d.outstanding[key] = msg
+	// the timer is started before sending so that an ack cannot arrive before the message is outstanding
+	msg.timer = time.AfterFunc(d.retransmissionTimeout, func() { d.retransmit(key) })
+	d.Unlock()
+
+	return d.sendAll(payload, addr)
+}
+
+// sendAll sends every byte array buffer of a callback, returns the first error. The subscriber can only reassemble and ack
+// the callback once all of them arrive, so every byte array buffer is sent again on retransmission.
+func (d *deliveryTracker) sendAll(payload [][]byte, addr string) error {
+	var firstErr error
+	for _, buf := range payload {
+		if err := d.send(buf, addr); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Ack removes the callback from the outstanding messages so that it is not retransmitted
+func (d *deliveryTracker) Ack(addr string, requestID string) bool {
+	d.Lock()
+	defer d.Unlock()
+	key := deliveryKey{Addr: addr, RequestID: requestID}
+	msg, ok := d.outstanding[key]
+	if !ok {
+		return false
+	}
+	msg.timer.Stop()
+	delete(d.outstanding, key)
+	return true
+}
+
+// retransmit sends the callback again if it has not been acked, or drops the subscriber if it has been retransmitted too many times
+func (d *deliveryTracker) retransmit(key deliveryKey) {
+	d.Lock()
+	msg, ok := d.outstanding[key]
+	if !ok {
+		// acked just as the timer fired
+		d.Unlock()
+		return
+	}
+	if msg.Retransmissions >= d.maxRetransmissions {
+		delete(d.outstanding, key)
+		d.Unlock()
+		logs.Warn("subscriber %s did not ack callback %s after %v retransmissions, dropping subscriber", key.Addr, key.RequestID, msg.Retransmissions)
+		if msg.OnDrop != nil {
+			msg.OnDrop()
+		}
+		return
+	}
+	msg.Retransmissions += 1
+	retransmissions := msg.Retransmissions
+	msg.timer.Reset(d.retransmissionTimeout)
+	d.Unlock()
+
+	logs.Info("no ack for callback %s from subscriber %s, retransmitting (retransmission %v of %v)", key.RequestID, key.Addr, retransmissions, d.maxRetransmissions)
+	if err := d.sendAll(msg.Payload, key.Addr); err != nil {
+		logs.Warn("unable to retransmit callback %s to subscriber %s, err: %v", key.RequestID, key.Addr, err)
+	}
+}
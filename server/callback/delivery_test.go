@@ -0,0 +1,122 @@
+package callback
+
+import (
+	"fmt"
+	"math/rand"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	fisnet "github.com/cyiafn/flight_information_system/server/net"
+	"github.com/stretchr/testify/assert"
+)
+
+// lossySubscriber is a local UDP stand-in for a subscriber on a lossy network. It drops dropRate of the callbacks it
+// receives and acks the rest, which the deliveryTracker would otherwise receive through the server's listener.
+type lossySubscriber struct {
+	sync.Mutex
+	conn *net.UDPConn
+	// received are the number of datagrams received of each callback, including those dropped
+	received map[string]int
+	// delivered are the callbacks that were not dropped
+	delivered map[string]bool
+}
+
+func startLossySubscriber(t *testing.T, tracker *deliveryTracker, dropRate float64, seed int64) *lossySubscriber {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.ParseIP("127.0.0.1")})
+	assert.Nil(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	s := &lossySubscriber{conn: conn, received: make(map[string]int), delivered: make(map[string]bool)}
+	random := rand.New(rand.NewSource(seed))
+	go func() {
+		for {
+			buf := make([]byte, fisnet.DefaultByteBufferSize)
+			n, err := conn.Read(buf)
+			if err != nil {
+				return
+			}
+			requestID := string(buf[1:10])
+			s.Lock()
+			s.received[requestID] += 1
+			dropped := random.Float64() < dropRate
+			if !dropped {
+				s.delivered[requestID] = true
+			}
+			s.Unlock()
+			if !dropped && n > 0 {
+				tracker.Ack(conn.LocalAddr().String(), requestID)
+			}
+		}
+	}()
+	return s
+}
+
+func (s *lossySubscriber) Addr() string {
+	return s.conn.LocalAddr().String()
+}
+
+// newTestPayload makes a callback of a single byte array buffer with the requestID in the header
+func newTestPayload(requestID string) [][]byte {
+	return [][]byte{append([]byte{201}, []byte(requestID)...)}
+}
+
+func TestDeliveryTrackerRetransmitsUntilAcked(t *testing.T) {
+	tracker := newDeliveryTracker(fisnet.SendData, 20*time.Millisecond, 20)
+	subscriber := startLossySubscriber(t, tracker, 0.5, 1)
+
+	var drops int32
+	for i := 0; i < 50; i++ {
+		requestID := fmt.Sprintf("%-9d", i)
+		assert.Nil(t, tracker.Deliver(requestID, subscriber.Addr(), newTestPayload(requestID), func() {
+			atomic.AddInt32(&drops, 1)
+		}))
+	}
+
+	assert.Eventually(t, func() bool {
+		tracker.Lock()
+		defer tracker.Unlock()
+		return len(tracker.outstanding) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, int32(0), atomic.LoadInt32(&drops))
+	subscriber.Lock()
+	defer subscriber.Unlock()
+	assert.Len(t, subscriber.delivered, 50)
+	retransmitted := 0
+	for _, received := range subscriber.received {
+		retransmitted += received - 1
+	}
+	// about half of the callbacks are dropped at least once
+	assert.Greater(t, retransmitted, 0)
+}
+
+func TestDeliveryTrackerDropsSubscriberThatNeverAcks(t *testing.T) {
+	tracker := newDeliveryTracker(fisnet.SendData, 10*time.Millisecond, 3)
+	subscriber := startLossySubscriber(t, tracker, 1, 1)
+
+	dropped := make(chan struct{})
+	assert.Nil(t, tracker.Deliver("abcdefghi", subscriber.Addr(), newTestPayload("abcdefghi"), func() {
+		close(dropped)
+	}))
+
+	select {
+	case <-dropped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("subscriber was not dropped")
+	}
+	// a late ack does nothing
+	assert.False(t, tracker.Ack(subscriber.Addr(), "abcdefghi"))
+
+	assert.Eventually(t, func() bool {
+		subscriber.Lock()
+		defer subscriber.Unlock()
+		// the first transmission and every retransmission
+		return subscriber.received["abcdefghi"] == 4
+	}, time.Second, 10*time.Millisecond)
+	tracker.Lock()
+	defer tracker.Unlock()
+	assert.Len(t, tracker.outstanding, 0)
+}
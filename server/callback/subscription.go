@@ -0,0 +1,271 @@
+package callback
+
+import (
+	"container/heap"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cyiafn/flight_information_system/server/custom_errors"
+	"github.com/cyiafn/flight_information_system/server/dto"
+	"github.com/cyiafn/flight_information_system/server/logs"
+)
+
+/**
+Every subscription of every callback client is registered with a subscription identifier, which is returned to the
+subscriber so that it can unsubscribe early, renew the subscription or list its subscriptions.
+Expiries are kept in a single min-heap by expiry time, served by one goroutine that sleeps until the earliest expiry, so
+the number of goroutines and timers does not grow with the number of subscriptions. Renewals reorder the heap in place, so
+an older expiry never removes a renewed subscription.
+*/
+
+// Subscription is a subscriber of an item of a callback client
+type Subscription struct {
+	SubscriptionIdentifier int64
+	// CallbackType is the type of the callbacks the subscriber is notified with
+	CallbackType dto.ResponseType
+	// Addr is the IP:Port of the subscriber
+	Addr string
+	// Item is the item subscribed to, in JSON
+	Item       string
+	ExpiryTime time.Time
+	// index is the index of the subscription in the expiry heap
+	index int
+	// remove removes the subscriber from the callback client, it is passed the subscription identifier as the callback
+	// client does not know it until Add returns
+	remove func(subscriptionIdentifier int64)
+}
+
+// subscriptions are the subscriptions of every callback client, as subscription identifiers are unique across them
+var subscriptions *subscriptionRegistry
+
+func init() {
+	subscriptions = newSubscriptionRegistry()
+}
+
+// Unsubscribe removes the subscription of the subscriber at addr (IP:Port) before it expires, returns
+// NoSuchSubscriptionIdentifierError if the subscriber does not have that subscription
+func Unsubscribe(addr string, subscriptionIdentifier int64) error {
+	return subscriptions.Cancel(addr, subscriptionIdentifier)
+}
+
+// RenewSubscription makes the subscription of the subscriber at addr (IP:Port) expire after expireDuration from now instead,
+// returns NoSuchSubscriptionIdentifierError if the subscriber does not have that subscription
+func RenewSubscription(addr string, subscriptionIdentifier int64, expireDuration time.Duration) (Subscription, error) {
+	return subscriptions.Renew(addr, subscriptionIdentifier, expireDuration)
+}
+
+// ListSubscriptions lists the subscriptions of the subscriber at addr (IP:Port) that have not expired
+func ListSubscriptions(addr string) []Subscription {
+	return subscriptions.List(addr)
+}
+
+// subscriptionRegistry keeps track of subscriptions until they expire or are cancelled.
+// This is CONCURRENT-SAFE
+type subscriptionRegistry struct {
+	sync.Mutex
+	subscriptions map[int64]*Subscription
+	// expiries are the subscriptions ordered by expiry time
+	expiries expiryHeap
+	// lastSubscriptionIdentifier is the identifier of the latest subscription, identifiers start from 1
+	lastSubscriptionIdentifier int64
+	// wake wakes the expiry goroutine when the earliest expiry may have changed
+	wake chan struct{}
+	// stop terminates the expiry goroutine
+	stop chan struct{}
+	// stopped is closed once the expiry goroutine has terminated
+	stopped chan struct{}
+}
+
+// newSubscriptionRegistry instantiates an empty subscriptionRegistry and starts its expiry goroutine
+func newSubscriptionRegistry() *subscriptionRegistry {
+	r := &subscriptionRegistry{
+		subscriptions: make(map[int64]*Subscription),
+		wake:          make(chan struct{}, 1),
+		stop:          make(chan struct{}),
+		stopped:       make(chan struct{}),
+	}
+	go r.runExpiries()
+	return r
+}
+
+// Stop terminates the expiry goroutine, subscriptions no longer expire after
+func (r *subscriptionRegistry) Stop() {
+	close(r.stop)
+	<-r.stopped
+}
+
+// Add registers a subscription that expires after expireDuration, remove is called once it expires or is cancelled
+func (r *subscriptionRegistry) Add(callbackType dto.ResponseType, addr string, item string, expireDuration time.Duration, remove func(subscriptionIdentifier int64)) Subscription {
+	r.Lock()
+	defer r.Unlock()
+	r.lastSubscriptionIdentifier += 1
+	sub := &Subscription{
+		SubscriptionIdentifier: r.lastSubscriptionIdentifier,
+		CallbackType:           callbackType,
+		Addr:                   addr,
+		Item:                   item,
+		ExpiryTime:             time.Now().Add(expireDuration),
+		remove:                 remove,
+	}
+	r.subscriptions[sub.SubscriptionIdentifier] = sub
+	heap.Push(&r.expiries, sub)
+	r.wakeExpiries(sub)
+	return *sub
+}
+
+// Renew makes the subscription expire after expireDuration from now instead
+func (r *subscriptionRegistry) Renew(addr string, subscriptionIdentifier int64, expireDuration time.Duration) (Subscription, error) {
+	r.Lock()
+	defer r.Unlock()
+	sub, ok := r.subscriptions[subscriptionIdentifier]
+	if !ok || sub.Addr != addr {
+		return Subscription{}, custom_errors.NewNoSuchSubscriptionIdentifierError()
+	}
+	sub.ExpiryTime = time.Now().Add(expireDuration)
+	heap.Fix(&r.expiries, sub.index)
+	r.wakeExpiries(sub)
+	return *sub, nil
+}
+
+// Cancel removes the subscription before it expires
+func (r *subscriptionRegistry) Cancel(addr string, subscriptionIdentifier int64) error {
+	r.Lock()
+	sub, ok := r.subscriptions[subscriptionIdentifier]
+	if !ok || sub.Addr != addr {
+		r.Unlock()
+		return custom_errors.NewNoSuchSubscriptionIdentifierError()
+	}
+	r.delete(sub)
+	r.Unlock()
+
+	logs.Info("subscriber %s has unsubscribed from subscription %v of item: %s", addr, subscriptionIdentifier, sub.Item)
+	sub.remove(sub.SubscriptionIdentifier)
+	return nil
+}
+
+// Remove removes the subscription without calling remove, for subscribers already removed from the callback client
+func (r *subscriptionRegistry) Remove(subscriptionIdentifier int64) {
+	r.Lock()
+	defer r.Unlock()
+	if sub, ok := r.subscriptions[subscriptionIdentifier]; ok {
+		r.delete(sub)
+	}
+}
+
+// List lists the subscriptions of the subscriber in the order they were subscribed
+func (r *subscriptionRegistry) List(addr string) []Subscription {
+	r.Lock()
+	defer r.Unlock()
+	res := make([]Subscription, 0)
+	for _, sub := range r.subscriptions {
+		if sub.Addr == addr {
+			res = append(res, *sub)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].SubscriptionIdentifier < res[j].SubscriptionIdentifier })
+	return res
+}
+
+// Len is the number of subscriptions that have not expired
+func (r *subscriptionRegistry) Len() int {
+	r.Lock()
+	defer r.Unlock()
+	return len(r.subscriptions)
+}
+
+// delete removes the subscription from the registry, the lock must be held
+func (r *subscriptionRegistry) delete(sub *Subscription) {
+	heap.Remove(&r.expiries, sub.index)
+	delete(r.subscriptions, sub.SubscriptionIdentifier)
+}
+
+// wakeExpiries wakes the expiry goroutine if the subscription is now the earliest to expire, the lock must be held.
+// Later expiries do not need to, as the goroutine only sleeps until the earliest one.
+func (r *subscriptionRegistry) wakeExpiries(sub *Subscription) {
+	if r.expiries[0] != sub {
+		return
+	}
+	select {
+	case r.wake <- struct{}{}:
+	default:
+		// a wake up is already pending
+	}
+}
+
+// runExpiries removes subscriptions as they expire, sleeping until the earliest expiry in between
+func (r *subscriptionRegistry) runExpiries() {
+	defer close(r.stopped)
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+	for {
+		expired, next := r.popExpired(time.Now())
+		for _, sub := range expired {
+			sub.remove(sub.SubscriptionIdentifier)
+		}
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		if next > 0 {
+			timer.Reset(next)
+		}
+		select {
+		case <-timer.C:
+		case <-r.wake:
+		case <-r.stop:
+			return
+		}
+	}
+}
+
+// popExpired removes the subscriptions that have expired by now, returns them along with the time until the next expiry,
+// or 0 if there are no subscriptions left
+func (r *subscriptionRegistry) popExpired(now time.Time) ([]*Subscription, time.Duration) {
+	r.Lock()
+	defer r.Unlock()
+	expired := make([]*Subscription, 0)
+	for len(r.expiries) > 0 && !r.expiries[0].ExpiryTime.After(now) {
+		sub := heap.Pop(&r.expiries).(*Subscription)
+		delete(r.subscriptions, sub.SubscriptionIdentifier)
+		expired = append(expired, sub)
+	}
+	if len(r.expiries) == 0 {
+		return expired, 0
+	}
+	return expired, r.expiries[0].ExpiryTime.Sub(now)
+}
+
+// expiryHeap is a min-heap of subscriptions by expiry time, it implements heap.Interface
+type expiryHeap []*Subscription
+
+func (h *expiryHeap) Len() int {
+	return len(*h)
+}
+
+func (h *expiryHeap) Less(i, j int) bool {
+	return (*h)[i].ExpiryTime.Before((*h)[j].ExpiryTime)
+}
+
+func (h *expiryHeap) Swap(i, j int) {
+	(*h)[i], (*h)[j] = (*h)[j], (*h)[i]
+	(*h)[i].index = i
+	(*h)[j].index = j
+}
+
+func (h *expiryHeap) Push(x any) {
+	sub := x.(*Subscription)
+	sub.index = len(*h)
+	*h = append(*h, sub)
+}
+
+func (h *expiryHeap) Pop() any {
+	old := *h
+	sub := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return sub
+}
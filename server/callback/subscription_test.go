@@ -0,0 +1,130 @@
+package callback
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cyiafn/flight_information_system/server/custom_errors"
+	"github.com/cyiafn/flight_information_system/server/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscriptionRegistry(t *testing.T) {
+	t.Run("renewed subscription is not removed by the older timer", func(t *testing.T) {
+		registry := newSubscriptionRegistry()
+		t.Cleanup(registry.Stop)
+		var removed int32
+		sub := registry.Add(dto.MonitorSeatUpdatesCallbackType, "127.0.0.1:1", "1", 30*time.Millisecond, func(int64) {
+			atomic.AddInt32(&removed, 1)
+		})
+
+		_, err := registry.Renew("127.0.0.1:1", sub.SubscriptionIdentifier, 200*time.Millisecond)
+		assert.Nil(t, err)
+		time.Sleep(80 * time.Millisecond)
+		assert.Equal(t, int32(0), atomic.LoadInt32(&removed))
+		assert.Len(t, registry.List("127.0.0.1:1"), 1)
+
+		assert.Eventually(t, func() bool { return atomic.LoadInt32(&removed) == 1 }, time.Second, 10*time.Millisecond)
+		assert.Len(t, registry.List("127.0.0.1:1"), 0)
+	})
+
+	t.Run("cancel removes the subscription once", func(t *testing.T) {
+		registry := newSubscriptionRegistry()
+		t.Cleanup(registry.Stop)
+		var removed int32
+		sub := registry.Add(dto.MonitorSeatUpdatesCallbackType, "127.0.0.1:1", "1", 30*time.Millisecond, func(int64) {
+			atomic.AddInt32(&removed, 1)
+		})
+
+		// only the subscriber can cancel its subscription
+		assert.IsType(t, &custom_errors.NoSuchSubscriptionIdentifierError{}, registry.Cancel("127.0.0.1:2", sub.SubscriptionIdentifier))
+		assert.Nil(t, registry.Cancel("127.0.0.1:1", sub.SubscriptionIdentifier))
+		assert.IsType(t, &custom_errors.NoSuchSubscriptionIdentifierError{}, registry.Cancel("127.0.0.1:1", sub.SubscriptionIdentifier))
+		_, err := registry.Renew("127.0.0.1:1", sub.SubscriptionIdentifier, time.Second)
+		assert.IsType(t, &custom_errors.NoSuchSubscriptionIdentifierError{}, err)
+
+		time.Sleep(80 * time.Millisecond)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&removed))
+	})
+
+	t.Run("list only returns the subscriptions of the subscriber", func(t *testing.T) {
+		registry := newSubscriptionRegistry()
+		t.Cleanup(registry.Stop)
+		first := registry.Add(dto.MonitorSeatUpdatesCallbackType, "127.0.0.1:1", "1", time.Second, func(int64) {})
+		registry.Add(dto.MonitorSeatUpdatesCallbackType, "127.0.0.1:2", "1", time.Second, func(int64) {})
+		second := registry.Add(dto.MonitorPriceUpdatesCallbackType, "127.0.0.1:1", "2", time.Second, func(int64) {})
+
+		subs := registry.List("127.0.0.1:1")
+		assert.Len(t, subs, 2)
+		assert.Equal(t, first.SubscriptionIdentifier, subs[0].SubscriptionIdentifier)
+		assert.Equal(t, second.SubscriptionIdentifier, subs[1].SubscriptionIdentifier)
+		assert.Equal(t, dto.ResponseType(dto.MonitorPriceUpdatesCallbackType), subs[1].CallbackType)
+	})
+}
+
+func TestClientConcurrentSubscriptions(t *testing.T) {
+	client := NewClient[int32](dto.MonitorSeatUpdatesCallbackType)
+	// callbacks are never acked, so subscribers are also dropped concurrently
+	defaultDeliveries := deliveries
+	deliveries = newDeliveryTracker(func([]byte, string) error { return nil }, time.Millisecond, 0)
+	t.Cleanup(func() { deliveries = defaultDeliveries })
+
+	// subscribes, renews, expires and drops concurrently, to be run with -race
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ctx := context.WithValue(context.Background(), "addr", fmt.Sprintf("127.0.0.1:%d", 10000+i))
+			for j := 0; j < 20; j++ {
+				client.Subscribe(ctx, int32(j%5), time.Duration(j%3)*time.Millisecond)
+				_ = client.Notify(int32(j%5), dto.MonitorSeatUpdatesCallbackType, nil, nil)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// every subscription is renewed by the last subscribe of each item, which expires in at most 2ms
+	assert.Eventually(t, func() bool { return client.Len() == 0 }, time.Second, 10*time.Millisecond)
+	client.RLock()
+	defer client.RUnlock()
+	assert.Len(t, client.NotifiableClients, 0)
+}
+
+// BenchmarkSubscriptionRegistry adds 100k subscriptions expiring over 100ms and waits for all of them to expire, reporting
+// the goroutines in use, which stay constant as expiries are served by a single goroutine, and the memory per subscription
+func BenchmarkSubscriptionRegistry(b *testing.B) {
+	const subscriptionCount = 100_000
+	for i := 0; i < b.N; i++ {
+		goroutinesBefore := runtime.NumGoroutine()
+		registry := newSubscriptionRegistry()
+		var removed int64
+		var before, after runtime.MemStats
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+
+		for j := 0; j < subscriptionCount; j++ {
+			expireDuration := 50*time.Millisecond + time.Duration(j%100)*time.Millisecond
+			registry.Add(dto.MonitorSeatUpdatesCallbackType, fmt.Sprintf("127.0.0.1:%d", j), "1", expireDuration, func(int64) {
+				atomic.AddInt64(&removed, 1)
+			})
+		}
+
+		runtime.ReadMemStats(&after)
+		b.ReportMetric(float64(runtime.NumGoroutine()-goroutinesBefore), "goroutines")
+		b.ReportMetric(float64(after.HeapAlloc-before.HeapAlloc)/subscriptionCount, "heap-B/subscription")
+
+		for atomic.LoadInt64(&removed) < subscriptionCount {
+			time.Sleep(10 * time.Millisecond)
+		}
+		if registry.Len() != 0 {
+			b.Fatalf("%v subscriptions did not expire", registry.Len())
+		}
+		registry.Stop()
+	}
+}
@@ -0,0 +1,34 @@
+package changelog
+
+import (
+	"github.com/cyiafn/flight_information_system/server/dao"
+)
+
+const (
+	// defaultCapacity is the number of changes kept for each flight and change type
+	defaultCapacity = 64
+)
+
+// changeLog is the change log of all flights, used by the package level functions
+var changeLog *Log
+
+func init() {
+	// initialises the change log on start
+	changeLog = NewLog(defaultCapacity)
+}
+
+// Record records a change to the flight in the change log of all flights
+func Record(changeType ChangeType, flight *dao.Flight) *Change {
+	return changeLog.Record(changeType, flight)
+}
+
+// Since gets the changes of the change type to the flight after the sequence number provided from the change log of all
+// flights
+func Since(flightIdentifier int32, changeType ChangeType, sequenceNumber int64) ([]*Change, bool) {
+	return changeLog.Since(flightIdentifier, changeType, sequenceNumber)
+}
+
+// Forget deletes the change log of the flight from the change log of all flights
+func Forget(flightIdentifier int32) {
+	changeLog.Forget(flightIdentifier)
+}
@@ -0,0 +1,166 @@
+package changelog
+
+import (
+	"sync"
+
+	"github.com/cyiafn/flight_information_system/server/dao"
+)
+
+/**
+Every change to a flight that subscribers are notified of is recorded in a change log of the flight and the type of
+change with a sequence number. Subscribers of each callback only see one type of change, so sequence numbers are kept
+per flight and change type. They start at 1 and increase by 1 with every change, so a subscriber that sees a gap in the
+sequence numbers of the callbacks it receives knows it has missed a change, and can get the changes it missed from the log.
+
+Sequence numbers are assigned by the FlightRepository in the same write as the change (see dao.Flight), so changes made
+concurrently are numbered in the order they were made even if they are recorded here out of order.
+
+Only the latest changes of each flight and change type are kept. If a change a subscriber missed is no longer kept, it
+is sent a snapshot of the flight instead.
+*/
+
+// ChangeType is what changed about the flight
+type ChangeType uint8
+
+const (
+	SeatsChange ChangeType = iota + 1
+	AirfareChange
+	StatusChange
+)
+
+// IsValid checks if the change type is known
+func (t ChangeType) IsValid() bool {
+	return t >= SeatsChange && t <= StatusChange
+}
+
+// SequenceNumber gets the sequence number of the latest change of this type to the flight
+func (t ChangeType) SequenceNumber(flight *dao.Flight) int64 {
+	switch t {
+	case SeatsChange:
+		return flight.SeatsSequenceNumber
+	case AirfareChange:
+		return flight.AirfareSequenceNumber
+	case StatusChange:
+		return flight.StatusSequenceNumber
+	}
+	return 0
+}
+
+// Change is a change to a flight, Flight is the flight right after the change
+type Change struct {
+	SequenceNumber int64
+	ChangeType     ChangeType
+	Flight         dao.Flight
+}
+
+// Log is a bounded change log of every flight.
+// This is CONCURRENT-SAFE
+type Log struct {
+	sync.Mutex
+	// logs are the change logs of each flight and change type
+	logs map[logKey]*changeTypeLog
+	// capacity is the maximum number of changes kept for each flight and change type
+	capacity int
+}
+
+// logKey is the flight and change type of a change log
+type logKey struct {
+	FlightIdentifier int32
+	ChangeType       ChangeType
+}
+
+// changeTypeLog is the change log of a single flight and change type
+type changeTypeLog struct {
+	// Changes are the latest changes recorded in ascending order of sequence number
+	Changes []*Change
+}
+
+// NewLog instantiates an empty change log that keeps up to capacity changes of each flight and change type
+func NewLog(capacity int) *Log {
+	return &Log{
+		logs:     make(map[logKey]*changeTypeLog),
+		capacity: capacity,
+	}
+}
+
+// Record records a change to the flight with the sequence number of the change type assigned to the flight by the
+// FlightRepository, the change recorded is returned
+func (l *Log) Record(changeType ChangeType, flight *dao.Flight) *Change {
+	change := &Change{SequenceNumber: changeType.SequenceNumber(flight), ChangeType: changeType, Flight: *flight}
+	output := *change
+
+	l.Lock()
+	defer l.Unlock()
+	key := logKey{FlightIdentifier: flight.FlightIdentifier, ChangeType: changeType}
+	log, ok := l.logs[key]
+	if !ok {
+		log = &changeTypeLog{Changes: make([]*Change, 0, l.capacity)}
+		l.logs[key] = log
+	}
+
+	// changes are usually recorded in order, so the change is inserted from the back
+	i := len(log.Changes)
+	for i > 0 && log.Changes[i-1].SequenceNumber > change.SequenceNumber {
+		i--
+	}
+	if i > 0 && log.Changes[i-1].SequenceNumber == change.SequenceNumber {
+		// already recorded
+		return &output
+	}
+	if len(log.Changes) == l.capacity {
+		if i == 0 {
+			// older than every change kept
+			return &output
+		}
+		// the oldest change is no longer kept
+		copy(log.Changes, log.Changes[1:])
+		log.Changes = log.Changes[:l.capacity-1]
+		i--
+	}
+	log.Changes = append(log.Changes, nil)
+	copy(log.Changes[i+1:], log.Changes[i:])
+	log.Changes[i] = change
+	return &output
+}
+
+// Since gets the changes of the change type to the flight after the sequence number provided in ascending order, up to
+// the first change that has not been recorded yet. Returns false if the change after the sequence number is no longer
+// kept or not recorded yet, or if the sequence number is after the latest change recorded, e.g. it is from before the
+// server restarted.
+func (l *Log) Since(flightIdentifier int32, changeType ChangeType, sequenceNumber int64) ([]*Change, bool) {
+	l.Lock()
+	defer l.Unlock()
+	log, ok := l.logs[logKey{FlightIdentifier: flightIdentifier, ChangeType: changeType}]
+	if !ok || len(log.Changes) == 0 {
+		return []*Change{}, sequenceNumber == 0
+	}
+	if sequenceNumber > log.Changes[len(log.Changes)-1].SequenceNumber {
+		return nil, false
+	}
+
+	output := make([]*Change, 0)
+	for _, change := range log.Changes {
+		if change.SequenceNumber <= sequenceNumber {
+			continue
+		}
+		if change.SequenceNumber != sequenceNumber+int64(len(output))+1 {
+			// the change after sequenceNumber is no longer kept, or a later one is yet to be recorded
+			if len(output) == 0 {
+				return nil, false
+			}
+			break
+		}
+		copiedChange := *change
+		output = append(output, &copiedChange)
+	}
+	return output, true
+}
+
+// Forget deletes the change logs of the flight, e.g. once the flight is deleted
+func (l *Log) Forget(flightIdentifier int32) {
+	l.Lock()
+	defer l.Unlock()
+	for changeType := SeatsChange; changeType.IsValid(); changeType++ {
+		delete(l.logs, logKey{FlightIdentifier: flightIdentifier, ChangeType: changeType})
+	}
+}
@@ -0,0 +1,119 @@
+package changelog
+
+import (
+	"testing"
+
+	"github.com/cyiafn/flight_information_system/server/dao"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLog(t *testing.T) {
+	log := NewLog(3)
+	flight := &dao.Flight{FlightIdentifier: 1, TotalAvailableSeats: 10}
+
+	changes, ok := log.Since(1, SeatsChange, 0)
+	assert.True(t, ok)
+	assert.Len(t, changes, 0)
+
+	for i := 0; i < 2; i++ {
+		flight.TotalAvailableSeats -= 1
+		flight.SeatsSequenceNumber += 1
+		change := log.Record(SeatsChange, flight)
+		assert.Equal(t, int64(i+1), change.SequenceNumber)
+	}
+	// the change log keeps a copy of the flight
+	flight.TotalAvailableSeats = 0
+
+	changes, ok = log.Since(1, SeatsChange, 0)
+	assert.True(t, ok)
+	assert.Len(t, changes, 2)
+	assert.Equal(t, int32(9), changes[0].Flight.TotalAvailableSeats)
+	assert.Equal(t, int32(8), changes[1].Flight.TotalAvailableSeats)
+
+	// sequence numbers are per flight and change type
+	flight.AirfareSequenceNumber = 1
+	assert.Equal(t, int64(1), log.Record(AirfareChange, flight).SequenceNumber)
+	changes, ok = log.Since(1, AirfareChange, 0)
+	assert.True(t, ok)
+	assert.Len(t, changes, 1)
+	changes, ok = log.Since(1, SeatsChange, 2)
+	assert.True(t, ok)
+	assert.Len(t, changes, 0)
+
+	for i := 0; i < 3; i++ {
+		flight.StatusSequenceNumber += 1
+		log.Record(StatusChange, flight)
+	}
+	flight.StatusSequenceNumber = 1
+	log.Record(StatusChange, flight)
+	tests := []struct {
+		Name                    string
+		SequenceNumber          int64
+		ExpectedSequenceNumbers []int64
+		ExpectedOk              bool
+	}{
+		{Name: "up to date", SequenceNumber: 3, ExpectedSequenceNumbers: []int64{}, ExpectedOk: true},
+		{Name: "oldest change kept", SequenceNumber: 0, ExpectedSequenceNumbers: []int64{1, 2, 3}, ExpectedOk: true},
+		{Name: "after the latest change", SequenceNumber: 4, ExpectedOk: false},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.Name, func(t *testing.T) {
+			changes, ok := log.Since(1, StatusChange, test.SequenceNumber)
+			assert.Equal(t, test.ExpectedOk, ok)
+			if !test.ExpectedOk {
+				return
+			}
+			sequenceNumbers := make([]int64, len(changes))
+			for i, change := range changes {
+				sequenceNumbers[i] = change.SequenceNumber
+			}
+			assert.Equal(t, test.ExpectedSequenceNumbers, sequenceNumbers)
+		})
+	}
+
+	log.Forget(1)
+	_, ok = log.Since(1, StatusChange, 3)
+	assert.False(t, ok)
+	_, ok = log.Since(1, SeatsChange, 2)
+	assert.False(t, ok)
+}
+
+func TestLogOutOfOrder(t *testing.T) {
+	log := NewLog(3)
+	record := func(sequenceNumber int64) {
+		log.Record(SeatsChange, &dao.Flight{FlightIdentifier: 1, SeatsSequenceNumber: sequenceNumber})
+	}
+	getSequenceNumbers := func(sequenceNumber int64) ([]int64, bool) {
+		changes, ok := log.Since(1, SeatsChange, sequenceNumber)
+		sequenceNumbers := make([]int64, len(changes))
+		for i, change := range changes {
+			sequenceNumbers[i] = change.SequenceNumber
+		}
+		return sequenceNumbers, ok
+	}
+
+	// changes are returned up to the first change yet to be recorded
+	record(1)
+	record(3)
+	sequenceNumbers, ok := getSequenceNumbers(0)
+	assert.True(t, ok)
+	assert.Equal(t, []int64{1}, sequenceNumbers)
+	_, ok = getSequenceNumbers(1)
+	assert.False(t, ok)
+
+	record(2)
+	sequenceNumbers, ok = getSequenceNumbers(0)
+	assert.True(t, ok)
+	assert.Equal(t, []int64{1, 2, 3}, sequenceNumbers)
+
+	// the oldest changes are no longer kept, changes older than every change kept are not recorded
+	record(5)
+	record(4)
+	record(1)
+	sequenceNumbers, ok = getSequenceNumbers(2)
+	assert.True(t, ok)
+	assert.Equal(t, []int64{3, 4, 5}, sequenceNumbers)
+	_, ok = getSequenceNumbers(1)
+	assert.False(t, ok)
+}
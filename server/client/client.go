@@ -0,0 +1,519 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/cyiafn/flight_information_system/server/custom_errors"
+	"github.com/cyiafn/flight_information_system/server/dto"
+	"github.com/cyiafn/flight_information_system/server/dto/status_code"
+	"github.com/cyiafn/flight_information_system/server/logs"
+	fisnet "github.com/cyiafn/flight_information_system/server/net"
+	"github.com/cyiafn/flight_information_system/server/utils/rpc"
+	"github.com/pkg/errors"
+)
+
+/**
+This is a Go client SDK for the flight information system. It follows the same wire format as the server:
+
+| uint8, 1 byte: request type | string, 9 bytes: requestID | int64, 8 bytes: byte array no. | int64, 8 bytes: total byte arrays | payload
+
+A single UDP socket is used for all calls so that the server is able to deliver callbacks to the same IP:port that
+subscribed to them. The socket is not "connected" as callbacks are sent from a different port than the server's listener.
+All incoming datagrams are read by a single goroutine and dispatched by requestID (responses) or by response type (callbacks).
+
+If no response arrives in time, the exact same byte arrays (with the same requestID) are retransmitted according to the
+RetryPolicy. In at most once mode, the server's duplicate request filter will recognise the requestID and reply with the
+cached response instead of executing the RPC call again.
+
+Every callback received is acked with its requestID, as the server retransmits callbacks until they are acked. Callbacks
+with a requestID already handled are retransmissions (the ack was lost or late) and are acked but not delivered again.
+*/
+
+const (
+	// defaultTimeout is how long we wait for a response before retransmitting, the same as the TypeScript client
+	defaultTimeout = 5 * time.Second
+	// defaultMaxRetries is the number of retransmissions before giving up, the same as the TypeScript client
+	defaultMaxRetries = 3
+	// defaultBackoff is the wait before the first retransmission
+	defaultBackoff = 100 * time.Millisecond
+	// handledCallbackWindow is how long the requestIDs of callbacks are remembered to discard retransmissions, well over
+	// the time the server keeps retransmitting for
+	handledCallbackWindow = 1 * time.Minute
+	// maxByteArrayBuffers caps the byte arrays a response or callback can be split into, the same as the server, so a
+	// malformed header cannot allocate more
+	maxByteArrayBuffers = 128
+
+	// requestType length in bytes
+	requestTypeBytesLength = 1
+	// schemaVersion length in bytes
+	schemaVersionBytesLength = 1
+	// requestID length in bytes
+	shortIDBytesLength = 9
+	// currentByteBufferArrayBytesLength no in bytes
+	currentByteBufferArrayBytesLength = 8
+	// totalByteBufferArrayByteLength no in bytes
+	totalByteBufferArrayByteLength = 8
+	// requestIDAlphabet are the characters used in requestIDs, the same as the TypeScript client
+	requestIDAlphabet = "1234567890abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	// headerBytesLength is the total size of the header of each byte array buffer
+	headerBytesLength = requestTypeBytesLength + schemaVersionBytesLength + shortIDBytesLength + currentByteBufferArrayBytesLength + totalByteBufferArrayByteLength
+)
+
+// Client is a client for the flight information system server. It is CONCURRENT-SAFE.
+type Client struct {
+	sync.Mutex
+	// conn is the UDP socket used for sending requests and receiving responses and callbacks
+	conn *net.UDPConn
+	// serverAddr is the IP:port of the server
+	serverAddr *net.UDPAddr
+	// RetryPolicy configures the timeouts and retransmissions of every call
+	RetryPolicy RetryPolicy
+	// pendingCalls are the calls waiting on a response, keyed by requestID
+	pendingCalls map[string]*pendingCall
+	// subscriptions are the callback handlers registered, keyed by the callback type
+	subscriptions map[dto.ResponseType][]*subscription
+	// pendingCallbacks are the callbacks waiting on the rest of their byte array buffers, keyed by requestID
+	pendingCallbacks map[string]*pendingCall
+	// handledCallbacks are the requestIDs of the callbacks delivered and when they were received
+	handledCallbacks map[string]time.Time
+	// closeChan signals the read loop to terminate
+	closeChan chan struct{}
+}
+
+// NewClient instantiates a client that sends requests to the server at addr (IP:port)
+func NewClient(addr string) (*Client, error) {
+	serverAddr, err := net.ResolveUDPAddr("udp", addr)
+	if err != nil {
+		logs.Error("unable to resolve server address, err: %v", err)
+		return nil, err
+	}
+	conn, err := net.ListenUDP("udp", nil)
+	if err != nil {
+		logs.Error("unable to open UDP socket, err: %v", err)
+		return nil, err
+	}
+
+	c := &Client{
+		conn:             conn,
+		serverAddr:       serverAddr,
+		RetryPolicy:      DefaultRetryPolicy(),
+		pendingCalls:     make(map[string]*pendingCall),
+		subscriptions:    make(map[dto.ResponseType][]*subscription),
+		pendingCallbacks: make(map[string]*pendingCall),
+		handledCallbacks: make(map[string]time.Time),
+		closeChan:        make(chan struct{}),
+	}
+	go c.readLoop()
+	return c, nil
+}
+
+// Close closes the underlying socket and terminates the read loop
+func (c *Client) Close() error {
+	close(c.closeChan)
+	return c.conn.Close()
+}
+
+// pendingCall collects all the byte array buffers of a response
+type pendingCall struct {
+	// ResponseType expected for this call
+	ResponseType dto.ResponseType
+	// Body are the payloads of each byte array buffer received, indexed by byte array number - 1
+	Body [][]byte
+	// done is closed once all byte array buffers are received
+	done chan struct{}
+	// StartedAt is when the first byte array buffer was received, for discarding callbacks that are never completed
+	StartedAt time.Time
+}
+
+// AddByteBufferArray stores the body of a byte array buffer of the response, returns false if the byte array number is
+// invalid or the total is not the same as the total of the first byte array buffer received
+func (p *pendingCall) AddByteBufferArray(buf []byte) bool {
+	total := getTotalByteBufferArrayNumber(buf)
+	current := getCurrentByteBufferArrayNumber(buf)
+	if total <= 0 || total > maxByteArrayBuffers || current <= 0 || current > total || (p.Body != nil && int64(len(p.Body)) != total) {
+		logs.Warn("discarding datagram with byte array no. %v out of %v", current, total)
+		return false
+	}
+	if p.Body == nil {
+		p.Body = make([][]byte, total)
+	}
+	p.Body[current-1] = getBody(buf)
+	return true
+}
+
+// IsComplete checks if all the byte arrays are here
+func (p *pendingCall) IsComplete() bool {
+	if len(p.Body) == 0 {
+		return false
+	}
+	for _, part := range p.Body {
+		if part == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// CompileResponse gets all the compiled bodies of the different byte arrays.
+func (p *pendingCall) CompileResponse() []byte {
+	var response []byte
+	for _, part := range p.Body {
+		response = append(response, part...)
+	}
+	return response
+}
+
+// subscription is a callback handler registered for a callback type until it expires
+type subscription struct {
+	Handler  func(body []byte)
+	ExpireAt time.Time
+	// SubscriptionIdentifier is the identifier of the subscription on the server, 0 until the server has replied
+	SubscriptionIdentifier int64
+}
+
+// RetryPolicy configures how long to wait for a response and how many times to retransmit a request
+type RetryPolicy struct {
+	// Timeout is how long to wait for the full response after each transmission
+	Timeout time.Duration
+	// MaxRetries is the number of retransmissions after the first transmission, 0 disables retries
+	MaxRetries int
+	// Backoff is the wait before the first retransmission, it doubles after every retransmission
+	Backoff time.Duration
+}
+
+// DefaultRetryPolicy returns the retry policy used by a new client
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		Timeout:    defaultTimeout,
+		MaxRetries: defaultMaxRetries,
+		Backoff:    defaultBackoff,
+	}
+}
+
+// TimeoutError is returned when no full response was received after all retransmissions
+type TimeoutError struct {
+	RequestID string
+	Attempts  int
+}
+
+func (t *TimeoutError) Error() string {
+	return fmt.Sprintf("no response received for requestID: %s after %v attempts", t.RequestID, t.Attempts)
+}
+
+func NewTimeoutError(requestID string, attempts int) error {
+	return &TimeoutError{RequestID: requestID, Attempts: attempts}
+}
+
+// call sends the request and blocks until the full response is received, the context is done or all retransmissions time out.
+// The response body is decoded into res, which may be nil for RPC calls without a response body.
+func (c *Client) call(ctx context.Context, requestType dto.RequestType, req any, res any) error {
+	payload, err := rpc.MarshalTagged(req)
+	if err != nil {
+		return err
+	}
+
+	requestID := newRequestID()
+	call := &pendingCall{ResponseType: dto.GetResponseType(requestType), done: make(chan struct{})}
+	c.Lock()
+	c.pendingCalls[requestID] = call
+	c.Unlock()
+	defer func() {
+		c.Lock()
+		delete(c.pendingCalls, requestID)
+		c.Unlock()
+	}()
+
+	// the byte arrays are only built once so that every retransmission is identical
+	bufs := splitPayloadForSending(uint8(requestType), []byte(requestID), payload)
+	policy := c.RetryPolicy
+	backoff := policy.Backoff
+
+	for attempt := 1; ; attempt++ {
+		if err := c.send(bufs); err != nil {
+			return err
+		}
+
+		timer := time.NewTimer(policy.Timeout)
+		select {
+		case <-call.done:
+			timer.Stop()
+			return decodeResponse(call.CompileResponse(), res)
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		if attempt > policy.MaxRetries {
+			logs.Warn("giving up on requestID: %s after %v attempts", requestID, attempt)
+			return NewTimeoutError(requestID, attempt)
+		}
+		logs.Info("no response for requestID: %s, retransmitting (attempt %v of %v)", requestID, attempt+1, policy.MaxRetries+1)
+
+		// byte arrays of the response received so far are kept, so a retransmitted response only needs to fill in the missing ones
+		timer = time.NewTimer(backoff)
+		select {
+		case <-call.done:
+			timer.Stop()
+			return decodeResponse(call.CompileResponse(), res)
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
+
+// send writes all the byte arrays of a request to the server
+func (c *Client) send(bufs [][]byte) error {
+	for _, buf := range bufs {
+		if _, err := c.conn.WriteToUDP(buf, c.serverAddr); err != nil {
+			logs.Error("error sending payload: %v", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// subscribe registers a handler for a callback type until the duration expires
+func (c *Client) subscribe(callbackType dto.ResponseType, duration time.Duration, handler func(body []byte)) *subscription {
+	sub := &subscription{Handler: handler, ExpireAt: time.Now().Add(duration)}
+	c.Lock()
+	defer c.Unlock()
+	c.subscriptions[callbackType] = append(c.subscriptions[callbackType], sub)
+	return sub
+}
+
+// unsubscribe removes a handler for a callback type
+func (c *Client) unsubscribe(callbackType dto.ResponseType, sub *subscription) {
+	c.Lock()
+	defer c.Unlock()
+	subs := c.subscriptions[callbackType]
+	for i, v := range subs {
+		if v == sub {
+			c.subscriptions[callbackType] = append(subs[:i], subs[i+1:]...)
+			return
+		}
+	}
+}
+
+// setSubscriptionIdentifier records the identifier of the subscription on the server
+func (c *Client) setSubscriptionIdentifier(sub *subscription, subscriptionIdentifier int64) {
+	c.Lock()
+	defer c.Unlock()
+	sub.SubscriptionIdentifier = subscriptionIdentifier
+}
+
+// unsubscribeByIdentifier removes every handler of the subscription on the server, subscribing to the same item again
+// returns the same subscription identifier
+func (c *Client) unsubscribeByIdentifier(subscriptionIdentifier int64) {
+	c.Lock()
+	defer c.Unlock()
+	for callbackType, subs := range c.subscriptions {
+		kept := make([]*subscription, 0, len(subs))
+		for _, sub := range subs {
+			if sub.SubscriptionIdentifier != subscriptionIdentifier {
+				kept = append(kept, sub)
+			}
+		}
+		c.subscriptions[callbackType] = kept
+	}
+}
+
+// renewByIdentifier makes every handler of the subscription on the server expire at expireAt instead
+func (c *Client) renewByIdentifier(subscriptionIdentifier int64, expireAt time.Time) {
+	c.Lock()
+	defer c.Unlock()
+	for _, subs := range c.subscriptions {
+		for _, sub := range subs {
+			if sub.SubscriptionIdentifier == subscriptionIdentifier {
+				sub.ExpireAt = expireAt
+			}
+		}
+	}
+}
+
+// readLoop reads every incoming datagram and dispatches it to the pending call or callback subscribers
+func (c *Client) readLoop() {
+	for {
+		buf := make([]byte, fisnet.DefaultByteBufferSize)
+		n, _, err := c.conn.ReadFromUDP(buf)
+		if err != nil {
+			select {
+			case <-c.closeChan:
+				return
+			default:
+			}
+			logs.Warn("unable to read from buffer, err: %v", err)
+			continue
+		}
+		if n < headerBytesLength {
+			logs.Warn("discarding datagram of len %v as it is smaller than the header", n)
+			continue
+		}
+		// the server replies in the schema version we send in, anything else is not for us
+		if getSchemaVersion(buf) != rpc.CurrentSchemaVersion {
+			logs.Warn("discarding datagram in schema version %v", getSchemaVersion(buf))
+			continue
+		}
+		c.handleIncomingData(buf[:n])
+	}
+}
+
+// handleIncomingData routes a datagram based on its response type
+func (c *Client) handleIncomingData(buf []byte) {
+	responseType := dto.ResponseType(getResponseType(buf))
+	// 201 - 300 are callback messages
+	if responseType >= dto.MonitorSeatUpdatesCallbackType {
+		c.handleCallbackByteBufferArray(responseType, buf)
+		return
+	}
+
+	c.Lock()
+	defer c.Unlock()
+	call, ok := c.pendingCalls[string(getRequestID(buf))]
+	if !ok || call.IsComplete() {
+		// either a late reply to a call that has timed out or a duplicate, nothing to do
+		return
+	}
+	if call.ResponseType != responseType {
+		logs.Warn("received response type %v for request %s, expected %v", responseType, getRequestID(buf), call.ResponseType)
+		return
+	}
+
+	if call.AddByteBufferArray(buf) && call.IsComplete() {
+		close(call.done)
+	}
+}
+
+// handleCallbackByteBufferArray collects the byte array buffers of a callback and handles the callback once all of them
+// are received. Callbacks are split into byte array buffers the same way responses are.
+func (c *Client) handleCallbackByteBufferArray(callbackType dto.ResponseType, buf []byte) {
+	requestID := string(getRequestID(buf))
+	now := time.Now()
+
+	c.Lock()
+	if _, ok := c.handledCallbacks[requestID]; ok {
+		c.Unlock()
+		// a retransmission of a callback already handled, handleCallback acks and discards it
+		c.handleCallback(callbackType, requestID, nil)
+		return
+	}
+	for pendingRequestID, pending := range c.pendingCallbacks {
+		if now.Sub(pending.StartedAt) > handledCallbackWindow {
+			delete(c.pendingCallbacks, pendingRequestID)
+		}
+	}
+	pending, ok := c.pendingCallbacks[requestID]
+	if !ok {
+		pending = &pendingCall{ResponseType: callbackType, StartedAt: now}
+	}
+	// a callback is only kept once a valid byte array buffer of it is received
+	if !pending.AddByteBufferArray(buf) {
+		c.Unlock()
+		return
+	}
+	c.pendingCallbacks[requestID] = pending
+	if !pending.IsComplete() {
+		c.Unlock()
+		return
+	}
+	delete(c.pendingCallbacks, requestID)
+	c.Unlock()
+
+	c.handleCallback(callbackType, requestID, pending.CompileResponse())
+}
+
+// handleCallback acks the callback and delivers it to every unexpired subscriber of that callback type, unless it has
+// already been delivered
+func (c *Client) handleCallback(callbackType dto.ResponseType, requestID string, body []byte) {
+	// retransmissions are acked too, as the previous ack may have been lost
+	c.ack(requestID)
+
+	now := time.Now()
+	c.Lock()
+	if c.isHandledCallback(requestID, now) {
+		c.Unlock()
+		logs.Info("discarding retransmitted callback %s", requestID)
+		return
+	}
+	subs := make([]*subscription, 0, len(c.subscriptions[callbackType]))
+	for _, sub := range c.subscriptions[callbackType] {
+		if sub.ExpireAt.After(now) {
+			subs = append(subs, sub)
+		}
+	}
+	c.subscriptions[callbackType] = subs
+	c.Unlock()
+
+	for _, sub := range subs {
+		sub.Handler(body)
+	}
+}
+
+// isHandledCallback checks if the callback has already been delivered, else it is recorded as delivered. The lock must
+// be held by the caller.
+func (c *Client) isHandledCallback(requestID string, now time.Time) bool {
+	for handledRequestID, receivedAt := range c.handledCallbacks {
+		if now.Sub(receivedAt) > handledCallbackWindow {
+			delete(c.handledCallbacks, handledRequestID)
+		}
+	}
+	if _, ok := c.handledCallbacks[requestID]; ok {
+		return true
+	}
+	c.handledCallbacks[requestID] = now
+	return false
+}
+
+// ack sends a one way ack for the callback with the requestID to the server
+func (c *Client) ack(requestID string) {
+	payload, err := rpc.MarshalTagged(&dto.AckCallbackRequest{RequestID: requestID})
+	if err != nil {
+		logs.Warn("unable to marshal ack for callback %s, err: %v", requestID, err)
+		return
+	}
+	if err := c.send(splitPayloadForSending(uint8(dto.AckCallbackRequestType), []byte(newRequestID()), payload)); err != nil {
+		logs.Warn("unable to ack callback %s, err: %v", requestID, err)
+	}
+}
+
+// decodeResponse decodes the dto.Response wrapper into its status code and data.
+func decodeResponse(body []byte, res any) error {
+	if len(body) == 0 {
+		return custom_errors.NewMarshallerError(errors.Errorf("empty response body"))
+	}
+	wrappedResp := &dto.Response{}
+	if err := rpc.UnmarshalTagged(body, wrappedResp); err != nil {
+		return err
+	}
+	if err := status_code.GetError(wrappedResp.StatusCode); err != nil {
+		// some errors carry details, which are sent in place of the data
+		if detailedErr, ok := err.(custom_errors.DetailedError); ok {
+			if unmarshalErr := rpc.UnmarshalTagged(body, &dto.Response{Data: detailedErr.Details()}); unmarshalErr != nil {
+				logs.Warn("unable to unmarshal error details, err: %v", unmarshalErr)
+			}
+		}
+		return err
+	}
+	if res == nil {
+		return nil
+	}
+	return rpc.UnmarshalTagged(body, &dto.Response{Data: res})
+}
+
+// newRequestID generates a random requestID of exactly shortIDBytesLength characters. Note that shortid cannot be used here
+// as the IDs it generates are not always 9 characters long.
+func newRequestID() string {
+	requestID := make([]byte, shortIDBytesLength)
+	for i := range requestID {
+		requestID[i] = requestIDAlphabet[rand.Intn(len(requestIDAlphabet))]
+	}
+	return string(requestID)
+}
@@ -0,0 +1,345 @@
+package client
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cyiafn/flight_information_system/server/custom_errors"
+	"github.com/cyiafn/flight_information_system/server/dto"
+	"github.com/cyiafn/flight_information_system/server/dto/status_code"
+	"github.com/cyiafn/flight_information_system/server/server"
+	"github.com/cyiafn/flight_information_system/server/utils"
+	"github.com/cyiafn/flight_information_system/server/utils/rpc"
+	"github.com/stretchr/testify/assert"
+)
+
+// startFakeServer replies to every complete request with the response returned by handler, split the same way the server splits responses.
+// The first dropResponses responses of every requestID are dropped to emulate a lossy network.
+func startFakeServer(t *testing.T, dropResponses int, handler func(requestType dto.RequestType, body []byte) *dto.Response) string {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.ParseIP("127.0.0.1")})
+	assert.Nil(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		bodies := make(map[string][][]byte)
+		receipts := make(map[string]int)
+		for {
+			buf := make([]byte, 512)
+			n, addr, err := conn.ReadFromUDP(buf)
+			if err != nil {
+				return
+			}
+			buf = buf[:n]
+			requestID := string(getRequestID(buf))
+			if _, ok := bodies[requestID]; !ok {
+				bodies[requestID] = make([][]byte, getTotalByteBufferArrayNumber(buf))
+			}
+			bodies[requestID][getCurrentByteBufferArrayNumber(buf)-1] = getBody(buf)
+
+			call := &pendingCall{Body: bodies[requestID]}
+			if !call.IsComplete() {
+				continue
+			}
+			requestType := dto.RequestType(getResponseType(buf))
+			payload, _ := rpc.MarshalTagged(handler(requestType, call.CompileResponse()))
+			receipts[requestID] += 1
+			if receipts[requestID] <= dropResponses {
+				continue
+			}
+			for _, part := range splitPayloadForSending(uint8(dto.GetResponseType(requestType)), []byte(requestID), payload) {
+				_, _ = conn.WriteToUDP(part, addr)
+			}
+		}
+	}()
+
+	return conn.LocalAddr().String()
+}
+
+func TestClient(t *testing.T) {
+	flightIdentifiers := make([]int32, 300)
+	for i := range flightIdentifiers {
+		flightIdentifiers[i] = int32(i)
+	}
+
+	addr := startFakeServer(t, 0, func(requestType dto.RequestType, body []byte) *dto.Response {
+		switch requestType {
+		case dto.PingRequestType:
+			return &dto.Response{StatusCode: status_code.Success}
+		case dto.GetFlightIdentifiersRequestType:
+			req := &dto.GetFlightIdentifiersRequest{}
+			_ = rpc.UnmarshalTagged(body, req)
+			if req.SourceLocation != "Singapore" {
+				return &dto.Response{StatusCode: status_code.NoMatchForSourceAndDestination}
+			}
+			return &dto.Response{StatusCode: status_code.Success, Data: &dto.GetFlightIdentifiersResponse{FlightIdentifiers: flightIdentifiers}}
+		case dto.ReserveSpecificSeatsRequestType:
+			return &dto.Response{StatusCode: status_code.SeatsUnavailable, Data: custom_errors.NewSeatsUnavailableError([]string{"1A", "3C"})}
+		case dto.CreateFlightRequestType:
+			req := &dto.CreateFlightRequest{}
+			_ = rpc.UnmarshalTagged(body, req)
+			return &dto.Response{StatusCode: status_code.Success, Data: &dto.CreateFlightResponse{FlightIdentifier: int32(len(req.SourceLocation))}}
+		}
+		return &dto.Response{StatusCode: status_code.BusinessLogicGenericError}
+	})
+
+	c, err := NewClient(addr)
+	assert.Nil(t, err)
+	defer c.Close()
+	ctx := context.Background()
+
+	t.Run("ping", func(t *testing.T) {
+		assert.Nil(t, c.Ping(ctx))
+	})
+
+	t.Run("response split into multiple byte arrays", func(t *testing.T) {
+		res, err := c.GetFlightIdentifiers(ctx, &dto.GetFlightIdentifiersRequest{SourceLocation: "Singapore", DestinationLocation: "Bali"})
+		assert.Nil(t, err)
+		assert.Equal(t, flightIdentifiers, res.FlightIdentifiers)
+	})
+
+	t.Run("request split into multiple byte arrays", func(t *testing.T) {
+		res, err := c.CreateFlight(ctx, &dto.CreateFlightRequest{SourceLocation: strings.Repeat("a", 1000)})
+		assert.Nil(t, err)
+		assert.Equal(t, int32(1000), res.FlightIdentifier)
+	})
+
+	t.Run("status code decoded into custom error", func(t *testing.T) {
+		_, err := c.GetFlightIdentifiers(ctx, &dto.GetFlightIdentifiersRequest{SourceLocation: "Tokyo", DestinationLocation: "Bali"})
+		assert.IsType(t, &custom_errors.NoMatchForSourceAndDestinationError{}, err)
+	})
+
+	t.Run("error details decoded into custom error", func(t *testing.T) {
+		_, err := c.ReserveSpecificSeats(ctx, &dto.ReserveSpecificSeatsRequest{FlightIdentifier: 1, SeatLabels: []string{"1A", "3C"}})
+		assert.Equal(t, custom_errors.NewSeatsUnavailableError([]string{"1A", "3C"}), err)
+	})
+}
+
+// startRouter routes every datagram received to the server's RouteRequest in at most once mode and sends its replies back.
+// The first dropResponses replies of every requestID are dropped to emulate a lossy network, every reply routed is sent
+// to replies by requestID.
+func startRouter(t *testing.T, dropResponses int, routes map[dto.RequestType]func(ctx context.Context, request any) (any, error), replies chan<- [][]byte) string {
+	routeRequest, closeRouter := server.NewRequestRouter(routes, true)
+	t.Cleanup(closeRouter)
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.ParseIP("127.0.0.1")})
+	assert.Nil(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		receipts := make(map[string]int)
+		for {
+			buf := make([]byte, 512)
+			n, addr, err := conn.ReadFromUDP(buf)
+			if err != nil {
+				return
+			}
+			res, ok := routeRequest(context.WithValue(context.Background(), "addr", addr.String()), buf[:n])
+			if !ok {
+				continue
+			}
+			replies <- res
+			requestID := string(getRequestID(buf))
+			receipts[requestID] += 1
+			if receipts[requestID] <= dropResponses {
+				continue
+			}
+			for _, part := range res {
+				_, _ = conn.WriteToUDP(part, addr)
+			}
+		}
+	}()
+
+	return conn.LocalAddr().String()
+}
+
+func TestClientRetry(t *testing.T) {
+	tests := []struct {
+		Name          string
+		DropResponses int
+		MaxRetries    int
+		Err           error
+	}{
+		{
+			Name:          "no responses dropped",
+			DropResponses: 0,
+			MaxRetries:    2,
+		},
+		{
+			Name:          "retransmits with the same requestID until a response is received",
+			DropResponses: 2,
+			MaxRetries:    2,
+		},
+		{
+			Name:          "gives up after max retries",
+			DropResponses: 3,
+			MaxRetries:    2,
+			Err:           &TimeoutError{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var invocations int32
+			routes := map[dto.RequestType]func(ctx context.Context, request any) (any, error){
+				dto.GetFlightInformationRequestType: func(_ context.Context, request any) (any, error) {
+					atomic.AddInt32(&invocations, 1)
+					return &dto.GetFlightInformationResponse{Airfare: 10}, nil
+				},
+			}
+			replies := make(chan [][]byte, test.MaxRetries+1)
+			addr := startRouter(t, test.DropResponses, routes, replies)
+
+			c, err := NewClient(addr)
+			assert.Nil(t, err)
+			defer c.Close()
+			c.RetryPolicy = RetryPolicy{Timeout: 50 * time.Millisecond, MaxRetries: test.MaxRetries, Backoff: 10 * time.Millisecond}
+
+			res, err := c.GetFlightInformation(context.Background(), &dto.GetFlightInformationRequest{FlightIdentifier: 1})
+			if test.Err != nil {
+				assert.IsType(t, test.Err, err)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, float64(10), res.Airfare)
+			}
+
+			// the handler runs once however many times the request is retransmitted, every retransmission is replied to
+			// with the cached reply
+			assert.Equal(t, int32(1), atomic.LoadInt32(&invocations))
+			transmissions := utils.TernaryOperator(test.Err != nil, test.MaxRetries+1, test.DropResponses+1)
+			assert.Len(t, replies, transmissions)
+			first := <-replies
+			for i := 1; i < transmissions; i++ {
+				assert.Equal(t, first, <-replies)
+			}
+		})
+	}
+}
+
+func TestClientCallbacks(t *testing.T) {
+	addr := startFakeServer(t, 0, func(requestType dto.RequestType, body []byte) *dto.Response {
+		return &dto.Response{StatusCode: status_code.Success, Data: &dto.MonitorNewFlightsResponse{SubscriptionIdentifier: 1}}
+	})
+	c, err := NewClient(addr)
+	assert.Nil(t, err)
+	defer c.Close()
+
+	updates := make(chan *dto.MonitorNewFlightsCallbackResponse, 2)
+	_, err = c.MonitorNewFlights(context.Background(), &dto.MonitorNewFlightsCallbackRequest{SourceLocation: strings.Repeat("a", 1000), LengthOfMonitorIntervalInSeconds: 5}, func(res *dto.MonitorNewFlightsCallbackResponse) {
+		updates <- res
+	})
+	assert.Nil(t, err)
+
+	t.Run("callback split into multiple byte arrays", func(t *testing.T) {
+		callback := &dto.MonitorNewFlightsCallbackResponse{Flight: dto.FlightInformation{FlightIdentifier: 1, SourceLocation: strings.Repeat("a", 1000)}}
+		payload, err := rpc.MarshalTagged(&dto.Response{StatusCode: status_code.Success, Data: callback})
+		assert.Nil(t, err)
+		parts := splitPayloadForSending(uint8(dto.MonitorNewFlightsCallbackType), []byte("abcdefghi"), payload)
+		assert.Greater(t, len(parts), 1)
+
+		conn, err := net.Dial("udp", c.conn.LocalAddr().String())
+		assert.Nil(t, err)
+		defer conn.Close()
+		// byte arrays may arrive out of order, duplicated, and again once the callback is retransmitted
+		for i := len(parts) - 1; i >= 0; i-- {
+			_, _ = conn.Write(parts[i])
+		}
+		_, _ = conn.Write(parts[0])
+		for _, part := range parts {
+			_, _ = conn.Write(part)
+		}
+
+		select {
+		case res := <-updates:
+			assert.Equal(t, callback, res)
+		case <-time.After(time.Second):
+			t.Fatal("callback was not delivered")
+		}
+		select {
+		case <-updates:
+			t.Fatal("callback was delivered more than once")
+		case <-time.After(100 * time.Millisecond):
+		}
+	})
+}
+
+func TestClientCallbacksOfFlight(t *testing.T) {
+	addr := startFakeServer(t, 0, func(requestType dto.RequestType, body []byte) *dto.Response {
+		return &dto.Response{StatusCode: status_code.Success, Data: &dto.MonitorSeatUpdatesResponse{SubscriptionIdentifier: 1}}
+	})
+	c, err := NewClient(addr)
+	assert.Nil(t, err)
+	defer c.Close()
+
+	updates := map[int32]chan *dto.MonitorSeatUpdatesCallbackResponse{1: make(chan *dto.MonitorSeatUpdatesCallbackResponse, 1), 2: make(chan *dto.MonitorSeatUpdatesCallbackResponse, 1)}
+	for flightIdentifier, flightUpdates := range updates {
+		flightUpdates := flightUpdates
+		_, err = c.MonitorSeatUpdates(context.Background(), &dto.MonitorSeatUpdatesCallbackRequest{FlightIdentifier: flightIdentifier, LengthOfMonitorIntervalInSeconds: 5}, func(res *dto.MonitorSeatUpdatesCallbackResponse) {
+			flightUpdates <- res
+		})
+		assert.Nil(t, err)
+	}
+
+	callback := &dto.MonitorSeatUpdatesCallbackResponse{TotalAvailableSeats: 5, SequenceNumber: 1, FlightIdentifier: 2}
+	payload, err := rpc.MarshalTagged(&dto.Response{StatusCode: status_code.Success, Data: callback})
+	assert.Nil(t, err)
+	conn, err := net.Dial("udp", c.conn.LocalAddr().String())
+	assert.Nil(t, err)
+	defer conn.Close()
+	for _, part := range splitPayloadForSending(uint8(dto.MonitorSeatUpdatesCallbackType), []byte("abcdefghi"), payload) {
+		_, _ = conn.Write(part)
+	}
+
+	// only the subscription of the flight of the callback receives it
+	select {
+	case res := <-updates[2]:
+		assert.Equal(t, callback, res)
+	case <-time.After(time.Second):
+		t.Fatal("callback was not delivered")
+	}
+	select {
+	case <-updates[1]:
+		t.Fatal("callback was delivered to the subscription of another flight")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestHandleIncomingDataMalformedTotal(t *testing.T) {
+	c := &Client{pendingCalls: make(map[string]*pendingCall)}
+	call := &pendingCall{ResponseType: dto.PingResponseType, done: make(chan struct{})}
+	c.pendingCalls["abcdefghi"] = call
+
+	// a total of 2^40 byte arrays is discarded instead of allocated
+	c.handleIncomingData(addHeaders(uint8(dto.PingResponseType), []byte("abcdefghi"), 1, 1<<40, []byte("a")))
+	assert.Nil(t, call.Body)
+
+	// byte arrays with another total than the first byte array received are discarded
+	c.handleIncomingData(addHeaders(uint8(dto.PingResponseType), []byte("abcdefghi"), 1, 2, []byte("a")))
+	c.handleIncomingData(addHeaders(uint8(dto.PingResponseType), []byte("abcdefghi"), 3, 3, []byte("c")))
+	assert.Len(t, call.Body, 2)
+	assert.False(t, call.IsComplete())
+
+	c.handleIncomingData(addHeaders(uint8(dto.PingResponseType), []byte("abcdefghi"), 2, 2, []byte("b")))
+	assert.Equal(t, []byte("ab"), call.CompileResponse())
+	select {
+	case <-call.done:
+	default:
+		t.Fatal("call was not completed")
+	}
+}
+
+func TestHandleCallbackMalformedTotal(t *testing.T) {
+	c := &Client{pendingCallbacks: make(map[string]*pendingCall), handledCallbacks: make(map[string]time.Time)}
+
+	// a total of 2^40 byte arrays is discarded instead of allocated, and the callback is not kept
+	c.handleIncomingData(addHeaders(uint8(dto.MonitorNewFlightsCallbackType), []byte("abcdefghi"), 1, 1<<40, []byte("a")))
+	assert.Empty(t, c.pendingCallbacks)
+
+	// byte arrays with another total than the first byte array received are discarded
+	c.handleIncomingData(addHeaders(uint8(dto.MonitorNewFlightsCallbackType), []byte("abcdefghi"), 1, 2, []byte("a")))
+	c.handleIncomingData(addHeaders(uint8(dto.MonitorNewFlightsCallbackType), []byte("abcdefghi"), 3, 3, []byte("c")))
+	assert.Len(t, c.pendingCallbacks["abcdefghi"].Body, 2)
+	assert.False(t, c.pendingCallbacks["abcdefghi"].IsComplete())
+}
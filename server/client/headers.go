@@ -0,0 +1,68 @@
+package client
+
+import (
+	"github.com/cyiafn/flight_information_system/server/net"
+	"github.com/cyiafn/flight_information_system/server/utils"
+	"github.com/cyiafn/flight_information_system/server/utils/bytes"
+	"github.com/cyiafn/flight_information_system/server/utils/rpc"
+)
+
+// requestIDOffset is where the requestID starts, after the request type and schema version
+const requestIDOffset = requestTypeBytesLength + schemaVersionBytesLength
+
+// splitPayloadForSending splits the payload into multiple byte array buffers to send, this mirrors the way the server splits its responses
+func splitPayloadForSending(requestType uint8, requestID []byte, payload []byte) [][]byte {
+	// if the payload length == 0 we can hardcode this
+	if len(payload) == 0 {
+		return [][]byte{addHeaders(requestType, requestID, 1, 1, make([]byte, 0))}
+	}
+	output := make([][]byte, 0)
+	// we split it up into array of byte arrays
+	for i := 0; i < len(payload); i += net.DefaultByteBufferSize - headerBytesLength {
+		mxSize := utils.TernaryOperator(len(payload) < i+net.DefaultByteBufferSize-headerBytesLength, len(payload), i+net.DefaultByteBufferSize-headerBytesLength)
+		output = append(output, payload[i:mxSize])
+	}
+
+	for i := range output {
+		// we add headers for each byte array, byte array numbers start from 1
+		output[i] = addHeaders(requestType, requestID, int64(i+1), int64(len(output)), output[i])
+	}
+
+	return output
+}
+
+// addHeaders adds the requestType, schema version, requestID, byteBufferArrayNo and totalByteBufferArray to header. The client
+// always sends in rpc.CurrentSchemaVersion, which the server replies in.
+func addHeaders(requestType uint8, requestID []byte, byteArrayBufferNo int64, totalByteArrayBuffer int64, body []byte) []byte {
+	header := make([]byte, 0, headerBytesLength+len(body))
+	header = append(header, requestType)
+	header = append(header, uint8(rpc.CurrentSchemaVersion))
+	header = append(header, requestID...)
+	header = append(header, bytes.Int64ToBytes(byteArrayBufferNo)...)
+	header = append(header, bytes.Int64ToBytes(totalByteArrayBuffer)...)
+	return append(header, body...)
+}
+
+func getResponseType(response []byte) uint8 {
+	return response[0]
+}
+
+func getSchemaVersion(response []byte) rpc.SchemaVersion {
+	return rpc.SchemaVersion(response[requestTypeBytesLength])
+}
+
+func getRequestID(response []byte) []byte {
+	return response[requestIDOffset : requestIDOffset+shortIDBytesLength]
+}
+
+func getCurrentByteBufferArrayNumber(response []byte) int64 {
+	return bytes.ToInt64(response[requestIDOffset+shortIDBytesLength : requestIDOffset+shortIDBytesLength+currentByteBufferArrayBytesLength])
+}
+
+func getTotalByteBufferArrayNumber(response []byte) int64 {
+	return bytes.ToInt64(response[requestIDOffset+shortIDBytesLength+currentByteBufferArrayBytesLength : headerBytesLength])
+}
+
+func getBody(response []byte) []byte {
+	return response[headerBytesLength:]
+}
@@ -0,0 +1,81 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/cyiafn/flight_information_system/server/dto"
+	"github.com/cyiafn/flight_information_system/server/locations"
+	"github.com/cyiafn/flight_information_system/server/logs"
+)
+
+/**
+Stubs contains a typed method for each RPC call in dto.RequestType, generated into stubs_gen.go from the IDL except for the
+manual RPC calls here, which also update the subscriptions of the client. Errors returned by the server are decoded into the
+custom_errors types, e.g. *custom_errors.NoSuchFlightIdentifierError.
+*/
+
+// monitor registers onUpdate for every callback of callbackType received until the interval expires, then sends the
+// subscription request. subscriptionIdentifier points into res, where the server's reply is decoded. Methods cannot have
+// type parameters, hence the function.
+func monitor[T any](ctx context.Context, c *Client, requestType dto.RequestType, callbackType dto.ResponseType, interval time.Duration, req any, res any, subscriptionIdentifier *int64, onUpdate func(*T)) error {
+	// we register the handler before sending the request so that we do not miss any callbacks sent right after the response
+	sub := c.subscribe(callbackType, interval, func(body []byte) {
+		res := new(T)
+		if err := decodeResponse(body, res); err != nil {
+			logs.Warn("unable to decode callback of type %v, err: %v", callbackType, err)
+			return
+		}
+		// every subscription of the callback type receives the callback, so callbacks of other flights are skipped
+		if !isCallbackOfRequest(req, res) {
+			return
+		}
+		onUpdate(res)
+	})
+
+	if err := c.call(ctx, requestType, req, res); err != nil {
+		c.unsubscribe(callbackType, sub)
+		return err
+	}
+	c.setSubscriptionIdentifier(sub, *subscriptionIdentifier)
+	return nil
+}
+
+// isCallbackOfRequest checks if a callback is of the flight or route subscribed to by the monitor request
+func isCallbackOfRequest(req any, callback any) bool {
+	switch req := req.(type) {
+	case *dto.MonitorSeatUpdatesCallbackRequest:
+		// servers from before the flight identifier was appended to seat updates send 0, which cannot be filtered on
+		flightIdentifier := callback.(*dto.MonitorSeatUpdatesCallbackResponse).FlightIdentifier
+		return flightIdentifier == 0 || flightIdentifier == req.FlightIdentifier
+	case *dto.MonitorPriceUpdatesCallbackRequest:
+		return callback.(*dto.MonitorPriceUpdatesCallbackResponse).FlightIdentifier == req.FlightIdentifier
+	case *dto.MonitorFlightStatusCallbackRequest:
+		return callback.(*dto.MonitorFlightStatusCallbackResponse).FlightIdentifier == req.FlightIdentifier
+	case *dto.MonitorNewFlightsCallbackRequest:
+		// the server matches locations by any of their names, and flights are created with the canonical city name
+		flight := callback.(*dto.MonitorNewFlightsCallbackResponse).Flight
+		return flight.SourceLocation == locations.Normalise(req.SourceLocation) &&
+			flight.DestinationLocation == locations.Normalise(req.DestinationLocation)
+	}
+	return true
+}
+
+// Unsubscribe stops the callbacks of a subscription before it expires
+func (c *Client) Unsubscribe(ctx context.Context, req *dto.UnsubscribeRequest) error {
+	if err := c.call(ctx, dto.UnsubscribeRequestType, req, nil); err != nil {
+		return err
+	}
+	c.unsubscribeByIdentifier(req.SubscriptionIdentifier)
+	return nil
+}
+
+// RenewSubscription makes a subscription expire after the interval requested from now instead
+func (c *Client) RenewSubscription(ctx context.Context, req *dto.RenewSubscriptionRequest) (*dto.RenewSubscriptionResponse, error) {
+	res := &dto.RenewSubscriptionResponse{}
+	if err := c.call(ctx, dto.RenewSubscriptionRequestType, req, res); err != nil {
+		return nil, err
+	}
+	c.renewByIdentifier(req.SubscriptionIdentifier, time.Now().Add(time.Duration(req.LengthOfMonitorIntervalInSeconds)*time.Second))
+	return res, nil
+}
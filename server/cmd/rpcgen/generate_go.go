@@ -0,0 +1,269 @@
+package main
+
+import (
+	"fmt"
+	"go/format"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+// generatedHeader marks generated files so that they are not edited by hand
+const generatedHeader = "// Code generated by rpcgen from %s. DO NOT EDIT.\n\n"
+
+// goWriter builds a Go file
+type goWriter struct {
+	strings.Builder
+}
+
+func (w *goWriter) line(format string, args ...any) {
+	_, _ = fmt.Fprintf(w, format+"\n", args...)
+}
+
+// doc writes the doc comment, gofmt indents it
+func (w *goWriter) doc(doc []string) {
+	for _, line := range doc {
+		w.line("// %s", line)
+	}
+}
+
+// format gofmts the file
+func (w *goWriter) format(name string) ([]byte, error) {
+	res, err := format.Source([]byte(w.String()))
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to format %s", name)
+	}
+	return res, nil
+}
+
+// generateDTO generates the request, response and callback types, the mappings between them and every enum and message
+func generateDTO(schema *Schema, idlName string) ([]byte, error) {
+	w := &goWriter{}
+	w.line(generatedHeader+"package dto", idlName)
+	w.line("")
+	w.line(`import "github.com/cyiafn/flight_information_system/server/logs"`)
+	w.line("")
+
+	w.line("// Each of this request types correspond with a request for an RPC call. 1 - 100 are requests")
+	w.line("const (")
+	for _, rpc := range schema.RPCs {
+		if rpc.OneWay {
+			w.line("// %s is a one way request, the server does not reply to it", rpc.RequestType())
+		}
+		w.line("%s RequestType = %d", rpc.RequestType(), rpc.Number)
+	}
+	w.line(")")
+	w.line("")
+
+	w.line("// Each of these response types correspond with a response for an RPC call. 101 - 200 are responses, one way requests do not have one")
+	w.line("const (")
+	for _, rpc := range schema.RPCs {
+		if !rpc.OneWay {
+			w.line("%s ResponseType = %d", rpc.ResponseType(), rpc.Number+responseTypeOffset)
+		}
+	}
+	w.line(")")
+	w.line("")
+
+	w.line("// Each of these callback types correspond with a callback for a subscription. 201 - 300 are callback messages")
+	w.line("const (")
+	for _, rpc := range schema.Callbacks() {
+		w.line("%s ResponseType = %d", rpc.Callback.CallbackType(), rpc.Callback.Number)
+	}
+	w.line(")")
+	w.line("")
+
+	w.line("// requestToResponseMap simply maps the request to the relevant response types")
+	w.line("var requestToResponseMap = map[RequestType]ResponseType{")
+	for _, rpc := range schema.RPCs {
+		if !rpc.OneWay {
+			w.line("%s: %s,", rpc.RequestType(), rpc.ResponseType())
+		}
+	}
+	w.line("}")
+	w.line("")
+
+	w.line("// oneWayRequestTypes are requests the server does not reply to, so they do not have a response type")
+	w.line("var oneWayRequestTypes = map[RequestType]bool{")
+	for _, rpc := range schema.RPCs {
+		if rpc.OneWay {
+			w.line("%s: true,", rpc.RequestType())
+		}
+	}
+	w.line("}")
+	w.line("")
+
+	w.line("// NewRequestDTO generates a new request DTO of the request type to unmarshal the request into, nil if it has no body")
+	w.line("func NewRequestDTO(requestType RequestType) any {")
+	w.line("switch requestType {")
+	for _, rpc := range schema.RPCs {
+		w.line("case %s:", rpc.RequestType())
+		if rpc.Request == "" {
+			w.line("return nil")
+		} else {
+			w.line("return &%s{}", rpc.Request)
+		}
+	}
+	w.line("}")
+	w.line(`logs.Error("Request DTO not provided")`)
+	w.line("return nil")
+	w.line("}")
+
+	for _, enum := range schema.Enums {
+		w.line("")
+		w.doc(enum.Doc)
+		w.line("type %s uint8", enum.Name)
+		w.line("")
+		w.line("const (")
+		for i, value := range enum.Values {
+			w.doc(value.Doc)
+			if i == 0 {
+				w.line("%s %s = iota + 1", value.Name, enum.Name)
+			} else {
+				w.line("%s", value.Name)
+			}
+		}
+		w.line(")")
+	}
+
+	w.line("")
+	w.line("/*")
+	w.line("The following are request and response data transfer objects defined for each RPC call.")
+	w.line("")
+	w.line("Some RPC calls may have no response body and only return a statusCode")
+	w.line("*/")
+	for _, message := range schema.Messages {
+		w.line("")
+		w.doc(message.Doc)
+		w.line("type %s struct {", message.Name)
+		for _, field := range message.Fields {
+			w.doc(field.Doc)
+			w.line("%s %s", field.Name, goType(field))
+		}
+		w.line("}")
+	}
+	return w.format("dto")
+}
+
+// generateRoutes generates the route table of main, every RPC call is routed to the handler of the same name
+func generateRoutes(schema *Schema, idlName string) ([]byte, error) {
+	w := &goWriter{}
+	w.line(generatedHeader+"package main", idlName)
+	w.line("")
+	w.line("import (")
+	w.line(`"context"`)
+	w.line("")
+	w.line(`"github.com/cyiafn/flight_information_system/server/dto"`)
+	w.line(`"github.com/cyiafn/flight_information_system/server/handlers"`)
+	w.line(")")
+	w.line("")
+	w.line("// routes are the routes from request to handlers")
+	w.line("var routes = map[dto.RequestType]func(ctx context.Context, request any) (any, error){")
+	for _, rpc := range schema.RPCs {
+		w.line("dto.%s: handlers.%s,", rpc.RequestType(), rpc.Name)
+	}
+	w.line("}")
+	return w.format("routes")
+}
+
+// generateHandler generates a handler that is not implemented yet, for RPC calls without one. It is only generated once and
+// is not marked as generated, as it is meant to be implemented by hand.
+func generateHandler(rpc *RPC) ([]byte, error) {
+	w := &goWriter{}
+	w.line("package handlers")
+	w.line("")
+	w.line("import (")
+	w.line(`"context"`)
+	w.line("")
+	w.line(`"github.com/cyiafn/flight_information_system/server/custom_errors"`)
+	if rpc.Request != "" {
+		w.line(`"github.com/cyiafn/flight_information_system/server/dto"`)
+	}
+	w.line(")")
+	w.line("")
+	w.doc(rpc.Doc)
+	if rpc.Request == "" {
+		w.line("func %s(_ context.Context, _ any) (any, error) {", rpc.Name)
+	} else {
+		w.line("func %s(_ context.Context, request any) (any, error) {", rpc.Name)
+		w.line("_ = request.(*dto.%s)", rpc.Request)
+	}
+	w.line("")
+	w.line("// TODO: implement %s", rpc.Name)
+	w.line("return nil, custom_errors.NewBusinessLogicGenericError()")
+	w.line("}")
+	return w.format(rpc.Name)
+}
+
+// generateClientStubs generates a typed method of the Go client for each RPC call, except one way and manual RPC calls
+func generateClientStubs(schema *Schema, idlName string) ([]byte, error) {
+	w := &goWriter{}
+	w.line(generatedHeader+"package client", idlName)
+	w.line("")
+	w.line("import (")
+	w.line(`"context"`)
+	// the interval of subscriptions is converted to a duration
+	if len(schema.Callbacks()) > 0 {
+		w.line(`"time"`)
+	}
+	w.line("")
+	w.line(`"github.com/cyiafn/flight_information_system/server/dto"`)
+	w.line(")")
+	for _, rpc := range schema.RPCs {
+		if rpc.OneWay || rpc.Manual {
+			continue
+		}
+		w.line("")
+		w.doc(rpc.Doc)
+		params := "ctx context.Context"
+		req := "nil"
+		if rpc.Request != "" {
+			params += fmt.Sprintf(", req *dto.%s", rpc.Request)
+			req = "req"
+		}
+
+		switch {
+		case rpc.Callback != nil:
+			w.line("func (c *Client) %s(%s, onUpdate func(*dto.%s)) (*dto.%s, error) {", rpc.Name, params, rpc.Callback.Message, rpc.Response)
+			w.line("res := &dto.%s{}", rpc.Response)
+			w.line("interval := time.Duration(req.%s) * time.Second", monitorIntervalField)
+			w.line("if err := monitor(ctx, c, dto.%s, dto.%s, interval, req, res, &res.%s, onUpdate); err != nil {", rpc.RequestType(), rpc.Callback.CallbackType(), subscriptionIdentifierField)
+			w.line("return nil, err")
+			w.line("}")
+			w.line("return res, nil")
+		case rpc.Response == "":
+			w.line("func (c *Client) %s(%s) error {", rpc.Name, params)
+			w.line("return c.call(ctx, dto.%s, %s, nil)", rpc.RequestType(), req)
+		default:
+			w.line("func (c *Client) %s(%s) (*dto.%s, error) {", rpc.Name, params, rpc.Response)
+			w.line("res := &dto.%s{}", rpc.Response)
+			w.line("if err := c.call(ctx, dto.%s, %s, res); err != nil {", rpc.RequestType(), req)
+			w.line("return nil, err")
+			w.line("}")
+			w.line("return res, nil")
+		}
+		w.line("}")
+	}
+	return w.format("client stubs")
+}
+
+// toSnakeCase converts a name such as GetFlightInformation to get_flight_information for file names
+func toSnakeCase(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) && i > 0 {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// goType is the Go type of the field
+func goType(field *Field) string {
+	if field.Repeated {
+		return "[]" + field.Type
+	}
+	return field.Type
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// tsWriter builds a TypeScript file, indented by 2 spaces
+type tsWriter struct {
+	strings.Builder
+}
+
+func (w *tsWriter) line(format string, args ...any) {
+	_, _ = fmt.Fprintf(w, format+"\n", args...)
+}
+
+// doc writes the doc comment with the indent
+func (w *tsWriter) doc(indent string, doc []string) {
+	for _, line := range doc {
+		w.line("%s// %s", indent, line)
+	}
+}
+
+// tsWireTypes are the wire types of the primitives in the MessageSchema of marshal.ts, enums are uint8
+var tsWireTypes = map[string]string{
+	"int":     "int64",
+	"int32":   "int32",
+	"int64":   "int64",
+	"uint8":   "uint8",
+	"float64": "float64",
+	"string":  "string",
+}
+
+// tsTypes are the TypeScript types of the primitives, 64 bit integers do not fit in a number
+var tsTypes = map[string]string{
+	"int":     "bigint",
+	"int32":   "number",
+	"int64":   "bigint",
+	"uint8":   "number",
+	"float64": "number",
+	"string":  "string",
+}
+
+// generateTSDTO generates the request and response types as enums, every enum, and every message as a type along with
+// its MessageSchema to marshal it with
+func generateTSDTO(schema *Schema, idlName string) []byte {
+	w := &tsWriter{}
+	w.line(generatedHeader+"import { MessageSchema } from '../marshal';", idlName)
+	w.line("")
+
+	w.line("export enum RequestType {")
+	for i, rpc := range schema.RPCs {
+		w.line("  %s = %d%s", rpc.RequestType(), rpc.Number, separator(i, len(schema.RPCs)))
+	}
+	w.line("}")
+	w.line("")
+
+	responseTypes := make([]string, 0)
+	for _, rpc := range schema.RPCs {
+		if !rpc.OneWay {
+			responseTypes = append(responseTypes, fmt.Sprintf("%s = %d", rpc.ResponseType(), rpc.Number+responseTypeOffset))
+		}
+	}
+	for _, rpc := range schema.Callbacks() {
+		responseTypes = append(responseTypes, fmt.Sprintf("%s = %d", rpc.Callback.CallbackType(), rpc.Callback.Number))
+	}
+	w.line("export enum ResponseType {")
+	for i, responseType := range responseTypes {
+		w.line("  %s%s", responseType, separator(i, len(responseTypes)))
+	}
+	w.line("}")
+
+	for _, enum := range schema.Enums {
+		w.line("")
+		w.doc("", enum.Doc)
+		w.line("export enum %s {", enum.Name)
+		for i, value := range enum.Values {
+			w.doc("  ", value.Doc)
+			w.line("  %s = %d%s", value.Name, i+1, separator(i, len(enum.Values)))
+		}
+		w.line("}")
+	}
+
+	for _, message := range schema.Messages {
+		w.line("")
+		w.doc("", message.Doc)
+		w.line("export type %s = {", message.Name)
+		for _, field := range message.Fields {
+			w.doc("  ", field.Doc)
+			w.line("  %s: %s;", field.Name, tsType(field))
+		}
+		w.line("};")
+	}
+
+	// schemas refer to the schemas of nested messages, so they are declared after them
+	for _, message := range sortMessagesByDependency(schema) {
+		w.line("")
+		w.line("export const %sSchema: MessageSchema = [", message.Name)
+		for i, field := range message.Fields {
+			w.line("  ['%s', %s]%s", field.Name, tsWireType(schema, field), separator(i, len(message.Fields)))
+		}
+		w.line("];")
+	}
+	return []byte(w.String())
+}
+
+// generateTSStubs generates a function for each RPC call that is not one way, which sends the request with a new UDPClient
+// in the same way as stubs.ts
+func generateTSStubs(schema *Schema, idlName string) []byte {
+	imports := []string{"RequestType"}
+	for _, rpc := range schema.RPCs {
+		if !rpc.OneWay && rpc.Request != "" {
+			imports = append(imports, rpc.Request, rpc.Request+"Schema")
+		}
+	}
+
+	w := &tsWriter{}
+	w.line(generatedHeader+"import { Buffer } from 'buffer';", idlName)
+	w.line("import { UDPClient } from '../Client';")
+	w.line("import { marshalMessage } from '../marshal';")
+	w.line("import {")
+	for i, name := range imports {
+		w.line("  %s%s", name, separator(i, len(imports)))
+	}
+	w.line("} from './dto';")
+	w.line("")
+	w.line("const ip = process.env.IP || 'localhost';")
+	w.line("")
+	w.line("// sendRequest sends the payload in a single byte array buffer, callbacks are listened to for monitorTimeOut seconds")
+	w.line("function sendRequest(")
+	w.line("  requestType: RequestType,")
+	w.line("  payload: Buffer,")
+	w.line("  monitorTimeOut?: number")
+	w.line(") {")
+	w.line("  const client = new UDPClient(ip, 8080);")
+	w.line("  if (monitorTimeOut !== undefined) {")
+	w.line("    client.monitorTimeOut = monitorTimeOut;")
+	w.line("  }")
+	w.line("  return client.sendRequests({")
+	w.line("    payload: payload,")
+	w.line("    requestType: requestType,")
+	w.line("    byteArrayBufferNo: 1,")
+	w.line("    totalByteArrayBuffers: 1")
+	w.line("  });")
+	w.line("}")
+
+	for _, rpc := range schema.RPCs {
+		if rpc.OneWay {
+			continue
+		}
+		w.line("")
+		w.doc("", rpc.Doc)
+		if rpc.Request == "" {
+			w.line("export function %s() {", lowerFirst(rpc.Name))
+			w.line("  return sendRequest(RequestType.%s, Buffer.alloc(0));", rpc.RequestType())
+			w.line("}")
+			continue
+		}
+		w.line("export function %s(req: %s) {", lowerFirst(rpc.Name), rpc.Request)
+		w.line("  return sendRequest(")
+		w.line("    RequestType.%s,", rpc.RequestType())
+		if rpc.Callback != nil {
+			w.line("    marshalMessage(%sSchema, req),", rpc.Request)
+			w.line("    Number(req.%s)", monitorIntervalField)
+		} else {
+			w.line("    marshalMessage(%sSchema, req)", rpc.Request)
+		}
+		w.line("  );")
+		w.line("}")
+	}
+	return []byte(w.String())
+}
+
+// tsType is the TypeScript type of the field
+func tsType(field *Field) string {
+	res, ok := tsTypes[field.Type]
+	if !ok {
+		// enums and messages are declared with the same name
+		res = field.Type
+	}
+	if field.Repeated {
+		return res + "[]"
+	}
+	return res
+}
+
+// tsWireType is the FieldType of the field in a MessageSchema
+func tsWireType(schema *Schema, field *Field) string {
+	res, ok := tsWireTypes[field.Type]
+	switch {
+	case ok:
+		res = fmt.Sprintf("'%s'", res)
+	case schema.Enum(field.Type) != nil:
+		res = "'uint8'"
+	default:
+		res = fmt.Sprintf("{ message: %sSchema }", field.Type)
+	}
+	if field.Repeated {
+		return fmt.Sprintf("{ array: %s }", res)
+	}
+	return res
+}
+
+// sortMessagesByDependency sorts the messages such that every message comes after the messages of its fields, otherwise
+// in the order they are declared
+func sortMessagesByDependency(schema *Schema) []*Message {
+	res := make([]*Message, 0, len(schema.Messages))
+	visited := make(map[string]bool)
+	var visit func(message *Message)
+	visit = func(message *Message) {
+		if visited[message.Name] {
+			return
+		}
+		visited[message.Name] = true
+		for _, field := range message.Fields {
+			if nested := schema.Message(field.Type); nested != nil {
+				visit(nested)
+			}
+		}
+		res = append(res, message)
+	}
+	for _, message := range schema.Messages {
+		visit(message)
+	}
+	return res
+}
+
+// separator is the comma after every element of a list except the last
+func separator(i int, length int) string {
+	if i == length-1 {
+		return ""
+	}
+	return ","
+}
+
+// lowerFirst converts a name such as GetFlightInformation to getFlightInformation for TypeScript functions
+func lowerFirst(name string) string {
+	runes := []rune(name)
+	runes[0] = unicode.ToLower(runes[0])
+	return string(runes)
+}
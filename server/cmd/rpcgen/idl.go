@@ -0,0 +1,414 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+/**
+The IDL is line based. Comment lines (//) right before a declaration, enum value or field are its doc comment, a blank line
+in between drops them.
+
+	// TimeFormatType is how times are rendered
+	enum TimeFormatType {
+		UnixTimeFormat
+		LocalTimeFormat
+	}
+
+	message GetFlightInformationRequest {
+		FlightIdentifier int32
+		TimeFormat       TimeFormatType
+		SeatLabels       []string
+	}
+
+	rpc Ping = 1 () returns ()
+	rpc GetFlightInformation = 3 (GetFlightInformationRequest) returns (GetFlightInformationResponse)
+	rpc MonitorSeatUpdates = 5 (MonitorSeatUpdatesCallbackRequest) returns (MonitorSeatUpdatesResponse) callback MonitorSeatUpdates = 201 (MonitorSeatUpdatesCallbackResponse)
+	rpc AckCallback = 24 (AckCallbackRequest) oneway
+	rpc Unsubscribe = 26 (UnsubscribeRequest) returns () manual
+
+Enum values start from 1 in order. Field types are int, int32, int64, uint8, float64, string, enums, messages, and slices
+of them. The number of an RPC is its request type, its response type is the number + 100 and the number of its callback
+is the callback type. An empty request or response means the RPC call has no body. oneway RPC calls are not replied to and
+manual RPC calls have hand-written Go client stubs.
+*/
+
+const (
+	// maxRequestType is the largest request type, response types start after it
+	maxRequestType = 100
+	// responseTypeOffset is the difference between the request type and response type of an RPC call
+	responseTypeOffset = 100
+	// minCallbackType and maxCallbackType bound the callback types
+	minCallbackType = 201
+	maxCallbackType = 300
+	// monitorIntervalField is the field of the requests of RPC calls with callbacks that the subscription lasts for
+	monitorIntervalField = "LengthOfMonitorIntervalInSeconds"
+	// subscriptionIdentifierField is the field of the responses of RPC calls with callbacks that identifies the subscription
+	subscriptionIdentifierField = "SubscriptionIdentifier"
+)
+
+// primitiveTypes are the types supported by the marshallers
+var primitiveTypes = map[string]bool{
+	"int":     true,
+	"int32":   true,
+	"int64":   true,
+	"uint8":   true,
+	"float64": true,
+	"string":  true,
+}
+
+var (
+	enumPattern    = regexp.MustCompile(`^enum (\w+) \{$`)
+	messagePattern = regexp.MustCompile(`^message (\w+) \{$`)
+	fieldPattern   = regexp.MustCompile(`^(\w+)\s+(\[\])?(\w+)$`)
+	valuePattern   = regexp.MustCompile(`^(\w+)$`)
+	rpcPattern     = regexp.MustCompile(`^rpc (\w+) = (\d+) \((\w*)\)(?: returns \((\w*)\))?(?: callback (\w+) = (\d+) \((\w+)\))?( oneway)?( manual)?$`)
+)
+
+// Schema is every declaration of an IDL file
+type Schema struct {
+	Enums    []*Enum
+	Messages []*Message
+	RPCs     []*RPC
+}
+
+// Enum is an enum of uint8 values starting from 1
+type Enum struct {
+	Name   string
+	Doc    []string
+	Values []*EnumValue
+}
+
+type EnumValue struct {
+	Name string
+	Doc  []string
+}
+
+// Message is a structure sent over the wire, Fields are in the order they are marshalled in
+type Message struct {
+	Name   string
+	Doc    []string
+	Fields []*Field
+}
+
+type Field struct {
+	Name string
+	Doc  []string
+	// Type is a primitive type, enum or message
+	Type string
+	// Repeated is true for slices of Type
+	Repeated bool
+}
+
+// RPC is an RPC call, Request and Response are empty if they have no body
+type RPC struct {
+	Name     string
+	Doc      []string
+	Number   int
+	Request  string
+	Response string
+	OneWay   bool
+	Manual   bool
+	Callback *Callback
+}
+
+// Callback is the callback sent to subscribers of an RPC call
+type Callback struct {
+	Name    string
+	Number  int
+	Message string
+}
+
+// RequestType is the name of the request type constant
+func (r *RPC) RequestType() string {
+	return r.Name + "RequestType"
+}
+
+// ResponseType is the name of the response type constant
+func (r *RPC) ResponseType() string {
+	return r.Name + "ResponseType"
+}
+
+// CallbackType is the name of the callback type constant
+func (c *Callback) CallbackType() string {
+	return c.Name + "CallbackType"
+}
+
+// Message gets the message of the name, nil if there is no such message
+func (s *Schema) Message(name string) *Message {
+	for _, message := range s.Messages {
+		if message.Name == name {
+			return message
+		}
+	}
+	return nil
+}
+
+// Enum gets the enum of the name, nil if there is no such enum
+func (s *Schema) Enum(name string) *Enum {
+	for _, enum := range s.Enums {
+		if enum.Name == name {
+			return enum
+		}
+	}
+	return nil
+}
+
+// Callbacks are the RPC calls with callbacks in order of callback type
+func (s *Schema) Callbacks() []*RPC {
+	res := make([]*RPC, 0)
+	for _, rpc := range s.RPCs {
+		if rpc.Callback != nil {
+			res = append(res, rpc)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Callback.Number < res[j].Callback.Number })
+	return res
+}
+
+// Parse parses and validates an IDL file, RPCs are sorted by request type
+func Parse(r io.Reader) (*Schema, error) {
+	p := &idlParser{scanner: bufio.NewScanner(r), schema: &Schema{}}
+	if err := p.parse(); err != nil {
+		return nil, err
+	}
+	sort.Slice(p.schema.RPCs, func(i, j int) bool { return p.schema.RPCs[i].Number < p.schema.RPCs[j].Number })
+	if err := p.schema.validate(); err != nil {
+		return nil, err
+	}
+	return p.schema, nil
+}
+
+// idlParser parses an IDL file line by line
+type idlParser struct {
+	scanner *bufio.Scanner
+	schema  *Schema
+	// lineNo is the current line number for errors
+	lineNo int
+	// doc is the doc comment of the next declaration
+	doc []string
+}
+
+// next gets the next line trimmed, returns false at the end of the file
+func (p *idlParser) next() (string, bool) {
+	if !p.scanner.Scan() {
+		return "", false
+	}
+	p.lineNo += 1
+	return strings.TrimSpace(p.scanner.Text()), true
+}
+
+// takeDoc returns the doc comment collected and starts a new one
+func (p *idlParser) takeDoc() []string {
+	doc := p.doc
+	p.doc = nil
+	return doc
+}
+
+// readComment collects the line if it is a comment or drops the doc comment if it is blank, returns true for either
+func (p *idlParser) readComment(line string) bool {
+	if line == "" {
+		p.doc = nil
+		return true
+	}
+	if strings.HasPrefix(line, "//") {
+		p.doc = append(p.doc, strings.TrimSpace(strings.TrimPrefix(line, "//")))
+		return true
+	}
+	return false
+}
+
+func (p *idlParser) errorf(format string, args ...any) error {
+	return errors.Errorf("line %d: %s", p.lineNo, fmt.Sprintf(format, args...))
+}
+
+func (p *idlParser) parse() error {
+	for {
+		line, ok := p.next()
+		if !ok {
+			return p.scanner.Err()
+		}
+		if p.readComment(line) {
+			continue
+		}
+
+		if match := enumPattern.FindStringSubmatch(line); match != nil {
+			enum := &Enum{Name: match[1], Doc: p.takeDoc()}
+			if err := p.parseBody(func(line string) error {
+				value := valuePattern.FindStringSubmatch(line)
+				if value == nil {
+					return p.errorf("malformed enum value: %s", line)
+				}
+				enum.Values = append(enum.Values, &EnumValue{Name: value[1], Doc: p.takeDoc()})
+				return nil
+			}); err != nil {
+				return err
+			}
+			p.schema.Enums = append(p.schema.Enums, enum)
+			continue
+		}
+
+		if match := messagePattern.FindStringSubmatch(line); match != nil {
+			message := &Message{Name: match[1], Doc: p.takeDoc()}
+			if err := p.parseBody(func(line string) error {
+				field := fieldPattern.FindStringSubmatch(line)
+				if field == nil {
+					return p.errorf("malformed field: %s", line)
+				}
+				message.Fields = append(message.Fields, &Field{Name: field[1], Doc: p.takeDoc(), Type: field[3], Repeated: field[2] != ""})
+				return nil
+			}); err != nil {
+				return err
+			}
+			p.schema.Messages = append(p.schema.Messages, message)
+			continue
+		}
+
+		if match := rpcPattern.FindStringSubmatch(line); match != nil {
+			rpc, err := p.newRPC(match)
+			if err != nil {
+				return err
+			}
+			p.schema.RPCs = append(p.schema.RPCs, rpc)
+			continue
+		}
+
+		return p.errorf("unknown declaration: %s", line)
+	}
+}
+
+// parseBody parses every line until the closing brace with parseLine, skipping comments
+func (p *idlParser) parseBody(parseLine func(line string) error) error {
+	for {
+		line, ok := p.next()
+		if !ok {
+			return p.errorf("missing closing brace")
+		}
+		if line == "}" {
+			p.doc = nil
+			return nil
+		}
+		if p.readComment(line) {
+			continue
+		}
+		if err := parseLine(line); err != nil {
+			return err
+		}
+	}
+}
+
+// newRPC makes the RPC from the submatches of rpcPattern
+func (p *idlParser) newRPC(match []string) (*RPC, error) {
+	number, _ := strconv.Atoi(match[2])
+	rpc := &RPC{
+		Name:     match[1],
+		Doc:      p.takeDoc(),
+		Number:   number,
+		Request:  match[3],
+		Response: match[4],
+		OneWay:   match[8] != "",
+		Manual:   match[9] != "",
+	}
+	// the returns clause is the only way to tell an empty response from a one way call
+	hasReturns := strings.Contains(match[0], " returns (")
+	if rpc.OneWay == hasReturns {
+		return nil, p.errorf("rpc %s must either return a response or be oneway", rpc.Name)
+	}
+	if match[5] != "" {
+		callbackNumber, _ := strconv.Atoi(match[6])
+		rpc.Callback = &Callback{Name: match[5], Number: callbackNumber, Message: match[7]}
+	}
+	return rpc, nil
+}
+
+// validate checks that every type referenced is declared and every name and number is unique
+func (s *Schema) validate() error {
+	names := make(map[string]bool)
+	for _, enum := range s.Enums {
+		if names[enum.Name] || primitiveTypes[enum.Name] {
+			return errors.Errorf("%s is declared more than once", enum.Name)
+		}
+		names[enum.Name] = true
+		if len(enum.Values) == 0 {
+			return errors.Errorf("enum %s has no values", enum.Name)
+		}
+	}
+	for _, message := range s.Messages {
+		if names[message.Name] || primitiveTypes[message.Name] {
+			return errors.Errorf("%s is declared more than once", message.Name)
+		}
+		names[message.Name] = true
+	}
+	for _, message := range s.Messages {
+		fields := make(map[string]bool)
+		for _, field := range message.Fields {
+			if fields[field.Name] {
+				return errors.Errorf("field %s of %s is declared more than once", field.Name, message.Name)
+			}
+			fields[field.Name] = true
+			if !primitiveTypes[field.Type] && !names[field.Type] {
+				return errors.Errorf("unknown type %s of field %s of %s", field.Type, field.Name, message.Name)
+			}
+		}
+	}
+
+	rpcNames := make(map[string]bool)
+	numbers := make(map[int]bool)
+	for _, rpc := range s.RPCs {
+		if rpcNames[rpc.Name] {
+			return errors.Errorf("rpc %s is declared more than once", rpc.Name)
+		}
+		rpcNames[rpc.Name] = true
+		if rpc.Number < 1 || rpc.Number > maxRequestType || numbers[rpc.Number] {
+			return errors.Errorf("rpc %s must have a unique number from 1 to %d", rpc.Name, maxRequestType)
+		}
+		numbers[rpc.Number] = true
+		for _, message := range []string{rpc.Request, rpc.Response} {
+			if message != "" && s.Message(message) == nil {
+				return errors.Errorf("unknown message %s of rpc %s", message, rpc.Name)
+			}
+		}
+		if err := s.validateCallback(rpc, numbers); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateCallback checks that the RPC call subscribes for an interval and returns a subscription identifier if it has a callback
+func (s *Schema) validateCallback(rpc *RPC, numbers map[int]bool) error {
+	if rpc.Callback == nil {
+		return nil
+	}
+	if rpc.Callback.Number < minCallbackType || rpc.Callback.Number > maxCallbackType || numbers[rpc.Callback.Number] {
+		return errors.Errorf("callback of rpc %s must have a unique number from %d to %d", rpc.Name, minCallbackType, maxCallbackType)
+	}
+	numbers[rpc.Callback.Number] = true
+	if s.Message(rpc.Callback.Message) == nil {
+		return errors.Errorf("unknown message %s of callback of rpc %s", rpc.Callback.Message, rpc.Name)
+	}
+	if rpc.Request == "" || !s.Message(rpc.Request).hasField(monitorIntervalField, "int64") {
+		return errors.Errorf("request of rpc %s with a callback must have the field %s int64", rpc.Name, monitorIntervalField)
+	}
+	if rpc.Response == "" || !s.Message(rpc.Response).hasField(subscriptionIdentifierField, "int64") {
+		return errors.Errorf("response of rpc %s with a callback must have the field %s int64", rpc.Name, subscriptionIdentifierField)
+	}
+	return nil
+}
+
+// hasField checks if the message has the field of the type
+func (m *Message) hasField(name string, fieldType string) bool {
+	for _, field := range m.Fields {
+		if field.Name == name && field.Type == fieldType && !field.Repeated {
+			return true
+		}
+	}
+	return false
+}
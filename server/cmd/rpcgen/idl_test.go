@@ -0,0 +1,68 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testIDL = `
+message MonitorRequest {
+	LengthOfMonitorIntervalInSeconds int64
+}
+
+message MonitorResponse {
+	SubscriptionIdentifier int64
+}
+
+message Update {
+	Seats []int32
+}
+`
+
+func TestParse(t *testing.T) {
+	schema, err := Parse(strings.NewReader(testIDL + `
+// Monitor subscribes to updates
+rpc Monitor = 1 (MonitorRequest) returns (MonitorResponse) callback Monitor = 201 (Update)
+rpc Ack = 2 (Update) oneway
+`))
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(schema.Messages))
+	assert.Equal(t, []string{"Monitor subscribes to updates"}, schema.RPCs[0].Doc)
+	assert.Equal(t, 201, schema.RPCs[0].Callback.Number)
+	assert.True(t, schema.Message("Update").Fields[0].Repeated)
+	assert.True(t, schema.RPCs[1].OneWay)
+	assert.Equal(t, []*RPC{schema.RPCs[0]}, schema.Callbacks())
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := map[string]string{
+		"unknown type":                   "message Request {\n\tField Unknown\n}",
+		"missing closing brace":          "message Request {\n\tField int32",
+		"duplicate number":               "rpc Ping = 1 () returns ()\nrpc Pong = 1 () returns ()",
+		"number out of range":            "rpc Ping = 101 () returns ()",
+		"callback without interval":      "rpc Monitor = 1 (Update) returns (MonitorResponse) callback Monitor = 201 (Update)",
+		"callback without subscription":  "rpc Monitor = 1 (MonitorRequest) returns (Update) callback Monitor = 201 (Update)",
+		"callback number out of range":   "rpc Monitor = 1 (MonitorRequest) returns (MonitorResponse) callback Monitor = 1 (Update)",
+		"neither returns nor oneway":     "rpc Ping = 1 ()",
+		"oneway with returns":            "rpc Ping = 1 () returns () oneway",
+		"unknown message of rpc request": "rpc Ping = 1 (Unknown) returns ()",
+	}
+	for name, idl := range tests {
+		_, err := Parse(strings.NewReader(testIDL + idl))
+		assert.NotNil(t, err, name)
+	}
+}
+
+// TestGeneratedFilesUpToDate checks that the IDL was not changed without running go generate ./dto
+func TestGeneratedFilesUpToDate(t *testing.T) {
+	files, err := generate("../../dto/flight_information_system.idl", "../..", "../../../Client/src")
+	assert.Nil(t, err)
+	for _, file := range files {
+		content, err := os.ReadFile(file.Path)
+		assert.Nil(t, err, file.Path)
+		assert.Equal(t, string(file.Content), string(content), "%s is out of date, run go generate ./dto", file.Path)
+	}
+}
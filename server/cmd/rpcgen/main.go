@@ -0,0 +1,122 @@
+package main
+
+import (
+	"flag"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+
+	"github.com/cyiafn/flight_information_system/server/logs"
+	"github.com/pkg/errors"
+)
+
+/**
+rpcgen generates everything that has to change when an RPC call is added from the IDL in dto, so that adding an RPC call is
+one edit to the IDL and a handler. It is run with `go generate ./dto` from the server and generates:
+ 1. dto/dto_gen.go, the request, response and callback types, their mappings, NewRequestDTO and every enum and message
+ 2. routes_gen.go, the route table of main to the handler of the same name as the RPC call
+ 3. handlers/<rpc_call>.go, a handler that is not implemented yet for RPC calls without a handler
+ 4. client/stubs_gen.go, the Go client stubs
+ 5. generated/dto.ts and generated/stubs.ts of the TypeScript client, the types and stubs
+*/
+
+// generatedFile is a file generated from the IDL
+type generatedFile struct {
+	Path    string
+	Content []byte
+}
+
+func main() {
+	idlPath := flag.String("idl", "flight_information_system.idl", "path of the IDL file")
+	serverDir := flag.String("server", "..", "root directory of the server module")
+	tsDir := flag.String("ts", "../../Client/src", "source directory of the TypeScript client, not generated if empty")
+	flag.Parse()
+
+	files, err := generate(*idlPath, *serverDir, *tsDir)
+	if err != nil {
+		logs.Fatal("unable to generate from %s, err: %v", *idlPath, err)
+	}
+	for _, file := range files {
+		if err := os.MkdirAll(filepath.Dir(file.Path), 0o755); err != nil {
+			logs.Fatal("unable to create directory of %s, err: %v", file.Path, err)
+		}
+		if err := os.WriteFile(file.Path, file.Content, 0o644); err != nil {
+			logs.Fatal("unable to write %s, err: %v", file.Path, err)
+		}
+		logs.Info("generated %s", file.Path)
+	}
+}
+
+// generate parses the IDL and generates every file from it
+func generate(idlPath string, serverDir string, tsDir string) ([]generatedFile, error) {
+	f, err := os.Open(idlPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	schema, err := Parse(f)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid IDL")
+	}
+	idlName := filepath.Base(idlPath)
+
+	files := make([]generatedFile, 0)
+	for _, generator := range []struct {
+		Path     string
+		Generate func(*Schema, string) ([]byte, error)
+	}{
+		{Path: filepath.Join(serverDir, "dto", "dto_gen.go"), Generate: generateDTO},
+		{Path: filepath.Join(serverDir, "routes_gen.go"), Generate: generateRoutes},
+		{Path: filepath.Join(serverDir, "client", "stubs_gen.go"), Generate: generateClientStubs},
+	} {
+		content, err := generator.Generate(schema, idlName)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, generatedFile{Path: generator.Path, Content: content})
+	}
+
+	handlers, err := getHandlers(filepath.Join(serverDir, "handlers"))
+	if err != nil {
+		return nil, err
+	}
+	for _, rpc := range schema.RPCs {
+		if handlers[rpc.Name] {
+			continue
+		}
+		content, err := generateHandler(rpc)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, generatedFile{Path: filepath.Join(serverDir, "handlers", toSnakeCase(rpc.Name)+".go"), Content: content})
+	}
+
+	if tsDir != "" {
+		files = append(files,
+			generatedFile{Path: filepath.Join(tsDir, "generated", "dto.ts"), Content: generateTSDTO(schema, idlName)},
+			generatedFile{Path: filepath.Join(tsDir, "generated", "stubs.ts"), Content: generateTSStubs(schema, idlName)},
+		)
+	}
+	return files, nil
+}
+
+// getHandlers gets the names of every function of the handlers package
+func getHandlers(handlersDir string) (map[string]bool, error) {
+	packages, err := parser.ParseDir(token.NewFileSet(), handlersDir, nil, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to parse handlers")
+	}
+	res := make(map[string]bool)
+	for _, pkg := range packages {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil {
+					res[fn.Name.Name] = true
+				}
+			}
+		}
+	}
+	return res, nil
+}
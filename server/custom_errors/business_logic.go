@@ -9,15 +9,26 @@ Most errors here are self-explanatory.
 
 import "fmt"
 
+// NoMatchForSourceAndDestinationError contains "did you mean" suggestions for source and destination locations that are
+// not known, suggestions are empty if the location is known or nothing is close to it.
 type NoMatchForSourceAndDestinationError struct {
+	SourceSuggestions      []string
+	DestinationSuggestions []string
 }
 
 func (m *NoMatchForSourceAndDestinationError) Error() string {
-	return fmt.Sprintf("No flights match for source and destination locations!")
+	if len(m.SourceSuggestions) == 0 && len(m.DestinationSuggestions) == 0 {
+		return fmt.Sprintf("No flights match for source and destination locations!")
+	}
+	return fmt.Sprintf("No flights match for source and destination locations! Did you mean source: %v, destination: %v?", m.SourceSuggestions, m.DestinationSuggestions)
 }
 
-func NewNoMatchForSourceAndDestinationError() error {
-	return &NoMatchForSourceAndDestinationError{}
+func (m *NoMatchForSourceAndDestinationError) Details() any {
+	return m
+}
+
+func NewNoMatchForSourceAndDestinationError(sourceSuggestions, destinationSuggestions []string) error {
+	return &NoMatchForSourceAndDestinationError{SourceSuggestions: sourceSuggestions, DestinationSuggestions: destinationSuggestions}
 }
 
 type NoSuchFlightIdentifierError struct {
@@ -41,3 +52,110 @@ func (m *InsufficientNumberOfAvailableSeatsError) Error() string {
 func NewInsufficientNumberOfAvailableSeatsError() error {
 	return &InsufficientNumberOfAvailableSeatsError{}
 }
+
+type NoSuchBookingIdentifierError struct {
+}
+
+func (m *NoSuchBookingIdentifierError) Error() string {
+	return fmt.Sprintf("booking identifier provided does not exist")
+}
+
+func NewNoSuchBookingIdentifierError() error {
+	return &NoSuchBookingIdentifierError{}
+}
+
+type NoSuchHoldTokenError struct {
+}
+
+func (m *NoSuchHoldTokenError) Error() string {
+	return fmt.Sprintf("hold token provided does not exist or the hold has expired")
+}
+
+func NewNoSuchHoldTokenError() error {
+	return &NoSuchHoldTokenError{}
+}
+
+type NoSuchScheduleIdentifierError struct {
+}
+
+func (m *NoSuchScheduleIdentifierError) Error() string {
+	return fmt.Sprintf("schedule identifier provided does not exist")
+}
+
+func NewNoSuchScheduleIdentifierError() error {
+	return &NoSuchScheduleIdentifierError{}
+}
+
+type NoSuchSubscriptionIdentifierError struct {
+}
+
+func (m *NoSuchSubscriptionIdentifierError) Error() string {
+	return fmt.Sprintf("subscription identifier provided does not exist or the subscription has expired")
+}
+
+func NewNoSuchSubscriptionIdentifierError() error {
+	return &NoSuchSubscriptionIdentifierError{}
+}
+
+// FlightNotBookableError is returned when seats are reserved or held on a flight that has been cancelled or has departed.
+// Status is the value of dao.FlightStatusType of the flight.
+type FlightNotBookableError struct {
+	Status uint8
+}
+
+func (m *FlightNotBookableError) Error() string {
+	return fmt.Sprintf("flight is not open for reservations, flight status: %d", m.Status)
+}
+
+func (m *FlightNotBookableError) Details() any {
+	return m
+}
+
+func NewFlightNotBookableError(status uint8) error {
+	return &FlightNotBookableError{Status: status}
+}
+
+// SeatsUnavailableError lists the seats requested that are not free or do not exist on the flight.
+type SeatsUnavailableError struct {
+	SeatLabels []string
+}
+
+func (m *SeatsUnavailableError) Error() string {
+	return fmt.Sprintf("seats requested are unavailable: %v", m.SeatLabels)
+}
+
+func (m *SeatsUnavailableError) Details() any {
+	return m
+}
+
+func NewSeatsUnavailableError(seatLabels []string) error {
+	return &SeatsUnavailableError{SeatLabels: seatLabels}
+}
+
+// InvalidRequestError is returned when the request fails validation, Reason is returned to the user.
+type InvalidRequestError struct {
+	Reason string
+}
+
+func (m *InvalidRequestError) Error() string {
+	return fmt.Sprintf("invalid request: %s", m.Reason)
+}
+
+func (m *InvalidRequestError) Details() any {
+	return m
+}
+
+func NewInvalidRequestError(reason string) error {
+	return &InvalidRequestError{Reason: reason}
+}
+
+type BusinessLogicGenericError struct {
+}
+
+func (m *BusinessLogicGenericError) Error() string {
+	return fmt.Sprintf("server was unable to process the request")
+}
+
+func NewBusinessLogicGenericError() error {
+	return &BusinessLogicGenericError{}
+}
@@ -0,0 +1,8 @@
+package custom_errors
+
+// DetailedError is an error that returns more than just a statusCode to the client. Details must be a pointer to a
+// structure, it is marshalled as the Data of the response on the server and unmarshalled into on the client.
+type DetailedError interface {
+	error
+	Details() any
+}
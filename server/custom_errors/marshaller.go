@@ -20,3 +20,23 @@ func (m *MarshallerError) Error() string {
 func NewMarshallerError(err error) error {
 	return &MarshallerError{err: err}
 }
+
+// DecodeError is returned when a payload cannot be unmarshalled, e.g. when it is truncated. FieldPath is the field that
+// was being unmarshalled, e.g. Flights[2].SourceLocation, empty if it is not known, and Offset is the index of the byte
+// of the payload it failed at.
+type DecodeError struct {
+	FieldPath string
+	Offset    int
+	Reason    string
+}
+
+func (m *DecodeError) Error() string {
+	if m.FieldPath == "" {
+		return fmt.Sprintf("unable to decode payload at byte %d: %s", m.Offset, m.Reason)
+	}
+	return fmt.Sprintf("unable to decode field %s at byte %d: %s", m.FieldPath, m.Offset, m.Reason)
+}
+
+func NewDecodeError(offset int, reason string) error {
+	return &DecodeError{Offset: offset, Reason: reason}
+}
@@ -1,12 +1,51 @@
 package dao
 
+// MaxTotalSeats caps the seats of a flight, as every seat of a flight is stored when the flight is created
+const MaxTotalSeats = 1000
+
+// FlightStatusType is the status of a flight in its lifecycle
+type FlightStatusType uint8
+
+// Flight statuses, cancelled and departed are final as a flight cannot change status after either
+const (
+	ScheduledFlightStatus FlightStatusType = iota + 1
+	DelayedFlightStatus
+	CancelledFlightStatus
+	DepartedFlightStatus
+)
+
+// IsFinal checks if a flight with this status can no longer change status
+func (s FlightStatusType) IsFinal() bool {
+	return s == CancelledFlightStatus || s == DepartedFlightStatus
+}
+
 // Flight is the data access object we have for storing information about flights. Generally this will be linked to a db,
-// however, for the sake of simplicity, we have stored the data in memory.
+// however, for the sake of simplicity, we have stored the data in memory by default.
 type Flight struct {
-	FlightIdentifier    int32
+	FlightIdentifier    int32 `gorm:"primaryKey"`
 	SourceLocation      string
 	DestinationLocation string
 	DepartureTime       int64
 	Airfare             float64
 	TotalAvailableSeats int32
+	// ArrivalTime is the unix time the flight arrives at the destination, always after DepartureTime
+	ArrivalTime int64
+	// SourceTimezone and DestinationTimezone are the IANA timezones of the airports, e.g. Asia/Singapore
+	SourceTimezone      string
+	DestinationTimezone string
+	Aircraft            string
+	// ScheduleIdentifier is the schedule the flight departs under, 0 for flights created on their own
+	ScheduleIdentifier int32 `gorm:"index"`
+	// Status is the lifecycle status of the flight, seats can only be reserved or held while it is not final
+	Status FlightStatusType
+	// SeatsSequenceNumber, AirfareSequenceNumber and StatusSequenceNumber are incremented with every change to the
+	// TotalAvailableSeats, Airfare and Status (along with times) of the flight, in the same write as the change
+	SeatsSequenceNumber   int64
+	AirfareSequenceNumber int64
+	StatusSequenceNumber  int64
+}
+
+// IsBookable checks if seats on the flight can be reserved or held
+func (f *Flight) IsBookable() bool {
+	return !f.Status.IsFinal()
 }
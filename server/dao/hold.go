@@ -0,0 +1,15 @@
+package dao
+
+// Hold is the data access object for seats held on a flight for a limited time. A hold is either confirmed into a
+// Reservation or released once it expires.
+type Hold struct {
+	HoldToken        string `gorm:"primaryKey"`
+	FlightIdentifier int32  `gorm:"index"`
+	SeatsHeld        int32
+	// ClientAddress is the IP:port of the client that made the hold
+	ClientAddress string
+	// ExpiryTime is the unix time after which the hold can no longer be confirmed
+	ExpiryTime int64
+	// SeatLabels are the seats held under this hold, these are stored on the seats themselves
+	SeatLabels []string `gorm:"-"`
+}
@@ -0,0 +1,14 @@
+package dao
+
+// Reservation is the data access object for a seat reservation made on a flight.
+type Reservation struct {
+	BookingIdentifier int32 `gorm:"primaryKey"`
+	FlightIdentifier  int32 `gorm:"index"`
+	SeatsReserved     int32
+	// ClientAddress is the IP:port of the client that made the reservation
+	ClientAddress string
+	// ReservationTime is the unix time the reservation was made
+	ReservationTime int64
+	// SeatLabels are the seats booked under this reservation, these are stored on the seats themselves
+	SeatLabels []string `gorm:"-"`
+}
@@ -0,0 +1,22 @@
+package dao
+
+// Schedule is the data access object for a recurring flight, e.g. a daily flight from Singapore to Tokyo. A flight is
+// stored for every departure of the schedule.
+type Schedule struct {
+	ScheduleIdentifier  int32 `gorm:"primaryKey"`
+	SourceLocation      string
+	DestinationLocation string
+	SourceTimezone      string
+	DestinationTimezone string
+	// DaysOfWeek is a bitmask of the days flights depart on, bit 0 is Sunday and bit 6 is Saturday as with time.Weekday
+	DaysOfWeek uint8
+	// LocalDepartureTime is the time of day flights depart in the source timezone, e.g. 08:30
+	LocalDepartureTime string
+	DurationInSeconds  int64
+	// StartDate and EndDate are the inclusive dates of the first and last departures, e.g. 2023-12-01
+	StartDate  string
+	EndDate    string
+	Airfare    float64
+	TotalSeats int32
+	Aircraft   string
+}
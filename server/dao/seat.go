@@ -0,0 +1,34 @@
+package dao
+
+// CabinClassType is the cabin class of a seat
+type CabinClassType uint8
+
+const (
+	EconomyCabinClass CabinClassType = iota + 1
+	BusinessCabinClass
+)
+
+// SeatStatusType is the status of a seat
+type SeatStatusType uint8
+
+// Seat statuses, a seat is only available for reservation if it is free
+const (
+	FreeSeatStatus SeatStatusType = iota + 1
+	HeldSeatStatus
+	BookedSeatStatus
+)
+
+// Seat is the data access object for a single seat on a flight, e.g. seat 12A. The TotalAvailableSeats of a flight is
+// always the number of free seats of that flight.
+type Seat struct {
+	FlightIdentifier int32  `gorm:"primaryKey"`
+	SeatLabel        string `gorm:"primaryKey"`
+	SeatRow          int32
+	SeatLetter       string
+	CabinClass       CabinClassType
+	Status           SeatStatusType
+	// BookingIdentifier is the reservation the seat is booked under, 0 if it is not booked
+	BookingIdentifier int32 `gorm:"index"`
+	// HoldToken is the hold the seat is held under, empty if it is not held
+	HoldToken string `gorm:"index"`
+}
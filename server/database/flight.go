@@ -1,84 +1,191 @@
 package database
 
-import "github.com/cyiafn/flight_information_system/server/dao"
+import (
+	"fmt"
 
-/*
-Note: Query is a linear scan, it is not efficient, but this is not the focus of this project
+	"github.com/cyiafn/flight_information_system/server/custom_errors"
+	"github.com/cyiafn/flight_information_system/server/dao"
+)
 
-Note that this "database" is not fully concurrent-safe, usually this is delegated to the actual database.
+/**
+The flight store (and the reservations made on flights) is accessed through the FlightRepository interface so that the
+in-memory "db" can be swapped for an actual database on boot. All flights returned by a FlightRepository are copies,
+changes to a flight must go through the repository so that they are persisted (and so that checks such as seat
+availability are done atomically). Every change to the seats, airfare or status of a flight increments the sequence
+number of that change in the same write, so that the sequence numbers subscribers are notified with are in the order
+the changes were made.
 */
 
-// flights is our in-memory "db" to store flights
-var flights []*dao.Flight
+// FlightRepository is the interface to the store of flights
+type FlightRepository interface {
+	// GetFlight gets a flight by its flight identifier, returns NoSuchFlightIdentifierError if it does not exist
+	GetFlight(flightIdentifier int32) (*dao.Flight, error)
+	// GetAllFlights gets all flights ordered by flight identifier
+	GetAllFlights() ([]*dao.Flight, error)
+	// GetFlightsBySourceAndDestination gets all flights from source to destination ordered by flight identifier
+	GetFlightsBySourceAndDestination(sourceLocation, destinationLocation string) ([]*dao.Flight, error)
+	// SearchFlights gets the page of flights matching the criteria, along with the total number of matching flights
+	SearchFlights(criteria *FlightSearchCriteria) ([]*dao.Flight, int32, error)
+	// CreateFlight emulates an insert with an auto-incrementing PK, returns the flight identifier of the new flight.
+	// A seat map with TotalAvailableSeats free seats is generated for the flight, flights without a status are scheduled.
+	CreateFlight(flight *dao.Flight) (int32, error)
+	// GetSeatMap gets all seats of a flight ordered by row and letter
+	GetSeatMap(flightIdentifier int32) ([]*dao.Seat, error)
+	// MakeReservation books seats on the flight and stores the reservation with a new booking identifier. If SeatLabels
+	// are provided, exactly those seats are booked or SeatsUnavailableError is returned, else any SeatsReserved free seats
+	// are booked or InsufficientNumberOfAvailableSeatsError is returned. FlightNotBookableError is returned if the flight
+	// has been cancelled or has departed. The new reservation and updated flight are returned.
+	MakeReservation(reservation *dao.Reservation) (*dao.Reservation, *dao.Flight, error)
+	// GetReservation gets a reservation by its booking identifier, returns NoSuchBookingIdentifierError if it does not exist
+	GetReservation(bookingIdentifier int32) (*dao.Reservation, error)
+	// CancelReservation deletes the reservation and frees its seats. The cancelled reservation and updated flight are returned.
+	CancelReservation(bookingIdentifier int32) (*dao.Reservation, *dao.Flight, error)
+	// HoldSeats holds seats on the flight in the same way MakeReservation books them, and stores the hold with the
+	// HoldToken provided. The new hold and updated flight are returned.
+	HoldSeats(hold *dao.Hold) (*dao.Hold, *dao.Flight, error)
+	// GetHolds gets all holds that have not been confirmed or released
+	GetHolds() ([]*dao.Hold, error)
+	// ConfirmHold books the seats of the hold under a new reservation made at reservationTime, returns NoSuchHoldTokenError
+	// if the hold does not exist, was made by another client than clientAddress or has expired by reservationTime, or
+	// FlightNotBookableError if the flight has been cancelled or has departed since. The new reservation and flight are
+	// returned.
+	ConfirmHold(holdToken string, clientAddress string, reservationTime int64) (*dao.Reservation, *dao.Flight, error)
+	// ReleaseHold deletes the hold and frees its seats. The released hold and updated flight are returned.
+	ReleaseHold(holdToken string) (*dao.Hold, *dao.Flight, error)
+	// CreateSchedule stores the schedule with a new schedule identifier, along with its flights in the same way as
+	// CreateFlight. The schedule identifier and the flight identifiers of the new flights are returned.
+	CreateSchedule(schedule *dao.Schedule, flights []*dao.Flight) (int32, []int32, error)
+	// GetSchedule gets a schedule by its schedule identifier, returns NoSuchScheduleIdentifierError if it does not exist
+	GetSchedule(scheduleIdentifier int32) (*dao.Schedule, error)
+	// GetFlightsBySchedule gets all flights of a schedule ordered by departure time
+	GetFlightsBySchedule(scheduleIdentifier int32) ([]*dao.Flight, error)
+	// UpdateSchedule stores the updated schedule, and updates the DepartureTime, ArrivalTime, Airfare and Aircraft of the
+	// flights provided that belong to the schedule, are still scheduled and have not been booked or held, i.e. all seats
+	// are available. The flights updated are returned.
+	UpdateSchedule(schedule *dao.Schedule, flights []*dao.Flight) ([]*dao.Flight, error)
+	// CancelSchedule deletes the schedule along with the flights provided that belong to the schedule and have not been
+	// booked or held, other flights are kept without a schedule identifier. The flights deleted are returned as their
+	// last changes, cancelled with no seats available.
+	CancelSchedule(scheduleIdentifier int32, flightIdentifiers []int32) ([]*dao.Flight, error)
+	// UpdateAirfare updates the airfare of a flight, the updated flight is returned.
+	UpdateAirfare(flightIdentifier int32, airfare float64) (*dao.Flight, error)
+	// UpdateFlightStatus updates the status of a flight along with its DepartureTime and ArrivalTime if they are not 0.
+	// Returns InvalidRequestError if the status of the flight is already final. The updated flight is returned.
+	UpdateFlightStatus(flightIdentifier int32, status dao.FlightStatusType, departureTime, arrivalTime int64) (*dao.Flight, error)
+}
+
+// flightRepository is the flight store selected on boot
+var flightRepository FlightRepository
 
-// this emulates the auto-incrementing PK function of some databases
-var largestFlightID int32
+// Init selects the flight store used by the application
+func Init(repository FlightRepository) {
+	flightRepository = repository
+}
 
-// GetAllFlights Getter function to return all flights
-func GetAllFlights() []*dao.Flight {
-	return flights
+// GetFlightRepository gets the flight store selected on boot
+func GetFlightRepository() FlightRepository {
+	return flightRepository
 }
 
-// GetLargestFlightID gets the largest flight, as there is no flight delete functionality, the following is sufficient
-func GetLargestFlightID() int32 {
-	return largestFlightID
+// PopulateFlights simply populates hardcoded data for flights if the store is empty.
+func PopulateFlights(repository FlightRepository) error {
+	existingFlights, err := repository.GetAllFlights()
+	if err != nil {
+		return err
+	}
+	if len(existingFlights) != 0 {
+		return nil
+	}
+
+	flights := []*dao.Flight{
+		{
+			SourceLocation:      "Singapore",
+			DestinationLocation: "San Francisco",
+			DepartureTime:       1701388800,
+			Airfare:             2050.6,
+			TotalAvailableSeats: 99,
+			ArrivalTime:         1701446400,
+			SourceTimezone:      "Asia/Singapore",
+			DestinationTimezone: "America/Los_Angeles",
+			Aircraft:            "Airbus A350-900ULR",
+		},
+		{
+			SourceLocation:      "Singapore",
+			DestinationLocation: "San Francisco",
+			DepartureTime:       1701388900,
+			Airfare:             3239.20,
+			TotalAvailableSeats: 54,
+			ArrivalTime:         1701446500,
+			SourceTimezone:      "Asia/Singapore",
+			DestinationTimezone: "America/Los_Angeles",
+			Aircraft:            "Boeing 777-300ER",
+		},
+		{
+			SourceLocation:      "Singapore",
+			DestinationLocation: "Kuala Lumpur",
+			DepartureTime:       1701287800,
+			Airfare:             99.9,
+			TotalAvailableSeats: 22,
+			ArrivalTime:         1701291400,
+			SourceTimezone:      "Asia/Singapore",
+			DestinationTimezone: "Asia/Kuala_Lumpur",
+			Aircraft:            "Boeing 737-800",
+		},
+		{
+			SourceLocation:      "Singapore",
+			DestinationLocation: "Bali",
+			DepartureTime:       1701176800,
+			Airfare:             325.1,
+			TotalAvailableSeats: 2,
+			ArrivalTime:         1701185800,
+			SourceTimezone:      "Asia/Singapore",
+			DestinationTimezone: "Asia/Makassar",
+			Aircraft:            "Airbus A320neo",
+		},
+		{
+			SourceLocation:      "Tokyo",
+			DestinationLocation: "Seoul",
+			DepartureTime:       1701065800,
+			Airfare:             892.2,
+			TotalAvailableSeats: 1,
+			ArrivalTime:         1701074800,
+			SourceTimezone:      "Asia/Tokyo",
+			DestinationTimezone: "Asia/Seoul",
+			Aircraft:            "Boeing 787-8",
+		},
+		{
+			SourceLocation:      "Tokyo",
+			DestinationLocation: "Shanghai",
+			DepartureTime:       1701054800,
+			Airfare:             239.2,
+			TotalAvailableSeats: 2,
+			ArrivalTime:         1701065600,
+			SourceTimezone:      "Asia/Tokyo",
+			DestinationTimezone: "Asia/Shanghai",
+			Aircraft:            "Airbus A321",
+		},
+	}
+	// flight identifiers are assigned 1 to 6 in order
+	for _, flight := range flights {
+		if _, err := repository.CreateFlight(flight); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-// NewFlight emulates an insert with an auto-incrementing PK.
-func NewFlight(flight *dao.Flight) {
-	flights = append(flights, flight)
-	largestFlightID += 1
+// newFinalFlightStatusError is returned when the status of a flight that is already cancelled or departed is updated
+func newFinalFlightStatusError(flight *dao.Flight) error {
+	return custom_errors.NewInvalidRequestError(fmt.Sprintf("flight %d can no longer change status, flight status: %d", flight.FlightIdentifier, flight.Status))
 }
 
-// PopulateFlights simply populates hardcoded data for flights.
-func PopulateFlights() {
-	flights = append(flights, &dao.Flight{
-		FlightIdentifier:    1,
-		SourceLocation:      "Singapore",
-		DestinationLocation: "San Francisco",
-		DepartureTime:       1701388800,
-		Airfare:             2050.6,
-		TotalAvailableSeats: 99,
-	})
-	flights = append(flights, &dao.Flight{
-		FlightIdentifier:    2,
-		SourceLocation:      "Singapore",
-		DestinationLocation: "San Francisco",
-		DepartureTime:       1701388900,
-		Airfare:             3239.20,
-		TotalAvailableSeats: 54,
-	})
-	flights = append(flights, &dao.Flight{
-		FlightIdentifier:    3,
-		SourceLocation:      "Singapore",
-		DestinationLocation: "Kular Lumpur",
-		DepartureTime:       1701287800,
-		Airfare:             99.9,
-		TotalAvailableSeats: 22,
-	})
-	flights = append(flights, &dao.Flight{
-		FlightIdentifier:    4,
-		SourceLocation:      "Singapore",
-		DestinationLocation: "Bali",
-		DepartureTime:       1701176800,
-		Airfare:             325.1,
-		TotalAvailableSeats: 2,
-	})
-	flights = append(flights, &dao.Flight{
-		FlightIdentifier:    5,
-		SourceLocation:      "Tokyo",
-		DestinationLocation: "Seoul",
-		DepartureTime:       1701065800,
-		Airfare:             892.2,
-		TotalAvailableSeats: 1,
-	})
-	flights = append(flights, &dao.Flight{
-		FlightIdentifier:    6,
-		SourceLocation:      "Tokyo",
-		DestinationLocation: "Shanghai",
-		DepartureTime:       1701054800,
-		Airfare:             239.2,
-		TotalAvailableSeats: 2,
-	})
-	largestFlightID = 6
+// cancelDeletedFlight copies a flight that has been deleted as cancelled with no seats available, which are its last
+// changes
+func cancelDeletedFlight(flight *dao.Flight) *dao.Flight {
+	output := *flight
+	output.Status = dao.CancelledFlightStatus
+	output.StatusSequenceNumber += 1
+	output.TotalAvailableSeats = 0
+	output.SeatsSequenceNumber += 1
+	return &output
 }
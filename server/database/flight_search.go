@@ -0,0 +1,77 @@
+package database
+
+import (
+	"sort"
+
+	"github.com/cyiafn/flight_information_system/server/dao"
+)
+
+// FlightSortOrderType is the order of flights returned by a search
+type FlightSortOrderType uint8
+
+// Sort orders, flights are always sorted in ascending order with ties broken by flight identifier
+const (
+	FlightIdentifierSortOrder FlightSortOrderType = iota + 1
+	AirfareSortOrder
+	DepartureTimeSortOrder
+)
+
+// FlightSearchCriteria are the filters, sort order and page of a flight search. Zero values of the filters are not applied,
+// e.g. an empty SourceLocation matches flights from any location.
+type FlightSearchCriteria struct {
+	SourceLocation      string
+	DestinationLocation string
+	// DepartureTimeFrom and DepartureTimeTo are the inclusive bounds of the departure time in unix time
+	DepartureTimeFrom int64
+	DepartureTimeTo   int64
+	MaxAirfare        float64
+	MinAvailableSeats int32
+	SortOrder         FlightSortOrderType
+	// Offset is the number of matching flights to skip, Limit is the maximum number of flights returned
+	Offset int
+	Limit  int
+}
+
+// matches checks if a flight passes every filter of the criteria
+func (c *FlightSearchCriteria) matches(flight *dao.Flight) bool {
+	return (c.SourceLocation == "" || flight.SourceLocation == c.SourceLocation) &&
+		(c.DestinationLocation == "" || flight.DestinationLocation == c.DestinationLocation) &&
+		(c.DepartureTimeFrom == 0 || flight.DepartureTime >= c.DepartureTimeFrom) &&
+		(c.DepartureTimeTo == 0 || flight.DepartureTime <= c.DepartureTimeTo) &&
+		(c.MaxAirfare == 0 || flight.Airfare <= c.MaxAirfare) &&
+		flight.TotalAvailableSeats >= c.MinAvailableSeats
+}
+
+// sortFlights sorts flights in the sort order of the criteria, flights are expected to be ordered by flight identifier
+func (c *FlightSearchCriteria) sortFlights(flights []*dao.Flight) {
+	switch c.SortOrder {
+	case AirfareSortOrder:
+		sort.SliceStable(flights, func(i, j int) bool { return flights[i].Airfare < flights[j].Airfare })
+	case DepartureTimeSortOrder:
+		sort.SliceStable(flights, func(i, j int) bool { return flights[i].DepartureTime < flights[j].DepartureTime })
+	}
+}
+
+// page returns the flights in the page of the criteria
+func (c *FlightSearchCriteria) page(flights []*dao.Flight) []*dao.Flight {
+	if c.Offset >= len(flights) {
+		return make([]*dao.Flight, 0)
+	}
+	flights = flights[c.Offset:]
+	if c.Limit < len(flights) {
+		flights = flights[:c.Limit]
+	}
+	return flights
+}
+
+// orderByColumn is the column flights are sorted by in the database
+func (c *FlightSearchCriteria) orderByColumn() string {
+	switch c.SortOrder {
+	case AirfareSortOrder:
+		return "airfare"
+	case DepartureTimeSortOrder:
+		return "departure_time"
+	default:
+		return "flight_identifier"
+	}
+}
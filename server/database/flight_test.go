@@ -0,0 +1,514 @@
+package database
+
+import (
+	"math"
+	"sync"
+	"testing"
+
+	"github.com/cyiafn/flight_information_system/server/custom_errors"
+	"github.com/cyiafn/flight_information_system/server/dao"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openTestDB opens a new in-memory SQLite database. Every connection to file::memory: opens a database of its own, so the
+// pool is kept to a single connection for every query to see the same tables and rows.
+func openTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	sqlDB, err := db.DB()
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	return db
+}
+
+// newFlightRepositories returns a fresh instance of every flight store implementation, the GORM one backed by an in-memory SQLite database
+func newFlightRepositories(t *testing.T) map[string]FlightRepository {
+	db := openTestDB(t)
+	gormRepository, err := NewGormFlightRepository(db)
+	assert.Nil(t, err)
+
+	return map[string]FlightRepository{
+		"in memory": NewInMemoryFlightRepository(),
+		"gorm":      gormRepository,
+	}
+}
+
+func TestGormFlightRepositoryBackfills(t *testing.T) {
+	db := openTestDB(t)
+	assert.Nil(t, db.AutoMigrate(&dao.Flight{}, &dao.Schedule{}))
+	// flights and schedules stored before flights had a status and locations were normalised
+	assert.Nil(t, db.Create(&dao.Flight{FlightIdentifier: 1, SourceLocation: " sin", DestinationLocation: "Bali"}).Error)
+	assert.Nil(t, db.Create(&dao.Flight{FlightIdentifier: 2, SourceLocation: "Singapore", DestinationLocation: "DPS"}).Error)
+	assert.Nil(t, db.Create(&dao.Schedule{ScheduleIdentifier: 1, SourceLocation: "SIN", DestinationLocation: "Atlantis"}).Error)
+
+	repository, err := NewGormFlightRepository(db)
+	assert.Nil(t, err)
+	flight, err := repository.GetFlight(1)
+	assert.Nil(t, err)
+	assert.Equal(t, dao.ScheduledFlightStatus, flight.Status)
+	flights, err := repository.GetFlightsBySourceAndDestination("Singapore", "Bali")
+	assert.Nil(t, err)
+	assert.Len(t, flights, 2)
+	schedule, err := repository.GetSchedule(1)
+	assert.Nil(t, err)
+	assert.Equal(t, "Singapore", schedule.SourceLocation)
+	assert.Equal(t, "Atlantis", schedule.DestinationLocation)
+}
+
+func TestFlightRepository(t *testing.T) {
+	for name, repository := range newFlightRepositories(t) {
+		repository := repository
+		t.Run(name, func(t *testing.T) {
+			assert.Nil(t, PopulateFlights(repository))
+			// populating twice does not duplicate flights
+			assert.Nil(t, PopulateFlights(repository))
+			flights, err := repository.GetAllFlights()
+			assert.Nil(t, err)
+			assert.Len(t, flights, 6)
+
+			id, err := repository.CreateFlight(&dao.Flight{
+				SourceLocation:      "Singapore",
+				DestinationLocation: "Bali",
+				DepartureTime:       1701176900,
+				Airfare:             300,
+				TotalAvailableSeats: 10,
+				ArrivalTime:         1701185900,
+				SourceTimezone:      "Asia/Singapore",
+				DestinationTimezone: "Asia/Makassar",
+				Aircraft:            "Airbus A320neo",
+			})
+			assert.Nil(t, err)
+			assert.Equal(t, int32(7), id)
+
+			flights, err = repository.GetFlightsBySourceAndDestination("Singapore", "Bali")
+			assert.Nil(t, err)
+			assert.Len(t, flights, 2)
+			assert.Equal(t, int32(4), flights[0].FlightIdentifier)
+			assert.Equal(t, int32(7), flights[1].FlightIdentifier)
+
+			reservation, flight, err := repository.MakeReservation(&dao.Reservation{FlightIdentifier: 7, SeatsReserved: 4, ClientAddress: "127.0.0.1:5000", ReservationTime: 1701000000})
+			assert.Nil(t, err)
+			assert.Equal(t, int32(1), reservation.BookingIdentifier)
+			assert.Equal(t, int32(6), flight.TotalAvailableSeats)
+
+			_, _, err = repository.MakeReservation(&dao.Reservation{FlightIdentifier: 7, SeatsReserved: 7})
+			assert.IsType(t, &custom_errors.InsufficientNumberOfAvailableSeatsError{}, err)
+			// the number of seats requested is not allocated up front
+			_, _, err = repository.MakeReservation(&dao.Reservation{FlightIdentifier: 7, SeatsReserved: math.MaxInt32})
+			assert.IsType(t, &custom_errors.InsufficientNumberOfAvailableSeatsError{}, err)
+
+			_, _, err = repository.MakeReservation(&dao.Reservation{FlightIdentifier: 99, SeatsReserved: 1})
+			assert.IsType(t, &custom_errors.NoSuchFlightIdentifierError{}, err)
+
+			// cancelling restores the seats and the reservation can no longer be found
+			reservation, _, err = repository.MakeReservation(&dao.Reservation{FlightIdentifier: 7, SeatsReserved: 2})
+			assert.Nil(t, err)
+			assert.Equal(t, int32(2), reservation.BookingIdentifier)
+			reservation, flight, err = repository.CancelReservation(2)
+			assert.Nil(t, err)
+			assert.Equal(t, int32(2), reservation.SeatsReserved)
+			assert.Equal(t, int32(6), flight.TotalAvailableSeats)
+			_, err = repository.GetReservation(2)
+			assert.IsType(t, &custom_errors.NoSuchBookingIdentifierError{}, err)
+			_, _, err = repository.CancelReservation(2)
+			assert.IsType(t, &custom_errors.NoSuchBookingIdentifierError{}, err)
+
+			reservation, err = repository.GetReservation(1)
+			assert.Nil(t, err)
+			assert.Equal(t, dao.Reservation{
+				BookingIdentifier: 1,
+				FlightIdentifier:  7,
+				SeatsReserved:     4,
+				ClientAddress:     "127.0.0.1:5000",
+				ReservationTime:   1701000000,
+				SeatLabels:        []string{"1A", "1B", "1C", "1D"},
+			}, *reservation)
+
+			// the seats cancelled are free again, so the next reservation books them
+			reservation, _, err = repository.MakeReservation(&dao.Reservation{FlightIdentifier: 7, SeatsReserved: 1})
+			assert.Nil(t, err)
+			assert.Equal(t, []string{"1E"}, reservation.SeatLabels)
+			_, flight, err = repository.CancelReservation(reservation.BookingIdentifier)
+			assert.Nil(t, err)
+			assert.Equal(t, int32(6), flight.TotalAvailableSeats)
+
+			flight, err = repository.UpdateAirfare(7, 250.5)
+			assert.Nil(t, err)
+			assert.Equal(t, 250.5, flight.Airfare)
+
+			_, err = repository.UpdateAirfare(99, 1)
+			assert.IsType(t, &custom_errors.NoSuchFlightIdentifierError{}, err)
+
+			// mutating a flight returned does not change the stored flight
+			flight.TotalAvailableSeats = 0
+			flight, err = repository.GetFlight(7)
+			assert.Nil(t, err)
+			assert.Equal(t, dao.Flight{
+				FlightIdentifier:    7,
+				SourceLocation:      "Singapore",
+				DestinationLocation: "Bali",
+				DepartureTime:       1701176900,
+				Airfare:             250.5,
+				TotalAvailableSeats: 6,
+				ArrivalTime:         1701185900,
+				SourceTimezone:      "Asia/Singapore",
+				DestinationTimezone: "Asia/Makassar",
+				Aircraft:            "Airbus A320neo",
+				Status:              dao.ScheduledFlightStatus,
+				// 3 reservations made and 2 cancelled, then an airfare update
+				SeatsSequenceNumber:   5,
+				AirfareSequenceNumber: 1,
+			}, *flight)
+		})
+	}
+}
+
+func TestFlightRepositoryConcurrentSequenceNumbers(t *testing.T) {
+	for name, repository := range newFlightRepositories(t) {
+		repository := repository
+		t.Run(name, func(t *testing.T) {
+			id, err := repository.CreateFlight(&dao.Flight{SourceLocation: "Singapore", DestinationLocation: "Bali", DepartureTime: 1000, ArrivalTime: 2000, TotalAvailableSeats: 20})
+			assert.Nil(t, err)
+
+			// every reservation made concurrently is given its own sequence number
+			sequenceNumbers := make(chan int64, 20)
+			var wg sync.WaitGroup
+			for i := 0; i < 20; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					_, flight, err := repository.MakeReservation(&dao.Reservation{FlightIdentifier: id, SeatsReserved: 1})
+					if assert.Nil(t, err) {
+						sequenceNumbers <- flight.SeatsSequenceNumber
+					}
+				}()
+			}
+			wg.Wait()
+			close(sequenceNumbers)
+			seen := make(map[int64]bool)
+			for sequenceNumber := range sequenceNumbers {
+				seen[sequenceNumber] = true
+			}
+			for i := int64(1); i <= 20; i++ {
+				assert.True(t, seen[i], "sequence number %d", i)
+			}
+		})
+	}
+}
+
+func TestFlightRepositorySeatMap(t *testing.T) {
+	for name, repository := range newFlightRepositories(t) {
+		repository := repository
+		t.Run(name, func(t *testing.T) {
+			id, err := repository.CreateFlight(&dao.Flight{SourceLocation: "Singapore", DestinationLocation: "Bali", TotalAvailableSeats: 32})
+			assert.Nil(t, err)
+
+			// 32 seats is 6 rows, the first of which is business class
+			seats, err := repository.GetSeatMap(id)
+			assert.Nil(t, err)
+			assert.Len(t, seats, 32)
+			assert.Equal(t, dao.Seat{FlightIdentifier: id, SeatLabel: "1A", SeatRow: 1, SeatLetter: "A", CabinClass: dao.BusinessCabinClass, Status: dao.FreeSeatStatus}, *seats[0])
+			assert.Equal(t, dao.Seat{FlightIdentifier: id, SeatLabel: "2A", SeatRow: 2, SeatLetter: "A", CabinClass: dao.EconomyCabinClass, Status: dao.FreeSeatStatus}, *seats[6])
+			assert.Equal(t, "6B", seats[31].SeatLabel)
+
+			reservation, flight, err := repository.MakeReservation(&dao.Reservation{FlightIdentifier: id, SeatLabels: []string{"3C", "3D", "3C"}})
+			assert.Nil(t, err)
+			assert.Equal(t, int32(2), reservation.SeatsReserved)
+			assert.Equal(t, []string{"3C", "3D"}, reservation.SeatLabels)
+			assert.Equal(t, int32(30), flight.TotalAvailableSeats)
+
+			// the reservation fails as a whole if any seat is booked or does not exist
+			_, _, err = repository.MakeReservation(&dao.Reservation{FlightIdentifier: id, SeatLabels: []string{"3B", "3C", "9Z"}})
+			assert.Equal(t, custom_errors.NewSeatsUnavailableError([]string{"3C", "9Z"}), err)
+			seats, err = repository.GetSeatMap(id)
+			assert.Nil(t, err)
+			booked := make([]string, 0)
+			for _, seat := range seats {
+				if seat.Status == dao.BookedSeatStatus {
+					assert.Equal(t, reservation.BookingIdentifier, seat.BookingIdentifier)
+					booked = append(booked, seat.SeatLabel)
+				}
+			}
+			assert.Equal(t, []string{"3C", "3D"}, booked)
+
+			_, err = repository.GetSeatMap(99)
+			assert.IsType(t, &custom_errors.NoSuchFlightIdentifierError{}, err)
+		})
+	}
+}
+
+func TestFlightRepositoryHolds(t *testing.T) {
+	for name, repository := range newFlightRepositories(t) {
+		repository := repository
+		t.Run(name, func(t *testing.T) {
+			id, err := repository.CreateFlight(&dao.Flight{SourceLocation: "Singapore", DestinationLocation: "Bali", TotalAvailableSeats: 6})
+			assert.Nil(t, err)
+
+			hold, flight, err := repository.HoldSeats(&dao.Hold{HoldToken: "first", FlightIdentifier: id, SeatsHeld: 2, ClientAddress: "127.0.0.1:5000", ExpiryTime: 100})
+			assert.Nil(t, err)
+			assert.Equal(t, []string{"1A", "1B"}, hold.SeatLabels)
+			assert.Equal(t, int32(4), flight.TotalAvailableSeats)
+
+			// held seats cannot be reserved or held by anyone else
+			_, _, err = repository.MakeReservation(&dao.Reservation{FlightIdentifier: id, SeatLabels: []string{"1B", "1C"}})
+			assert.Equal(t, custom_errors.NewSeatsUnavailableError([]string{"1B"}), err)
+			_, _, err = repository.HoldSeats(&dao.Hold{HoldToken: "second", FlightIdentifier: id, SeatLabels: []string{"1E", "1F"}, ExpiryTime: 100})
+			assert.Nil(t, err)
+			seats, err := repository.GetSeatMap(id)
+			assert.Nil(t, err)
+			assert.Equal(t, dao.HeldSeatStatus, seats[0].Status)
+			assert.Equal(t, "first", seats[0].HoldToken)
+			holds, err := repository.GetHolds()
+			assert.Nil(t, err)
+			assert.Len(t, holds, 2)
+
+			// an expired hold or a hold of another client cannot be confirmed
+			_, _, err = repository.ConfirmHold("first", "127.0.0.1:5000", 101)
+			assert.IsType(t, &custom_errors.NoSuchHoldTokenError{}, err)
+			_, _, err = repository.ConfirmHold("first", "127.0.0.1:5001", 100)
+			assert.IsType(t, &custom_errors.NoSuchHoldTokenError{}, err)
+			reservation, flight, err := repository.ConfirmHold("first", "127.0.0.1:5000", 100)
+			assert.Nil(t, err)
+			assert.Equal(t, dao.Reservation{
+				BookingIdentifier: 1,
+				FlightIdentifier:  id,
+				SeatsReserved:     2,
+				ClientAddress:     "127.0.0.1:5000",
+				ReservationTime:   100,
+				SeatLabels:        []string{"1A", "1B"},
+			}, *reservation)
+			assert.Equal(t, int32(2), flight.TotalAvailableSeats)
+			// a confirmed hold can no longer be confirmed or released
+			_, _, err = repository.ConfirmHold("first", "127.0.0.1:5000", 100)
+			assert.IsType(t, &custom_errors.NoSuchHoldTokenError{}, err)
+			_, _, err = repository.ReleaseHold("first")
+			assert.IsType(t, &custom_errors.NoSuchHoldTokenError{}, err)
+
+			hold, flight, err = repository.ReleaseHold("second")
+			assert.Nil(t, err)
+			assert.Equal(t, []string{"1E", "1F"}, hold.SeatLabels)
+			assert.Equal(t, int32(4), flight.TotalAvailableSeats)
+			seats, err = repository.GetSeatMap(id)
+			assert.Nil(t, err)
+			assert.Equal(t, dao.BookedSeatStatus, seats[0].Status)
+			assert.Equal(t, dao.Seat{FlightIdentifier: id, SeatLabel: "1E", SeatRow: 1, SeatLetter: "E", CabinClass: dao.EconomyCabinClass, Status: dao.FreeSeatStatus}, *seats[4])
+			holds, err = repository.GetHolds()
+			assert.Nil(t, err)
+			assert.Len(t, holds, 0)
+		})
+	}
+}
+
+func TestFlightRepositorySearchFlights(t *testing.T) {
+	// flights populated are 1 to 6, see PopulateFlights
+	tests := []struct {
+		Name                      string
+		Criteria                  FlightSearchCriteria
+		ExpectedFlightIdentifiers []int32
+		ExpectedTotalMatches      int32
+	}{
+		{
+			Name:                      "no filters",
+			Criteria:                  FlightSearchCriteria{SortOrder: FlightIdentifierSortOrder, Limit: 20},
+			ExpectedFlightIdentifiers: []int32{1, 2, 3, 4, 5, 6},
+			ExpectedTotalMatches:      6,
+		},
+		{
+			Name:                      "source only, sorted by airfare",
+			Criteria:                  FlightSearchCriteria{SourceLocation: "Singapore", SortOrder: AirfareSortOrder, Limit: 20},
+			ExpectedFlightIdentifiers: []int32{3, 4, 1, 2},
+			ExpectedTotalMatches:      4,
+		},
+		{
+			Name:                      "departure window and max airfare, sorted by departure time",
+			Criteria:                  FlightSearchCriteria{DepartureTimeFrom: 1701054800, DepartureTimeTo: 1701287800, MaxAirfare: 500, SortOrder: DepartureTimeSortOrder, Limit: 20},
+			ExpectedFlightIdentifiers: []int32{6, 4, 3},
+			ExpectedTotalMatches:      3,
+		},
+		{
+			Name:                      "min available seats",
+			Criteria:                  FlightSearchCriteria{MinAvailableSeats: 22, SortOrder: FlightIdentifierSortOrder, Limit: 20},
+			ExpectedFlightIdentifiers: []int32{1, 2, 3},
+			ExpectedTotalMatches:      3,
+		},
+		{
+			Name:                      "route",
+			Criteria:                  FlightSearchCriteria{SourceLocation: "Tokyo", DestinationLocation: "Seoul", SortOrder: FlightIdentifierSortOrder, Limit: 20},
+			ExpectedFlightIdentifiers: []int32{5},
+			ExpectedTotalMatches:      1,
+		},
+		{
+			Name:                      "second page",
+			Criteria:                  FlightSearchCriteria{SortOrder: AirfareSortOrder, Offset: 2, Limit: 2},
+			ExpectedFlightIdentifiers: []int32{4, 5},
+			ExpectedTotalMatches:      6,
+		},
+		{
+			Name:                      "offset past the last match",
+			Criteria:                  FlightSearchCriteria{SortOrder: FlightIdentifierSortOrder, Offset: 6, Limit: 20},
+			ExpectedFlightIdentifiers: []int32{},
+			ExpectedTotalMatches:      6,
+		},
+	}
+
+	for name, repository := range newFlightRepositories(t) {
+		assert.Nil(t, PopulateFlights(repository))
+		for _, test := range tests {
+			test := test
+			repository := repository
+			t.Run(name+"/"+test.Name, func(t *testing.T) {
+				flights, totalMatches, err := repository.SearchFlights(&test.Criteria)
+				assert.Nil(t, err)
+				flightIdentifiers := make([]int32, len(flights))
+				for i, flight := range flights {
+					flightIdentifiers[i] = flight.FlightIdentifier
+				}
+				assert.Equal(t, test.ExpectedFlightIdentifiers, flightIdentifiers)
+				assert.Equal(t, test.ExpectedTotalMatches, totalMatches)
+			})
+		}
+	}
+}
+
+func TestFlightRepositorySchedules(t *testing.T) {
+	for name, repository := range newFlightRepositories(t) {
+		repository := repository
+		t.Run(name, func(t *testing.T) {
+			schedule := &dao.Schedule{SourceLocation: "Singapore", DestinationLocation: "Bali", Airfare: 300, TotalSeats: 4}
+			flights := []*dao.Flight{
+				{SourceLocation: "Singapore", DestinationLocation: "Bali", DepartureTime: 2000, ArrivalTime: 3000, Airfare: 300, TotalAvailableSeats: 4},
+				{SourceLocation: "Singapore", DestinationLocation: "Bali", DepartureTime: 1000, ArrivalTime: 2000, Airfare: 300, TotalAvailableSeats: 4},
+				{SourceLocation: "Singapore", DestinationLocation: "Bali", DepartureTime: 3000, ArrivalTime: 4000, Airfare: 300, TotalAvailableSeats: 4},
+			}
+			scheduleIdentifier, flightIdentifiers, err := repository.CreateSchedule(schedule, flights)
+			assert.Nil(t, err)
+			assert.Equal(t, int32(1), scheduleIdentifier)
+			assert.Equal(t, []int32{1, 2, 3}, flightIdentifiers)
+
+			scheduledFlights, err := repository.GetFlightsBySchedule(scheduleIdentifier)
+			assert.Nil(t, err)
+			assert.Len(t, scheduledFlights, 3)
+			assert.Equal(t, int32(2), scheduledFlights[0].FlightIdentifier)
+			assert.Equal(t, scheduleIdentifier, scheduledFlights[0].ScheduleIdentifier)
+			seats, err := repository.GetSeatMap(2)
+			assert.Nil(t, err)
+			assert.Len(t, seats, 4)
+
+			// flights that have been booked are not updated or cancelled
+			_, _, err = repository.MakeReservation(&dao.Reservation{FlightIdentifier: 1, SeatsReserved: 1})
+			assert.Nil(t, err)
+
+			schedule, err = repository.GetSchedule(scheduleIdentifier)
+			assert.Nil(t, err)
+			schedule.Airfare = 400
+			for _, flight := range scheduledFlights {
+				flight.DepartureTime += 100
+				flight.Airfare = 400
+			}
+			updatedFlights, err := repository.UpdateSchedule(schedule, scheduledFlights)
+			assert.Nil(t, err)
+			assert.Len(t, updatedFlights, 2)
+			assert.Equal(t, int32(2), updatedFlights[0].FlightIdentifier)
+			assert.Equal(t, int64(1100), updatedFlights[0].DepartureTime)
+			assert.Equal(t, float64(400), updatedFlights[1].Airfare)
+			flight, err := repository.GetFlight(1)
+			assert.Nil(t, err)
+			assert.Equal(t, int64(2000), flight.DepartureTime)
+			assert.Equal(t, float64(300), flight.Airfare)
+			schedule, err = repository.GetSchedule(scheduleIdentifier)
+			assert.Nil(t, err)
+			assert.Equal(t, float64(400), schedule.Airfare)
+
+			// only the flights provided are cancelled
+			cancelledFlights, err := repository.CancelSchedule(scheduleIdentifier, []int32{1, 3})
+			assert.Nil(t, err)
+			assert.Len(t, cancelledFlights, 1)
+			assert.Equal(t, int32(3), cancelledFlights[0].FlightIdentifier)
+			assert.Equal(t, dao.CancelledFlightStatus, cancelledFlights[0].Status)
+			assert.Equal(t, int64(1), cancelledFlights[0].StatusSequenceNumber)
+			assert.Equal(t, int32(0), cancelledFlights[0].TotalAvailableSeats)
+			assert.Equal(t, int64(1), cancelledFlights[0].SeatsSequenceNumber)
+			_, err = repository.GetFlight(3)
+			assert.IsType(t, &custom_errors.NoSuchFlightIdentifierError{}, err)
+			_, err = repository.GetSeatMap(3)
+			assert.IsType(t, &custom_errors.NoSuchFlightIdentifierError{}, err)
+			// the flights kept no longer belong to the schedule
+			flight, err = repository.GetFlight(1)
+			assert.Nil(t, err)
+			assert.Equal(t, int32(0), flight.ScheduleIdentifier)
+			flight, err = repository.GetFlight(2)
+			assert.Nil(t, err)
+			assert.Equal(t, int32(0), flight.ScheduleIdentifier)
+			scheduledFlights, err = repository.GetFlightsBySchedule(scheduleIdentifier)
+			assert.Nil(t, err)
+			assert.Empty(t, scheduledFlights)
+			_, err = repository.GetSchedule(scheduleIdentifier)
+			assert.IsType(t, &custom_errors.NoSuchScheduleIdentifierError{}, err)
+			_, err = repository.UpdateSchedule(schedule, nil)
+			assert.IsType(t, &custom_errors.NoSuchScheduleIdentifierError{}, err)
+		})
+	}
+}
+
+func TestFlightRepositoryFlightStatus(t *testing.T) {
+	for name, repository := range newFlightRepositories(t) {
+		repository := repository
+		t.Run(name, func(t *testing.T) {
+			id, err := repository.CreateFlight(&dao.Flight{SourceLocation: "Singapore", DestinationLocation: "Bali", DepartureTime: 1000, ArrivalTime: 2000, TotalAvailableSeats: 6})
+			assert.Nil(t, err)
+			flight, err := repository.GetFlight(id)
+			assert.Nil(t, err)
+			assert.Equal(t, dao.ScheduledFlightStatus, flight.Status)
+
+			// times that are 0 are not updated
+			flight, err = repository.UpdateFlightStatus(id, dao.DelayedFlightStatus, 1500, 0)
+			assert.Nil(t, err)
+			assert.Equal(t, dao.DelayedFlightStatus, flight.Status)
+			assert.Equal(t, int64(1500), flight.DepartureTime)
+			assert.Equal(t, int64(2000), flight.ArrivalTime)
+
+			// delayed flights can still be booked
+			_, _, err = repository.MakeReservation(&dao.Reservation{FlightIdentifier: id, SeatsReserved: 1})
+			assert.Nil(t, err)
+			_, _, err = repository.HoldSeats(&dao.Hold{HoldToken: "held", FlightIdentifier: id, SeatsHeld: 1, ExpiryTime: 100})
+			assert.Nil(t, err)
+
+			flight, err = repository.UpdateFlightStatus(id, dao.CancelledFlightStatus, 0, 0)
+			assert.Nil(t, err)
+			assert.Equal(t, dao.CancelledFlightStatus, flight.Status)
+			assert.Equal(t, int64(1500), flight.DepartureTime)
+
+			notBookableErr := custom_errors.NewFlightNotBookableError(uint8(dao.CancelledFlightStatus))
+			_, _, err = repository.MakeReservation(&dao.Reservation{FlightIdentifier: id, SeatsReserved: 1})
+			assert.Equal(t, notBookableErr, err)
+			_, _, err = repository.HoldSeats(&dao.Hold{HoldToken: "second", FlightIdentifier: id, SeatsHeld: 1, ExpiryTime: 100})
+			assert.Equal(t, notBookableErr, err)
+			_, _, err = repository.ConfirmHold("held", "", 100)
+			assert.Equal(t, notBookableErr, err)
+			// reservations and holds on a cancelled flight can still be cancelled and released
+			_, _, err = repository.CancelReservation(1)
+			assert.Nil(t, err)
+			_, flight, err = repository.ReleaseHold("held")
+			assert.Nil(t, err)
+			assert.Equal(t, int32(6), flight.TotalAvailableSeats)
+			// every change has its own sequence numbers
+			assert.Equal(t, int64(4), flight.SeatsSequenceNumber)
+			assert.Equal(t, int64(2), flight.StatusSequenceNumber)
+			assert.Equal(t, int64(0), flight.AirfareSequenceNumber)
+
+			// cancelled is final
+			_, err = repository.UpdateFlightStatus(id, dao.ScheduledFlightStatus, 0, 0)
+			assert.IsType(t, &custom_errors.InvalidRequestError{}, err)
+			_, err = repository.UpdateFlightStatus(99, dao.DelayedFlightStatus, 0, 0)
+			assert.IsType(t, &custom_errors.NoSuchFlightIdentifierError{}, err)
+		})
+	}
+}
@@ -0,0 +1,754 @@
+package database
+
+import (
+	"errors"
+
+	"github.com/cyiafn/flight_information_system/server/custom_errors"
+	"github.com/cyiafn/flight_information_system/server/dao"
+	"github.com/cyiafn/flight_information_system/server/locations"
+	"github.com/cyiafn/flight_information_system/server/logs"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/logger"
+)
+
+/*
+GormFlightRepository persists flights in an actual database through GORM, flights survive restarts of the server.
+Updates are done within transactions, with the flight row locked for reservations, so that concurrent requests do not
+oversell seats or book the same seat twice.
+*/
+
+// Validate interface compliance at compile time.
+var _ FlightRepository = (*GormFlightRepository)(nil)
+
+// seatBatchSize is the number of seats inserted per statement, as databases limit the number of parameters per statement
+const seatBatchSize = 500
+
+// GormFlightRepository is a flight store backed by any database supported by GORM
+type GormFlightRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresFlightRepository connects to a postgres database with the dsn provided
+func NewPostgresFlightRepository(dsn string) (*GormFlightRepository, error) {
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Warn)})
+	if err != nil {
+		logs.Error("unable to connect to postgres, err: %v", err)
+		return nil, err
+	}
+	return NewGormFlightRepository(db)
+}
+
+// NewGormFlightRepository instantiates the flight store with a GORM connection, migrating the schema if required
+func NewGormFlightRepository(db *gorm.DB) (*GormFlightRepository, error) {
+	if err := db.AutoMigrate(&dao.Flight{}, &dao.Reservation{}, &dao.Seat{}, &dao.Hold{}, &dao.Schedule{}); err != nil {
+		logs.Error("unable to migrate flights, err: %v", err)
+		return nil, err
+	}
+	if err := backfillFlightStatus(db); err != nil {
+		logs.Error("unable to backfill the status of flights, err: %v", err)
+		return nil, err
+	}
+	if err := backfillLocations(db); err != nil {
+		logs.Error("unable to backfill the locations of flights, err: %v", err)
+		return nil, err
+	}
+	return &GormFlightRepository{db: db}, nil
+}
+
+// backfillLocations rewrites the locations of flights and schedules stored before locations were normalised to their
+// canonical city names, e.g. "SIN" to "Singapore", so that they are found by the normalised names searched for. Only the
+// distinct locations are normalised, so it is run on every boot.
+func backfillLocations(db *gorm.DB) error {
+	for _, model := range []any{&dao.Flight{}, &dao.Schedule{}} {
+		for _, column := range []string{"source_location", "destination_location"} {
+			var names []string
+			if err := db.Model(model).Distinct().Pluck(column, &names).Error; err != nil {
+				return err
+			}
+			for _, name := range names {
+				normalisedName := locations.Normalise(name)
+				if normalisedName == name {
+					continue
+				}
+				result := db.Model(model).Where(column+" = ?", name).Update(column, normalisedName)
+				if result.Error != nil {
+					return result.Error
+				}
+				logs.Info("backfilled %s of %d rows from %q to %q", column, result.RowsAffected, name, normalisedName)
+			}
+		}
+	}
+	return nil
+}
+
+// backfillFlightStatus sets the status of flights stored before flights had a status, which is 0, to scheduled. It only
+// updates those flights, so it is run on every boot.
+func backfillFlightStatus(db *gorm.DB) error {
+	result := db.Model(&dao.Flight{}).Where("status = ?", 0).Update("status", dao.ScheduledFlightStatus)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected != 0 {
+		logs.Info("backfilled the status of %d flights to scheduled", result.RowsAffected)
+	}
+	return nil
+}
+
+func (r *GormFlightRepository) GetFlight(flightIdentifier int32) (*dao.Flight, error) {
+	return r.getFlight(r.db, flightIdentifier)
+}
+
+func (r *GormFlightRepository) GetAllFlights() ([]*dao.Flight, error) {
+	output := make([]*dao.Flight, 0)
+	if err := r.db.Order("flight_identifier").Find(&output).Error; err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+func (r *GormFlightRepository) GetFlightsBySourceAndDestination(sourceLocation, destinationLocation string) ([]*dao.Flight, error) {
+	output := make([]*dao.Flight, 0)
+	err := r.db.
+		Where("source_location = ? AND destination_location = ?", sourceLocation, destinationLocation).
+		Order("flight_identifier").
+		Find(&output).Error
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+func (r *GormFlightRepository) SearchFlights(criteria *FlightSearchCriteria) ([]*dao.Flight, int32, error) {
+	// the filters are applied as a scope as the same conditions are used for both the count and the page
+	filters := func(db *gorm.DB) *gorm.DB {
+		if criteria.SourceLocation != "" {
+			db = db.Where("source_location = ?", criteria.SourceLocation)
+		}
+		if criteria.DestinationLocation != "" {
+			db = db.Where("destination_location = ?", criteria.DestinationLocation)
+		}
+		if criteria.DepartureTimeFrom != 0 {
+			db = db.Where("departure_time >= ?", criteria.DepartureTimeFrom)
+		}
+		if criteria.DepartureTimeTo != 0 {
+			db = db.Where("departure_time <= ?", criteria.DepartureTimeTo)
+		}
+		if criteria.MaxAirfare != 0 {
+			db = db.Where("airfare <= ?", criteria.MaxAirfare)
+		}
+		return db.Where("total_available_seats >= ?", criteria.MinAvailableSeats)
+	}
+
+	var total int64
+	if err := r.db.Model(&dao.Flight{}).Scopes(filters).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	output := make([]*dao.Flight, 0)
+	err := r.db.Scopes(filters).
+		Order(criteria.orderByColumn()).
+		Order("flight_identifier").
+		Offset(criteria.Offset).
+		Limit(criteria.Limit).
+		Find(&output).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return output, int32(total), nil
+}
+
+func (r *GormFlightRepository) CreateFlight(flight *dao.Flight) (int32, error) {
+	var flightIdentifier int32
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		flightIdentifier, err = r.createFlight(tx, flight)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return flightIdentifier, nil
+}
+
+// createFlight inserts a copy of the flight and its seat map within the transaction provided, returns the flight identifier
+func (r *GormFlightRepository) createFlight(tx *gorm.DB, flight *dao.Flight) (int32, error) {
+	newFlight := *flight
+	// a zero flight identifier lets the database assign one
+	newFlight.FlightIdentifier = 0
+	if newFlight.Status == 0 {
+		newFlight.Status = dao.ScheduledFlightStatus
+	}
+	if err := tx.Create(&newFlight).Error; err != nil {
+		return 0, err
+	}
+	seats := newSeatMap(newFlight.FlightIdentifier, newFlight.TotalAvailableSeats)
+	if len(seats) == 0 {
+		return newFlight.FlightIdentifier, nil
+	}
+	return newFlight.FlightIdentifier, tx.CreateInBatches(seats, seatBatchSize).Error
+}
+
+func (r *GormFlightRepository) GetSeatMap(flightIdentifier int32) ([]*dao.Seat, error) {
+	if _, err := r.getFlight(r.db, flightIdentifier); err != nil {
+		return nil, err
+	}
+	output := make([]*dao.Seat, 0)
+	if err := r.db.Where("flight_identifier = ?", flightIdentifier).Order("seat_row").Order("seat_letter").Find(&output).Error; err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+func (r *GormFlightRepository) MakeReservation(reservation *dao.Reservation) (*dao.Reservation, *dao.Flight, error) {
+	newReservation := *reservation
+	// a zero booking identifier lets the database assign one
+	newReservation.BookingIdentifier = 0
+	var output *dao.Flight
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// the flight is locked so that concurrent reservations on the same flight cannot book the same seats
+		flight, err := r.lockBookableFlight(tx, newReservation.FlightIdentifier)
+		if err != nil {
+			return err
+		}
+		seats, err := r.getFreeSeats(tx, newReservation.FlightIdentifier, newReservation.SeatsReserved, newReservation.SeatLabels)
+		if err != nil {
+			return err
+		}
+
+		newReservation.SeatsReserved = int32(len(seats))
+		newReservation.SeatLabels = make([]string, len(seats))
+		for i, seat := range seats {
+			newReservation.SeatLabels[i] = seat.SeatLabel
+		}
+		if err := tx.Create(&newReservation).Error; err != nil {
+			return err
+		}
+		err = tx.Model(&dao.Seat{}).
+			Where("flight_identifier = ? AND seat_label IN ?", newReservation.FlightIdentifier, newReservation.SeatLabels).
+			Updates(map[string]any{"status": dao.BookedSeatStatus, "booking_identifier": newReservation.BookingIdentifier}).Error
+		if err != nil {
+			return err
+		}
+		flight.TotalAvailableSeats -= newReservation.SeatsReserved
+		if err := r.updateSeats(tx, flight); err != nil {
+			return err
+		}
+		output = flight
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return &newReservation, output, nil
+}
+
+// getFreeSeats gets the seats requested if all of them are free, or the first numberOfSeats free seats if no seats are
+// requested. The flight must be locked by the caller.
+func (r *GormFlightRepository) getFreeSeats(tx *gorm.DB, flightIdentifier int32, numberOfSeats int32, seatLabels []string) ([]*dao.Seat, error) {
+	output := make([]*dao.Seat, 0)
+	if len(seatLabels) == 0 {
+		err := tx.Where("flight_identifier = ? AND status = ?", flightIdentifier, dao.FreeSeatStatus).
+			Order("seat_row").
+			Order("seat_letter").
+			Limit(int(numberOfSeats)).
+			Find(&output).Error
+		if err != nil {
+			return nil, err
+		}
+		if int32(len(output)) < numberOfSeats {
+			return nil, custom_errors.NewInsufficientNumberOfAvailableSeatsError()
+		}
+		return output, nil
+	}
+
+	seatLabels = uniqueSeatLabels(seatLabels)
+	err := tx.Where("flight_identifier = ? AND seat_label IN ? AND status = ?", flightIdentifier, seatLabels, dao.FreeSeatStatus).
+		Find(&output).Error
+	if err != nil {
+		return nil, err
+	}
+	freeSeats := make(map[string]*dao.Seat, len(output))
+	for _, seat := range output {
+		freeSeats[seat.SeatLabel] = seat
+	}
+
+	// we keep the order of the seats requested
+	output = make([]*dao.Seat, 0, len(seatLabels))
+	unavailableSeatLabels := make([]string, 0)
+	for _, seatLabel := range seatLabels {
+		seat, ok := freeSeats[seatLabel]
+		if !ok {
+			unavailableSeatLabels = append(unavailableSeatLabels, seatLabel)
+			continue
+		}
+		output = append(output, seat)
+	}
+	if len(unavailableSeatLabels) != 0 {
+		return nil, custom_errors.NewSeatsUnavailableError(unavailableSeatLabels)
+	}
+	return output, nil
+}
+
+func (r *GormFlightRepository) GetReservation(bookingIdentifier int32) (*dao.Reservation, error) {
+	return r.getReservation(r.db, bookingIdentifier)
+}
+
+func (r *GormFlightRepository) CancelReservation(bookingIdentifier int32) (*dao.Reservation, *dao.Flight, error) {
+	var reservation *dao.Reservation
+	var flight *dao.Flight
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		reservation, err = r.getReservation(tx, bookingIdentifier)
+		if err != nil {
+			return err
+		}
+		flight, err = r.lockFlight(tx, reservation.FlightIdentifier)
+		if err != nil {
+			return err
+		}
+		// the delete is conditional so that a concurrent cancellation cannot free the seats twice
+		result := tx.Delete(&dao.Reservation{}, "booking_identifier = ?", bookingIdentifier)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return custom_errors.NewNoSuchBookingIdentifierError()
+		}
+		err = tx.Model(&dao.Seat{}).
+			Where("booking_identifier = ?", bookingIdentifier).
+			Updates(map[string]any{"status": dao.FreeSeatStatus, "booking_identifier": 0}).Error
+		if err != nil {
+			return err
+		}
+		flight.TotalAvailableSeats += reservation.SeatsReserved
+		return r.updateSeats(tx, flight)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return reservation, flight, nil
+}
+
+func (r *GormFlightRepository) HoldSeats(hold *dao.Hold) (*dao.Hold, *dao.Flight, error) {
+	newHold := *hold
+	var output *dao.Flight
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// the flight is locked so that concurrent holds and reservations on the same flight cannot take the same seats
+		flight, err := r.lockBookableFlight(tx, newHold.FlightIdentifier)
+		if err != nil {
+			return err
+		}
+		seats, err := r.getFreeSeats(tx, newHold.FlightIdentifier, newHold.SeatsHeld, newHold.SeatLabels)
+		if err != nil {
+			return err
+		}
+
+		newHold.SeatsHeld = int32(len(seats))
+		newHold.SeatLabels = make([]string, len(seats))
+		for i, seat := range seats {
+			newHold.SeatLabels[i] = seat.SeatLabel
+		}
+		if err := tx.Create(&newHold).Error; err != nil {
+			return err
+		}
+		err = tx.Model(&dao.Seat{}).
+			Where("flight_identifier = ? AND seat_label IN ?", newHold.FlightIdentifier, newHold.SeatLabels).
+			Updates(map[string]any{"status": dao.HeldSeatStatus, "hold_token": newHold.HoldToken}).Error
+		if err != nil {
+			return err
+		}
+		flight.TotalAvailableSeats -= newHold.SeatsHeld
+		if err := r.updateSeats(tx, flight); err != nil {
+			return err
+		}
+		output = flight
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return &newHold, output, nil
+}
+
+func (r *GormFlightRepository) GetHolds() ([]*dao.Hold, error) {
+	holds := make([]*dao.Hold, 0)
+	if err := r.db.Find(&holds).Error; err != nil {
+		return nil, err
+	}
+	for _, hold := range holds {
+		if err := r.getHoldSeatLabels(r.db, hold); err != nil {
+			return nil, err
+		}
+	}
+	return holds, nil
+}
+
+func (r *GormFlightRepository) ConfirmHold(holdToken string, clientAddress string, reservationTime int64) (*dao.Reservation, *dao.Flight, error) {
+	var reservation *dao.Reservation
+	var flight *dao.Flight
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		hold, err := r.getHold(tx, holdToken)
+		if err != nil {
+			return err
+		}
+		if hold.ClientAddress != clientAddress || reservationTime > hold.ExpiryTime {
+			return custom_errors.NewNoSuchHoldTokenError()
+		}
+		flight, err = r.lockBookableFlight(tx, hold.FlightIdentifier)
+		if err != nil {
+			return err
+		}
+		// the delete is conditional so that the hold cannot be both confirmed and released concurrently
+		if err := r.deleteHold(tx, holdToken); err != nil {
+			return err
+		}
+
+		reservation = &dao.Reservation{
+			FlightIdentifier: hold.FlightIdentifier,
+			SeatsReserved:    hold.SeatsHeld,
+			ClientAddress:    hold.ClientAddress,
+			ReservationTime:  reservationTime,
+			SeatLabels:       hold.SeatLabels,
+		}
+		if err := tx.Create(reservation).Error; err != nil {
+			return err
+		}
+		// the seats were already taken off TotalAvailableSeats when they were held
+		return tx.Model(&dao.Seat{}).
+			Where("hold_token = ?", holdToken).
+			Updates(map[string]any{"status": dao.BookedSeatStatus, "booking_identifier": reservation.BookingIdentifier, "hold_token": ""}).Error
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return reservation, flight, nil
+}
+
+func (r *GormFlightRepository) ReleaseHold(holdToken string) (*dao.Hold, *dao.Flight, error) {
+	var hold *dao.Hold
+	var flight *dao.Flight
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		hold, err = r.getHold(tx, holdToken)
+		if err != nil {
+			return err
+		}
+		flight, err = r.lockFlight(tx, hold.FlightIdentifier)
+		if err != nil {
+			return err
+		}
+		if err := r.deleteHold(tx, holdToken); err != nil {
+			return err
+		}
+		err = tx.Model(&dao.Seat{}).
+			Where("hold_token = ?", holdToken).
+			Updates(map[string]any{"status": dao.FreeSeatStatus, "hold_token": ""}).Error
+		if err != nil {
+			return err
+		}
+		flight.TotalAvailableSeats += hold.SeatsHeld
+		return r.updateSeats(tx, flight)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return hold, flight, nil
+}
+
+func (r *GormFlightRepository) CreateSchedule(schedule *dao.Schedule, flights []*dao.Flight) (int32, []int32, error) {
+	newSchedule := *schedule
+	// a zero schedule identifier lets the database assign one
+	newSchedule.ScheduleIdentifier = 0
+	flightIdentifiers := make([]int32, len(flights))
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&newSchedule).Error; err != nil {
+			return err
+		}
+		for i, flight := range flights {
+			newFlight := *flight
+			newFlight.ScheduleIdentifier = newSchedule.ScheduleIdentifier
+			flightIdentifier, err := r.createFlight(tx, &newFlight)
+			if err != nil {
+				return err
+			}
+			flightIdentifiers[i] = flightIdentifier
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	return newSchedule.ScheduleIdentifier, flightIdentifiers, nil
+}
+
+func (r *GormFlightRepository) GetSchedule(scheduleIdentifier int32) (*dao.Schedule, error) {
+	return r.getSchedule(r.db, scheduleIdentifier)
+}
+
+func (r *GormFlightRepository) GetFlightsBySchedule(scheduleIdentifier int32) ([]*dao.Flight, error) {
+	output := make([]*dao.Flight, 0)
+	err := r.db.Where("schedule_identifier = ?", scheduleIdentifier).Order("departure_time").Order("flight_identifier").Find(&output).Error
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+func (r *GormFlightRepository) UpdateSchedule(schedule *dao.Schedule, flights []*dao.Flight) ([]*dao.Flight, error) {
+	output := make([]*dao.Flight, 0)
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := r.getSchedule(tx, schedule.ScheduleIdentifier); err != nil {
+			return err
+		}
+		if err := tx.Save(schedule).Error; err != nil {
+			return err
+		}
+
+		updatedFlightIdentifiers := make([]int32, 0, len(flights))
+		for _, flight := range flights {
+			// the update is conditional so that flights booked or held concurrently are not changed, flights that have been
+			// delayed or cancelled individually are left as they are
+			result := r.unbookedScheduledFlights(tx, schedule).
+				Where("flight_identifier = ? AND status = ?", flight.FlightIdentifier, dao.ScheduledFlightStatus).
+				Updates(map[string]any{
+					"departure_time":          flight.DepartureTime,
+					"arrival_time":            flight.ArrivalTime,
+					"airfare":                 flight.Airfare,
+					"airfare_sequence_number": gorm.Expr("airfare_sequence_number + 1"),
+					"aircraft":                flight.Aircraft,
+				})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected != 0 {
+				updatedFlightIdentifiers = append(updatedFlightIdentifiers, flight.FlightIdentifier)
+			}
+		}
+		if len(updatedFlightIdentifiers) == 0 {
+			return nil
+		}
+		return tx.Where("flight_identifier IN ?", updatedFlightIdentifiers).Order("departure_time").Order("flight_identifier").Find(&output).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+func (r *GormFlightRepository) CancelSchedule(scheduleIdentifier int32, flightIdentifiers []int32) ([]*dao.Flight, error) {
+	output := make([]*dao.Flight, 0)
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		schedule, err := r.getSchedule(tx, scheduleIdentifier)
+		if err != nil {
+			return err
+		}
+		deletedFlights := make([]*dao.Flight, 0)
+		if len(flightIdentifiers) != 0 {
+			// the flights are locked so that they cannot be booked or held while they are deleted
+			err = r.unbookedScheduledFlights(tx.Clauses(clause.Locking{Strength: "UPDATE"}), schedule).
+				Where("flight_identifier IN ?", flightIdentifiers).
+				Order("flight_identifier").
+				Find(&deletedFlights).Error
+			if err != nil {
+				return err
+			}
+		}
+		if len(deletedFlights) != 0 {
+			deletedFlightIdentifiers := make([]int32, len(deletedFlights))
+			for i, flight := range deletedFlights {
+				deletedFlightIdentifiers[i] = flight.FlightIdentifier
+				output = append(output, cancelDeletedFlight(flight))
+			}
+			if err := tx.Delete(&dao.Seat{}, "flight_identifier IN ?", deletedFlightIdentifiers).Error; err != nil {
+				return err
+			}
+			if err := tx.Delete(&dao.Flight{}, "flight_identifier IN ?", deletedFlightIdentifiers).Error; err != nil {
+				return err
+			}
+		}
+		// the flights kept no longer depart under a schedule
+		err = tx.Model(&dao.Flight{}).Where("schedule_identifier = ?", scheduleIdentifier).Update("schedule_identifier", 0).Error
+		if err != nil {
+			return err
+		}
+		return tx.Delete(&dao.Schedule{}, "schedule_identifier = ?", scheduleIdentifier).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// updateSeats stores the TotalAvailableSeats of a locked flight with the next seats sequence number
+func (r *GormFlightRepository) updateSeats(tx *gorm.DB, flight *dao.Flight) error {
+	flight.SeatsSequenceNumber += 1
+	return tx.Model(flight).Updates(map[string]any{
+		"total_available_seats": flight.TotalAvailableSeats,
+		"seats_sequence_number": flight.SeatsSequenceNumber,
+	}).Error
+}
+
+// unbookedScheduledFlights scopes a query to the flights of the schedule with all of their seats available
+func (r *GormFlightRepository) unbookedScheduledFlights(db *gorm.DB, schedule *dao.Schedule) *gorm.DB {
+	return db.Model(&dao.Flight{}).Where("schedule_identifier = ? AND total_available_seats = ?", schedule.ScheduleIdentifier, schedule.TotalSeats)
+}
+
+// getSchedule gets a schedule with the connection or transaction provided
+func (r *GormFlightRepository) getSchedule(db *gorm.DB, scheduleIdentifier int32) (*dao.Schedule, error) {
+	schedule := &dao.Schedule{}
+	err := db.First(schedule, "schedule_identifier = ?", scheduleIdentifier).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, custom_errors.NewNoSuchScheduleIdentifierError()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+func (r *GormFlightRepository) UpdateAirfare(flightIdentifier int32, airfare float64) (*dao.Flight, error) {
+	var output *dao.Flight
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// the flight is locked so that concurrent updates are given consecutive sequence numbers
+		flight, err := r.lockFlight(tx, flightIdentifier)
+		if err != nil {
+			return err
+		}
+		flight.Airfare = airfare
+		flight.AirfareSequenceNumber += 1
+		err = tx.Model(flight).Updates(map[string]any{
+			"airfare":                 flight.Airfare,
+			"airfare_sequence_number": flight.AirfareSequenceNumber,
+		}).Error
+		if err != nil {
+			return err
+		}
+		output = flight
+		return nil
+	})
+	return output, err
+}
+
+func (r *GormFlightRepository) UpdateFlightStatus(flightIdentifier int32, status dao.FlightStatusType, departureTime, arrivalTime int64) (*dao.Flight, error) {
+	var output *dao.Flight
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// the flight is locked so that a reservation cannot be made while it is being cancelled
+		flight, err := r.lockFlight(tx, flightIdentifier)
+		if err != nil {
+			return err
+		}
+		if flight.Status.IsFinal() {
+			return newFinalFlightStatusError(flight)
+		}
+		flight.Status = status
+		flight.StatusSequenceNumber += 1
+		if departureTime != 0 {
+			flight.DepartureTime = departureTime
+		}
+		if arrivalTime != 0 {
+			flight.ArrivalTime = arrivalTime
+		}
+		err = tx.Model(flight).Updates(map[string]any{
+			"status":                 flight.Status,
+			"status_sequence_number": flight.StatusSequenceNumber,
+			"departure_time":         flight.DepartureTime,
+			"arrival_time":           flight.ArrivalTime,
+		}).Error
+		if err != nil {
+			return err
+		}
+		output = flight
+		return nil
+	})
+	return output, err
+}
+
+// getFlight gets a flight with the connection or transaction provided
+func (r *GormFlightRepository) getFlight(db *gorm.DB, flightIdentifier int32) (*dao.Flight, error) {
+	flight := &dao.Flight{}
+	err := db.First(flight, "flight_identifier = ?", flightIdentifier).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, custom_errors.NewNoSuchFlightIdentifierError()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return flight, nil
+}
+
+// lockFlight gets a flight and locks the row until the end of the transaction (SELECT ... FOR UPDATE)
+func (r *GormFlightRepository) lockFlight(tx *gorm.DB, flightIdentifier int32) (*dao.Flight, error) {
+	return r.getFlight(tx.Clauses(clause.Locking{Strength: "UPDATE"}), flightIdentifier)
+}
+
+// lockBookableFlight locks a flight in the same way as lockFlight, returns FlightNotBookableError if seats on it cannot be
+// reserved or held
+func (r *GormFlightRepository) lockBookableFlight(tx *gorm.DB, flightIdentifier int32) (*dao.Flight, error) {
+	flight, err := r.lockFlight(tx, flightIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	if !flight.IsBookable() {
+		return nil, custom_errors.NewFlightNotBookableError(uint8(flight.Status))
+	}
+	return flight, nil
+}
+
+// getReservation gets a reservation along with the labels of its seats with the connection or transaction provided
+func (r *GormFlightRepository) getReservation(db *gorm.DB, bookingIdentifier int32) (*dao.Reservation, error) {
+	reservation := &dao.Reservation{}
+	err := db.First(reservation, "booking_identifier = ?", bookingIdentifier).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, custom_errors.NewNoSuchBookingIdentifierError()
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = db.Model(&dao.Seat{}).
+		Where("booking_identifier = ?", bookingIdentifier).
+		Order("seat_row").
+		Order("seat_letter").
+		Pluck("seat_label", &reservation.SeatLabels).Error
+	if err != nil {
+		return nil, err
+	}
+	return reservation, nil
+}
+
+// getHold gets a hold along with the labels of its seats with the connection or transaction provided
+func (r *GormFlightRepository) getHold(db *gorm.DB, holdToken string) (*dao.Hold, error) {
+	hold := &dao.Hold{}
+	err := db.First(hold, "hold_token = ?", holdToken).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, custom_errors.NewNoSuchHoldTokenError()
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := r.getHoldSeatLabels(db, hold); err != nil {
+		return nil, err
+	}
+	return hold, nil
+}
+
+// getHoldSeatLabels fills in the labels of the seats held under the hold
+func (r *GormFlightRepository) getHoldSeatLabels(db *gorm.DB, hold *dao.Hold) error {
+	return db.Model(&dao.Seat{}).
+		Where("hold_token = ?", hold.HoldToken).
+		Order("seat_row").
+		Order("seat_letter").
+		Pluck("seat_label", &hold.SeatLabels).Error
+}
+
+// deleteHold deletes a hold, returns NoSuchHoldTokenError if it was already deleted by a concurrent transaction
+func (r *GormFlightRepository) deleteHold(tx *gorm.DB, holdToken string) error {
+	result := tx.Delete(&dao.Hold{}, "hold_token = ?", holdToken)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return custom_errors.NewNoSuchHoldTokenError()
+	}
+	return nil
+}
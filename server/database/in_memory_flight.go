@@ -0,0 +1,560 @@
+package database
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/cyiafn/flight_information_system/server/custom_errors"
+	"github.com/cyiafn/flight_information_system/server/dao"
+)
+
+/*
+Flights are stored in a map by flight identifier, with a secondary index on (SourceLocation, DestinationLocation) so that
+queries do not need a linear scan.
+
+This is CONCURRENT-SAFE, every UDP datagram is handled in its own goroutine so all reads and writes are guarded by a
+single RWMutex. Checks and updates (e.g. seat availability and the reservation) are done while holding the write lock so
+that they are atomic.
+*/
+
+// Validate interface compliance at compile time.
+var _ FlightRepository = (*InMemoryFlightRepository)(nil)
+
+// InMemoryFlightRepository is our in-memory "db" to store flights
+type InMemoryFlightRepository struct {
+	sync.RWMutex
+	// flights are all the flights stored, keyed by flight identifier
+	flights map[int32]*dao.Flight
+	// flightIdentifiers are all flight identifiers in ascending order
+	flightIdentifiers []int32
+	// routeIndex is the secondary index of flight identifiers in ascending order, keyed by source and destination
+	routeIndex map[route][]int32
+	// this emulates the auto-incrementing PK function of some databases
+	largestFlightID int32
+	// seatMaps are the seats of each flight, keyed by flight identifier
+	seatMaps map[int32]*seatMap
+	// reservations are all the reservations made, keyed by booking identifier
+	reservations map[int32]*dao.Reservation
+	// this emulates the auto-incrementing PK function of some databases
+	largestBookingID int32
+	// holds are all the holds not yet confirmed or released, keyed by hold token
+	holds map[string]*dao.Hold
+	// schedules are all the schedules not yet cancelled, keyed by schedule identifier
+	schedules map[int32]*dao.Schedule
+	// scheduleIndex is the secondary index of flight identifiers in ascending order, keyed by schedule identifier
+	scheduleIndex map[int32][]int32
+	// this emulates the auto-incrementing PK function of some databases
+	largestScheduleID int32
+}
+
+// route is the key of the secondary index
+type route struct {
+	SourceLocation      string
+	DestinationLocation string
+}
+
+// seatMap stores the seats of a flight ordered by row and letter, with a lookup by seat label
+type seatMap struct {
+	Seats   []*dao.Seat
+	ByLabel map[string]*dao.Seat
+}
+
+// NewInMemoryFlightRepository instantiates an empty in-memory flight store
+func NewInMemoryFlightRepository() *InMemoryFlightRepository {
+	return &InMemoryFlightRepository{
+		flights:           make(map[int32]*dao.Flight),
+		flightIdentifiers: make([]int32, 0),
+		routeIndex:        make(map[route][]int32),
+		seatMaps:          make(map[int32]*seatMap),
+		reservations:      make(map[int32]*dao.Reservation),
+		holds:             make(map[string]*dao.Hold),
+		schedules:         make(map[int32]*dao.Schedule),
+		scheduleIndex:     make(map[int32][]int32),
+	}
+}
+
+func (r *InMemoryFlightRepository) GetFlight(flightIdentifier int32) (*dao.Flight, error) {
+	r.RLock()
+	defer r.RUnlock()
+	flight, err := r.getFlight(flightIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	return copyFlight(flight), nil
+}
+
+func (r *InMemoryFlightRepository) GetAllFlights() ([]*dao.Flight, error) {
+	r.RLock()
+	defer r.RUnlock()
+	return r.copyFlights(r.flightIdentifiers), nil
+}
+
+func (r *InMemoryFlightRepository) GetFlightsBySourceAndDestination(sourceLocation, destinationLocation string) ([]*dao.Flight, error) {
+	r.RLock()
+	defer r.RUnlock()
+	return r.copyFlights(r.routeIndex[route{SourceLocation: sourceLocation, DestinationLocation: destinationLocation}]), nil
+}
+
+func (r *InMemoryFlightRepository) SearchFlights(criteria *FlightSearchCriteria) ([]*dao.Flight, int32, error) {
+	r.RLock()
+	defer r.RUnlock()
+	// the secondary index narrows down the flights to check if both locations are provided
+	flightIdentifiers := r.flightIdentifiers
+	if criteria.SourceLocation != "" && criteria.DestinationLocation != "" {
+		flightIdentifiers = r.routeIndex[route{SourceLocation: criteria.SourceLocation, DestinationLocation: criteria.DestinationLocation}]
+	}
+
+	matches := make([]*dao.Flight, 0)
+	for _, flightIdentifier := range flightIdentifiers {
+		if flight := r.flights[flightIdentifier]; criteria.matches(flight) {
+			matches = append(matches, flight)
+		}
+	}
+	criteria.sortFlights(matches)
+
+	page := criteria.page(matches)
+	output := make([]*dao.Flight, len(page))
+	for i, flight := range page {
+		output[i] = copyFlight(flight)
+	}
+	return output, int32(len(matches)), nil
+}
+
+func (r *InMemoryFlightRepository) CreateFlight(flight *dao.Flight) (int32, error) {
+	r.Lock()
+	defer r.Unlock()
+	return r.createFlight(flight), nil
+}
+
+// createFlight stores a copy of the flight and its seat map, returns the flight identifier. The lock must be held by the caller.
+func (r *InMemoryFlightRepository) createFlight(flight *dao.Flight) int32 {
+	r.largestFlightID += 1
+	newFlight := copyFlight(flight)
+	newFlight.FlightIdentifier = r.largestFlightID
+	if newFlight.Status == 0 {
+		newFlight.Status = dao.ScheduledFlightStatus
+	}
+
+	// flight identifiers only increase, so appending keeps the indexes in ascending order
+	r.flights[newFlight.FlightIdentifier] = newFlight
+	r.flightIdentifiers = append(r.flightIdentifiers, newFlight.FlightIdentifier)
+	key := route{SourceLocation: newFlight.SourceLocation, DestinationLocation: newFlight.DestinationLocation}
+	r.routeIndex[key] = append(r.routeIndex[key], newFlight.FlightIdentifier)
+	if newFlight.ScheduleIdentifier != 0 {
+		r.scheduleIndex[newFlight.ScheduleIdentifier] = append(r.scheduleIndex[newFlight.ScheduleIdentifier], newFlight.FlightIdentifier)
+	}
+
+	seats := &seatMap{
+		Seats:   newSeatMap(newFlight.FlightIdentifier, newFlight.TotalAvailableSeats),
+		ByLabel: make(map[string]*dao.Seat),
+	}
+	for _, seat := range seats.Seats {
+		seats.ByLabel[seat.SeatLabel] = seat
+	}
+	r.seatMaps[newFlight.FlightIdentifier] = seats
+	return newFlight.FlightIdentifier
+}
+
+// deleteFlight deletes the flight from the store and every index. The lock must be held by the caller.
+func (r *InMemoryFlightRepository) deleteFlight(flight *dao.Flight) {
+	delete(r.flights, flight.FlightIdentifier)
+	delete(r.seatMaps, flight.FlightIdentifier)
+	r.flightIdentifiers = removeFlightIdentifier(r.flightIdentifiers, flight.FlightIdentifier)
+	key := route{SourceLocation: flight.SourceLocation, DestinationLocation: flight.DestinationLocation}
+	r.routeIndex[key] = removeFlightIdentifier(r.routeIndex[key], flight.FlightIdentifier)
+	if flight.ScheduleIdentifier != 0 {
+		r.scheduleIndex[flight.ScheduleIdentifier] = removeFlightIdentifier(r.scheduleIndex[flight.ScheduleIdentifier], flight.FlightIdentifier)
+	}
+}
+
+func (r *InMemoryFlightRepository) GetSeatMap(flightIdentifier int32) ([]*dao.Seat, error) {
+	r.RLock()
+	defer r.RUnlock()
+	if _, err := r.getFlight(flightIdentifier); err != nil {
+		return nil, err
+	}
+	seats := r.seatMaps[flightIdentifier].Seats
+	output := make([]*dao.Seat, len(seats))
+	for i, seat := range seats {
+		output[i] = copySeat(seat)
+	}
+	return output, nil
+}
+
+func (r *InMemoryFlightRepository) MakeReservation(reservation *dao.Reservation) (*dao.Reservation, *dao.Flight, error) {
+	r.Lock()
+	defer r.Unlock()
+	flight, err := r.getBookableFlight(reservation.FlightIdentifier)
+	if err != nil {
+		return nil, nil, err
+	}
+	seats, err := r.getFreeSeats(reservation.FlightIdentifier, reservation.SeatsReserved, reservation.SeatLabels)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r.largestBookingID += 1
+	newReservation := copyReservation(reservation)
+	newReservation.BookingIdentifier = r.largestBookingID
+	newReservation.SeatsReserved = int32(len(seats))
+	newReservation.SeatLabels = make([]string, len(seats))
+	for i, seat := range seats {
+		seat.Status = dao.BookedSeatStatus
+		seat.BookingIdentifier = newReservation.BookingIdentifier
+		newReservation.SeatLabels[i] = seat.SeatLabel
+	}
+	flight.TotalAvailableSeats -= newReservation.SeatsReserved
+	flight.SeatsSequenceNumber += 1
+	r.reservations[newReservation.BookingIdentifier] = newReservation
+
+	return copyReservation(newReservation), copyFlight(flight), nil
+}
+
+// getFreeSeats gets the seats requested if all of them are free, or the first numberOfSeats free seats if no seats are
+// requested. The lock must be held by the caller.
+func (r *InMemoryFlightRepository) getFreeSeats(flightIdentifier int32, numberOfSeats int32, seatLabels []string) ([]*dao.Seat, error) {
+	seats := r.seatMaps[flightIdentifier]
+	if len(seatLabels) == 0 {
+		// the number of seats comes from the request, so it is not used as the capacity
+		output := make([]*dao.Seat, 0)
+		for _, seat := range seats.Seats {
+			if int32(len(output)) == numberOfSeats {
+				break
+			}
+			if seat.Status == dao.FreeSeatStatus {
+				output = append(output, seat)
+			}
+		}
+		if int32(len(output)) < numberOfSeats {
+			return nil, custom_errors.NewInsufficientNumberOfAvailableSeatsError()
+		}
+		return output, nil
+	}
+
+	output := make([]*dao.Seat, 0, len(seatLabels))
+	unavailableSeatLabels := make([]string, 0)
+	for _, seatLabel := range uniqueSeatLabels(seatLabels) {
+		seat, ok := seats.ByLabel[seatLabel]
+		if !ok || seat.Status != dao.FreeSeatStatus {
+			unavailableSeatLabels = append(unavailableSeatLabels, seatLabel)
+			continue
+		}
+		output = append(output, seat)
+	}
+	if len(unavailableSeatLabels) != 0 {
+		return nil, custom_errors.NewSeatsUnavailableError(unavailableSeatLabels)
+	}
+	return output, nil
+}
+
+func (r *InMemoryFlightRepository) GetReservation(bookingIdentifier int32) (*dao.Reservation, error) {
+	r.RLock()
+	defer r.RUnlock()
+	reservation, ok := r.reservations[bookingIdentifier]
+	if !ok {
+		return nil, custom_errors.NewNoSuchBookingIdentifierError()
+	}
+	return copyReservation(reservation), nil
+}
+
+func (r *InMemoryFlightRepository) CancelReservation(bookingIdentifier int32) (*dao.Reservation, *dao.Flight, error) {
+	r.Lock()
+	defer r.Unlock()
+	reservation, ok := r.reservations[bookingIdentifier]
+	if !ok {
+		return nil, nil, custom_errors.NewNoSuchBookingIdentifierError()
+	}
+	flight, err := r.getFlight(reservation.FlightIdentifier)
+	if err != nil {
+		return nil, nil, err
+	}
+	seats := r.seatMaps[reservation.FlightIdentifier]
+	for _, seatLabel := range reservation.SeatLabels {
+		seats.ByLabel[seatLabel].Status = dao.FreeSeatStatus
+		seats.ByLabel[seatLabel].BookingIdentifier = 0
+	}
+	flight.TotalAvailableSeats += reservation.SeatsReserved
+	flight.SeatsSequenceNumber += 1
+	delete(r.reservations, bookingIdentifier)
+	return reservation, copyFlight(flight), nil
+}
+
+func (r *InMemoryFlightRepository) HoldSeats(hold *dao.Hold) (*dao.Hold, *dao.Flight, error) {
+	r.Lock()
+	defer r.Unlock()
+	flight, err := r.getBookableFlight(hold.FlightIdentifier)
+	if err != nil {
+		return nil, nil, err
+	}
+	seats, err := r.getFreeSeats(hold.FlightIdentifier, hold.SeatsHeld, hold.SeatLabels)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	newHold := copyHold(hold)
+	newHold.SeatsHeld = int32(len(seats))
+	newHold.SeatLabels = make([]string, len(seats))
+	for i, seat := range seats {
+		seat.Status = dao.HeldSeatStatus
+		seat.HoldToken = newHold.HoldToken
+		newHold.SeatLabels[i] = seat.SeatLabel
+	}
+	flight.TotalAvailableSeats -= newHold.SeatsHeld
+	flight.SeatsSequenceNumber += 1
+	r.holds[newHold.HoldToken] = newHold
+
+	return copyHold(newHold), copyFlight(flight), nil
+}
+
+func (r *InMemoryFlightRepository) GetHolds() ([]*dao.Hold, error) {
+	r.RLock()
+	defer r.RUnlock()
+	output := make([]*dao.Hold, 0, len(r.holds))
+	for _, hold := range r.holds {
+		output = append(output, copyHold(hold))
+	}
+	return output, nil
+}
+
+func (r *InMemoryFlightRepository) ConfirmHold(holdToken string, clientAddress string, reservationTime int64) (*dao.Reservation, *dao.Flight, error) {
+	r.Lock()
+	defer r.Unlock()
+	hold, ok := r.holds[holdToken]
+	if !ok || hold.ClientAddress != clientAddress || reservationTime > hold.ExpiryTime {
+		return nil, nil, custom_errors.NewNoSuchHoldTokenError()
+	}
+	flight, err := r.getBookableFlight(hold.FlightIdentifier)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r.largestBookingID += 1
+	reservation := &dao.Reservation{
+		BookingIdentifier: r.largestBookingID,
+		FlightIdentifier:  hold.FlightIdentifier,
+		SeatsReserved:     hold.SeatsHeld,
+		ClientAddress:     hold.ClientAddress,
+		ReservationTime:   reservationTime,
+		SeatLabels:        hold.SeatLabels,
+	}
+	seats := r.seatMaps[hold.FlightIdentifier]
+	for _, seatLabel := range hold.SeatLabels {
+		seats.ByLabel[seatLabel].Status = dao.BookedSeatStatus
+		seats.ByLabel[seatLabel].BookingIdentifier = reservation.BookingIdentifier
+		seats.ByLabel[seatLabel].HoldToken = ""
+	}
+	// the seats were already taken off TotalAvailableSeats when they were held
+	delete(r.holds, holdToken)
+	r.reservations[reservation.BookingIdentifier] = reservation
+
+	return copyReservation(reservation), copyFlight(flight), nil
+}
+
+func (r *InMemoryFlightRepository) ReleaseHold(holdToken string) (*dao.Hold, *dao.Flight, error) {
+	r.Lock()
+	defer r.Unlock()
+	hold, ok := r.holds[holdToken]
+	if !ok {
+		return nil, nil, custom_errors.NewNoSuchHoldTokenError()
+	}
+	flight, err := r.getFlight(hold.FlightIdentifier)
+	if err != nil {
+		return nil, nil, err
+	}
+	seats := r.seatMaps[hold.FlightIdentifier]
+	for _, seatLabel := range hold.SeatLabels {
+		seats.ByLabel[seatLabel].Status = dao.FreeSeatStatus
+		seats.ByLabel[seatLabel].HoldToken = ""
+	}
+	flight.TotalAvailableSeats += hold.SeatsHeld
+	flight.SeatsSequenceNumber += 1
+	delete(r.holds, holdToken)
+	return hold, copyFlight(flight), nil
+}
+
+func (r *InMemoryFlightRepository) CreateSchedule(schedule *dao.Schedule, flights []*dao.Flight) (int32, []int32, error) {
+	r.Lock()
+	defer r.Unlock()
+	r.largestScheduleID += 1
+	newSchedule := *schedule
+	newSchedule.ScheduleIdentifier = r.largestScheduleID
+	r.schedules[newSchedule.ScheduleIdentifier] = &newSchedule
+
+	flightIdentifiers := make([]int32, len(flights))
+	for i, flight := range flights {
+		newFlight := copyFlight(flight)
+		newFlight.ScheduleIdentifier = newSchedule.ScheduleIdentifier
+		flightIdentifiers[i] = r.createFlight(newFlight)
+	}
+	return newSchedule.ScheduleIdentifier, flightIdentifiers, nil
+}
+
+func (r *InMemoryFlightRepository) GetSchedule(scheduleIdentifier int32) (*dao.Schedule, error) {
+	r.RLock()
+	defer r.RUnlock()
+	schedule, ok := r.schedules[scheduleIdentifier]
+	if !ok {
+		return nil, custom_errors.NewNoSuchScheduleIdentifierError()
+	}
+	output := *schedule
+	return &output, nil
+}
+
+func (r *InMemoryFlightRepository) GetFlightsBySchedule(scheduleIdentifier int32) ([]*dao.Flight, error) {
+	r.RLock()
+	defer r.RUnlock()
+	output := r.copyFlights(r.scheduleIndex[scheduleIdentifier])
+	sort.SliceStable(output, func(i, j int) bool { return output[i].DepartureTime < output[j].DepartureTime })
+	return output, nil
+}
+
+func (r *InMemoryFlightRepository) UpdateSchedule(schedule *dao.Schedule, flights []*dao.Flight) ([]*dao.Flight, error) {
+	r.Lock()
+	defer r.Unlock()
+	if _, ok := r.schedules[schedule.ScheduleIdentifier]; !ok {
+		return nil, custom_errors.NewNoSuchScheduleIdentifierError()
+	}
+	newSchedule := *schedule
+	r.schedules[newSchedule.ScheduleIdentifier] = &newSchedule
+
+	output := make([]*dao.Flight, 0, len(flights))
+	for _, flight := range flights {
+		storedFlight, ok := r.flights[flight.FlightIdentifier]
+		// flights that have been delayed or cancelled individually are left as they are
+		if !ok || storedFlight.Status != dao.ScheduledFlightStatus || !isUnbookedScheduledFlight(storedFlight, &newSchedule) {
+			continue
+		}
+		storedFlight.DepartureTime = flight.DepartureTime
+		storedFlight.ArrivalTime = flight.ArrivalTime
+		storedFlight.Airfare = flight.Airfare
+		storedFlight.AirfareSequenceNumber += 1
+		storedFlight.Aircraft = flight.Aircraft
+		output = append(output, copyFlight(storedFlight))
+	}
+	return output, nil
+}
+
+func (r *InMemoryFlightRepository) CancelSchedule(scheduleIdentifier int32, flightIdentifiers []int32) ([]*dao.Flight, error) {
+	r.Lock()
+	defer r.Unlock()
+	schedule, ok := r.schedules[scheduleIdentifier]
+	if !ok {
+		return nil, custom_errors.NewNoSuchScheduleIdentifierError()
+	}
+
+	output := make([]*dao.Flight, 0, len(flightIdentifiers))
+	for _, flightIdentifier := range flightIdentifiers {
+		flight, ok := r.flights[flightIdentifier]
+		if !ok || !isUnbookedScheduledFlight(flight, schedule) {
+			continue
+		}
+		r.deleteFlight(flight)
+		output = append(output, cancelDeletedFlight(flight))
+	}
+	// the flights kept no longer depart under a schedule
+	for _, flightIdentifier := range r.scheduleIndex[scheduleIdentifier] {
+		r.flights[flightIdentifier].ScheduleIdentifier = 0
+	}
+	delete(r.scheduleIndex, scheduleIdentifier)
+	delete(r.schedules, scheduleIdentifier)
+	return output, nil
+}
+
+func (r *InMemoryFlightRepository) UpdateAirfare(flightIdentifier int32, airfare float64) (*dao.Flight, error) {
+	r.Lock()
+	defer r.Unlock()
+	flight, err := r.getFlight(flightIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	flight.Airfare = airfare
+	flight.AirfareSequenceNumber += 1
+	return copyFlight(flight), nil
+}
+
+func (r *InMemoryFlightRepository) UpdateFlightStatus(flightIdentifier int32, status dao.FlightStatusType, departureTime, arrivalTime int64) (*dao.Flight, error) {
+	r.Lock()
+	defer r.Unlock()
+	flight, err := r.getFlight(flightIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	if flight.Status.IsFinal() {
+		return nil, newFinalFlightStatusError(flight)
+	}
+	flight.Status = status
+	flight.StatusSequenceNumber += 1
+	if departureTime != 0 {
+		flight.DepartureTime = departureTime
+	}
+	if arrivalTime != 0 {
+		flight.ArrivalTime = arrivalTime
+	}
+	return copyFlight(flight), nil
+}
+
+// getBookableFlight returns the stored flight if seats on it can be reserved or held. The lock must be held by the caller.
+func (r *InMemoryFlightRepository) getBookableFlight(flightIdentifier int32) (*dao.Flight, error) {
+	flight, err := r.getFlight(flightIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	if !flight.IsBookable() {
+		return nil, custom_errors.NewFlightNotBookableError(uint8(flight.Status))
+	}
+	return flight, nil
+}
+
+// getFlight returns the stored flight, this must not be returned to callers of the repository. The lock must be held by the caller.
+func (r *InMemoryFlightRepository) getFlight(flightIdentifier int32) (*dao.Flight, error) {
+	flight, ok := r.flights[flightIdentifier]
+	if !ok {
+		return nil, custom_errors.NewNoSuchFlightIdentifierError()
+	}
+	return flight, nil
+}
+
+// copyFlights copies the flights of the flight identifiers provided. The lock must be held by the caller.
+func (r *InMemoryFlightRepository) copyFlights(flightIdentifiers []int32) []*dao.Flight {
+	output := make([]*dao.Flight, len(flightIdentifiers))
+	for i, flightIdentifier := range flightIdentifiers {
+		output[i] = copyFlight(r.flights[flightIdentifier])
+	}
+	return output
+}
+
+// isUnbookedScheduledFlight checks if the flight belongs to the schedule and has all of its seats available
+func isUnbookedScheduledFlight(flight *dao.Flight, schedule *dao.Schedule) bool {
+	return flight.ScheduleIdentifier == schedule.ScheduleIdentifier && flight.TotalAvailableSeats == schedule.TotalSeats
+}
+
+// removeFlightIdentifier removes a flight identifier from an index, keeping the index in ascending order
+func removeFlightIdentifier(flightIdentifiers []int32, flightIdentifier int32) []int32 {
+	output := make([]int32, 0, len(flightIdentifiers))
+	for _, v := range flightIdentifiers {
+		if v != flightIdentifier {
+			output = append(output, v)
+		}
+	}
+	return output
+}
+
+// copyReservation makes a copy of the reservation so that callers cannot mutate the stored reservation
+func copyReservation(reservation *dao.Reservation) *dao.Reservation {
+	output := *reservation
+	output.SeatLabels = append([]string(nil), reservation.SeatLabels...)
+	return &output
+}
+
+// copyHold makes a copy of the hold so that callers cannot mutate the stored hold
+func copyHold(hold *dao.Hold) *dao.Hold {
+	output := *hold
+	output.SeatLabels = append([]string(nil), hold.SeatLabels...)
+	return &output
+}
+
+// copyFlight makes a copy of the flight so that callers cannot mutate the stored flight
+func copyFlight(flight *dao.Flight) *dao.Flight {
+	output := *flight
+	return &output
+}
@@ -0,0 +1,103 @@
+package database
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/cyiafn/flight_information_system/server/custom_errors"
+	"github.com/cyiafn/flight_information_system/server/dao"
+	"github.com/stretchr/testify/assert"
+)
+
+// These tests are meant to be run with the race detector, i.e. go test -race ./database/...
+
+func TestInMemoryFlightRepositoryConcurrentReservations(t *testing.T) {
+	tests := []struct {
+		Name           string
+		TotalSeats     int32
+		Clients        int
+		SeatsPerClient int32
+	}{
+		{
+			Name:           "more seats requested than available",
+			TotalSeats:     100,
+			Clients:        500,
+			SeatsPerClient: 3,
+		},
+		{
+			Name:           "exactly enough seats",
+			TotalSeats:     200,
+			Clients:        100,
+			SeatsPerClient: 2,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			repository := NewInMemoryFlightRepository()
+			id, err := repository.CreateFlight(&dao.Flight{SourceLocation: "Singapore", DestinationLocation: "Bali", TotalAvailableSeats: test.TotalSeats})
+			assert.Nil(t, err)
+
+			var reserved, rejected int32
+			var wg sync.WaitGroup
+			for i := 0; i < test.Clients; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					_, _, err := repository.MakeReservation(&dao.Reservation{FlightIdentifier: id, SeatsReserved: test.SeatsPerClient})
+					if err == nil {
+						atomic.AddInt32(&reserved, test.SeatsPerClient)
+						return
+					}
+					assert.IsType(t, &custom_errors.InsufficientNumberOfAvailableSeatsError{}, err)
+					atomic.AddInt32(&rejected, 1)
+				}()
+				// readers and other writers contend for the same flight
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					flight, err := repository.GetFlight(id)
+					assert.Nil(t, err)
+					assert.GreaterOrEqual(t, flight.TotalAvailableSeats, int32(0))
+					_, _ = repository.UpdateAirfare(id, float64(flight.TotalAvailableSeats))
+					_, _ = repository.GetFlightsBySourceAndDestination("Singapore", "Bali")
+				}()
+			}
+			wg.Wait()
+
+			flight, err := repository.GetFlight(id)
+			assert.Nil(t, err)
+			// no overselling, and every seat not reserved is still available
+			assert.LessOrEqual(t, reserved, test.TotalSeats)
+			assert.Equal(t, test.TotalSeats-reserved, flight.TotalAvailableSeats)
+			assert.Less(t, flight.TotalAvailableSeats, test.SeatsPerClient)
+			assert.Equal(t, int32(test.Clients), reserved/test.SeatsPerClient+rejected)
+		})
+	}
+}
+
+func TestInMemoryFlightRepositoryConcurrentCreates(t *testing.T) {
+	repository := NewInMemoryFlightRepository()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 200; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := repository.CreateFlight(&dao.Flight{SourceLocation: "Tokyo", DestinationLocation: "Seoul"})
+			assert.Nil(t, err)
+			_, err = repository.GetAllFlights()
+			assert.Nil(t, err)
+		}()
+	}
+	wg.Wait()
+
+	// every flight gets a unique flight identifier and the indexes stay in ascending order
+	flights, err := repository.GetFlightsBySourceAndDestination("Tokyo", "Seoul")
+	assert.Nil(t, err)
+	assert.Len(t, flights, 200)
+	for i, flight := range flights {
+		assert.Equal(t, int32(i+1), flight.FlightIdentifier)
+	}
+}
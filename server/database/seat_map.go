@@ -0,0 +1,63 @@
+package database
+
+import (
+	"fmt"
+
+	"github.com/cyiafn/flight_information_system/server/dao"
+)
+
+const (
+	// seatLetters are the seats in each row
+	seatLetters = "ABCDEF"
+	// businessCabinRowsDivisor is used to allocate the front 1/5 of the rows to the business cabin
+	businessCabinRowsDivisor = 5
+)
+
+// newSeatMap generates the seats of a new flight. Seats are allocated row by row, 6 seats a row, so the last row may not be full.
+func newSeatMap(flightIdentifier int32, totalSeats int32) []*dao.Seat {
+	if totalSeats <= 0 {
+		return nil
+	}
+	seatsPerRow := int32(len(seatLetters))
+	totalRows := (totalSeats + seatsPerRow - 1) / seatsPerRow
+	businessRows := totalRows / businessCabinRowsDivisor
+
+	seats := make([]*dao.Seat, totalSeats)
+	for i := int32(0); i < totalSeats; i++ {
+		row := i/seatsPerRow + 1
+		letter := string(seatLetters[i%seatsPerRow])
+		cabinClass := dao.EconomyCabinClass
+		if row <= businessRows {
+			cabinClass = dao.BusinessCabinClass
+		}
+		seats[i] = &dao.Seat{
+			FlightIdentifier: flightIdentifier,
+			SeatLabel:        fmt.Sprintf("%d%s", row, letter),
+			SeatRow:          row,
+			SeatLetter:       letter,
+			CabinClass:       cabinClass,
+			Status:           dao.FreeSeatStatus,
+		}
+	}
+	return seats
+}
+
+// uniqueSeatLabels removes duplicate seat labels while keeping the order
+func uniqueSeatLabels(seatLabels []string) []string {
+	output := make([]string, 0, len(seatLabels))
+	seen := make(map[string]struct{}, len(seatLabels))
+	for _, seatLabel := range seatLabels {
+		if _, ok := seen[seatLabel]; ok {
+			continue
+		}
+		seen[seatLabel] = struct{}{}
+		output = append(output, seatLabel)
+	}
+	return output
+}
+
+// copySeat makes a copy of the seat so that callers cannot mutate the stored seat
+func copySeat(seat *dao.Seat) *dao.Seat {
+	output := *seat
+	return &output
+}
@@ -1,6 +1,9 @@
 package status_code
 
-import "github.com/cyiafn/flight_information_system/server/custom_errors"
+import (
+	"github.com/cyiafn/flight_information_system/server/custom_errors"
+	"github.com/pkg/errors"
+)
 
 // StatusCodeType are the types possible for errors.
 type StatusCodeType uint8
@@ -17,6 +20,13 @@ const (
 	NoMatchForSourceAndDestination
 	NoSuchFlightIdentifier
 	InsufficientNumberOfAvailableSeats
+	NoSuchBookingIdentifier
+	SeatsUnavailable
+	InvalidRequest
+	NoSuchHoldToken
+	NoSuchScheduleIdentifier
+	FlightNotBookable
+	NoSuchSubscriptionIdentifier
 )
 
 // GetStatusCode error maps the type of error to the statusCode to return
@@ -33,7 +43,53 @@ func GetStatusCode(err error) StatusCodeType {
 		return NoSuchFlightIdentifier
 	case *custom_errors.InsufficientNumberOfAvailableSeatsError:
 		return InsufficientNumberOfAvailableSeats
+	case *custom_errors.NoSuchBookingIdentifierError:
+		return NoSuchBookingIdentifier
+	case *custom_errors.SeatsUnavailableError:
+		return SeatsUnavailable
+	case *custom_errors.InvalidRequestError:
+		return InvalidRequest
+	case *custom_errors.NoSuchHoldTokenError:
+		return NoSuchHoldToken
+	case *custom_errors.NoSuchScheduleIdentifierError:
+		return NoSuchScheduleIdentifier
+	case *custom_errors.FlightNotBookableError:
+		return FlightNotBookable
+	case *custom_errors.NoSuchSubscriptionIdentifierError:
+		return NoSuchSubscriptionIdentifier
 	default:
 		return BusinessLogicGenericError
 	}
 }
+
+// GetError is the inverse of GetStatusCode, it maps a statusCode received from the server back to the error type. Returns nil on Success
+func GetError(statusCode StatusCodeType) error {
+	switch statusCode {
+	case Success:
+		return nil
+	case MarshallerError:
+		return custom_errors.NewMarshallerError(errors.Errorf("server was unable to marshal or unmarshal the request"))
+	case NoMatchForSourceAndDestination:
+		return custom_errors.NewNoMatchForSourceAndDestinationError(nil, nil)
+	case NoSuchFlightIdentifier:
+		return custom_errors.NewNoSuchFlightIdentifierError()
+	case InsufficientNumberOfAvailableSeats:
+		return custom_errors.NewInsufficientNumberOfAvailableSeatsError()
+	case NoSuchBookingIdentifier:
+		return custom_errors.NewNoSuchBookingIdentifierError()
+	case SeatsUnavailable:
+		return custom_errors.NewSeatsUnavailableError(nil)
+	case InvalidRequest:
+		return custom_errors.NewInvalidRequestError("")
+	case NoSuchHoldToken:
+		return custom_errors.NewNoSuchHoldTokenError()
+	case NoSuchScheduleIdentifier:
+		return custom_errors.NewNoSuchScheduleIdentifierError()
+	case FlightNotBookable:
+		return custom_errors.NewFlightNotBookableError(0)
+	case NoSuchSubscriptionIdentifier:
+		return custom_errors.NewNoSuchSubscriptionIdentifierError()
+	default:
+		return custom_errors.NewBusinessLogicGenericError()
+	}
+}
@@ -0,0 +1,24 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/cyiafn/flight_information_system/server/callback"
+	"github.com/cyiafn/flight_information_system/server/dto"
+	"github.com/cyiafn/flight_information_system/server/logs"
+	"github.com/cyiafn/flight_information_system/server/server"
+)
+
+// AckCallback acknowledges a callback received by the client of the RPC call so that it is no longer retransmitted.
+// Acks are one way, the server does not reply to them.
+func AckCallback(ctx context.Context, request any) (any, error) {
+	req := request.(*dto.AckCallbackRequest)
+	addr := server.GetIPAddr(ctx)
+
+	// acks of retransmitted callbacks arrive after the callback has already been acked
+	if !callback.Ack(addr, req.RequestID) {
+		logs.Info("callback %s acked by %s is not outstanding", req.RequestID, addr)
+	}
+
+	return nil, nil
+}
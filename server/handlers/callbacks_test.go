@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/cyiafn/flight_information_system/server/custom_errors"
+	"github.com/cyiafn/flight_information_system/server/dao"
+	"github.com/cyiafn/flight_information_system/server/database"
+	"github.com/cyiafn/flight_information_system/server/dto"
+	"github.com/cyiafn/flight_information_system/server/dto/status_code"
+	"github.com/cyiafn/flight_information_system/server/utils/rpc"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	// callbackHeaderSize is the size of the headers of a callback in PositionalSchemaVersion, | type | requestID | no | total |
+	callbackHeaderSize = 1 + 9 + 8 + 8
+	// callbackTimeout is how long a subscriber waits for a callback
+	callbackTimeout = 2 * time.Second
+)
+
+// subscriber is a UDP socket that callbacks are sent to
+type subscriber struct {
+	conn *net.UDPConn
+	// ctx is the context of requests from the subscriber
+	ctx context.Context
+}
+
+// newSubscriber listens on an ephemeral port and stores every flight in a new in memory repository
+func newSubscriber(t *testing.T) *subscriber {
+	database.Init(database.NewInMemoryFlightRepository())
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	t.Cleanup(func() { conn.Close() })
+	return &subscriber{conn: conn, ctx: context.WithValue(context.Background(), "addr", conn.LocalAddr().String())}
+}
+
+// readCallback reads a callback of a single byte array buffer into data and acks it so that it is not retransmitted
+func (s *subscriber) readCallback(t *testing.T, callbackType dto.ResponseType, data any) {
+	buf := make([]byte, 8192)
+	_ = s.conn.SetReadDeadline(time.Now().Add(callbackTimeout))
+	n, err := s.conn.Read(buf)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, uint8(callbackType), buf[0])
+	requestID := string(buf[1:10])
+
+	wrappedResp := &dto.Response{Data: data}
+	assert.Nil(t, rpc.Unmarshal(buf[callbackHeaderSize:n], wrappedResp))
+	assert.Equal(t, status_code.Success, wrappedResp.StatusCode)
+
+	_, err = AckCallback(s.ctx, &dto.AckCallbackRequest{RequestID: requestID})
+	assert.Nil(t, err)
+}
+
+// createTestFlight creates a flight from Singapore to Tokyo departing in a day
+func createTestFlight(t *testing.T) int32 {
+	departureTime := time.Now().Add(24 * time.Hour).Unix()
+	res, err := CreateFlight(context.Background(), &dto.CreateFlightRequest{
+		SourceLocation:      "Singapore",
+		DestinationLocation: "Tokyo",
+		DepartureTime:       departureTime,
+		ArrivalTime:         departureTime + 7*3600,
+		Airfare:             500,
+		TotalAvailableSeats: 100,
+	})
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	return res.(*dto.CreateFlightResponse).FlightIdentifier
+}
+
+func TestPriceUpdatesCallback(t *testing.T) {
+	s := newSubscriber(t)
+	flightIdentifier := createTestFlight(t)
+	_, err := MonitorPriceUpdates(s.ctx, &dto.MonitorPriceUpdatesCallbackRequest{FlightIdentifier: flightIdentifier, LengthOfMonitorIntervalInSeconds: 60})
+	assert.Nil(t, err)
+
+	_, err = UpdateFlightPrice(context.Background(), &dto.UpdateFlightPriceRequest{FlightIdentifier: flightIdentifier, NewPrice: 650})
+	assert.Nil(t, err)
+
+	res := &dto.MonitorPriceUpdatesCallbackResponse{}
+	s.readCallback(t, dto.MonitorPriceUpdatesCallbackType, res)
+	assert.Equal(t, &dto.MonitorPriceUpdatesCallbackResponse{FlightIdentifier: flightIdentifier, Airfare: 650, SequenceNumber: 1}, res)
+}
+
+func TestFlightStatusCallback(t *testing.T) {
+	s := newSubscriber(t)
+	flightIdentifier := createTestFlight(t)
+	flight, err := database.GetFlightRepository().GetFlight(flightIdentifier)
+	assert.Nil(t, err)
+	_, err = MonitorFlightStatus(s.ctx, &dto.MonitorFlightStatusCallbackRequest{FlightIdentifier: flightIdentifier, LengthOfMonitorIntervalInSeconds: 60})
+	assert.Nil(t, err)
+
+	// delayed by an hour, the flight keeps its duration
+	_, err = UpdateFlightStatus(context.Background(), &dto.UpdateFlightStatusRequest{
+		FlightIdentifier: flightIdentifier,
+		Status:           uint8(dao.DelayedFlightStatus),
+		DepartureTime:    flight.DepartureTime + 3600,
+	})
+	assert.Nil(t, err)
+
+	res := &dto.MonitorFlightStatusCallbackResponse{}
+	s.readCallback(t, dto.MonitorFlightStatusCallbackType, res)
+	assert.Equal(t, &dto.MonitorFlightStatusCallbackResponse{
+		FlightIdentifier: flightIdentifier,
+		Status:           uint8(dao.DelayedFlightStatus),
+		DepartureTime:    flight.DepartureTime + 3600,
+		ArrivalTime:      flight.ArrivalTime + 3600,
+		SequenceNumber:   1,
+	}, res)
+}
+
+func TestNewFlightsCallback(t *testing.T) {
+	s := newSubscriber(t)
+	// any name of the locations subscribes to the route
+	_, err := MonitorNewFlights(s.ctx, &dto.MonitorNewFlightsCallbackRequest{SourceLocation: "SIN", DestinationLocation: "Tokyo", LengthOfMonitorIntervalInSeconds: 60})
+	assert.Nil(t, err)
+
+	flightIdentifier := createTestFlight(t)
+	res := &dto.MonitorNewFlightsCallbackResponse{}
+	s.readCallback(t, dto.MonitorNewFlightsCallbackType, res)
+	assert.Equal(t, flightIdentifier, res.Flight.FlightIdentifier)
+	assert.Equal(t, "Singapore", res.Flight.SourceLocation)
+	assert.Equal(t, "Tokyo", res.Flight.DestinationLocation)
+	assert.Equal(t, 500.0, res.Flight.Airfare)
+	assert.Equal(t, uint8(dao.ScheduledFlightStatus), res.Flight.Status)
+}
+
+func TestNewFlightsCallbackOfSchedule(t *testing.T) {
+	s := newSubscriber(t)
+	_, err := MonitorNewFlights(s.ctx, &dto.MonitorNewFlightsCallbackRequest{SourceLocation: "Seattle", DestinationLocation: "Los Angeles", LengthOfMonitorIntervalInSeconds: 60})
+	assert.Nil(t, err)
+
+	// Monday and Friday, so 3 departures
+	res, err := CreateSchedule(context.Background(), &dto.CreateScheduleRequest{
+		SourceLocation:      "Seattle",
+		DestinationLocation: "Los Angeles",
+		SourceTimezone:      "America/Los_Angeles",
+		DestinationTimezone: "America/Los_Angeles",
+		DaysOfWeek:          1<<time.Monday | 1<<time.Friday,
+		LocalDepartureTime:  "08:30",
+		DurationInSeconds:   9000,
+		StartDate:           "2023-11-01",
+		EndDate:             "2023-11-10",
+		Airfare:             200,
+		TotalSeats:          120,
+	})
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	flightIdentifiers := res.(*dto.CreateScheduleResponse).FlightIdentifiers
+	assert.Len(t, flightIdentifiers, 3)
+
+	// callbacks of the departures are sent concurrently, so they may arrive in any order
+	var notified []int32
+	for range flightIdentifiers {
+		callbackRes := &dto.MonitorNewFlightsCallbackResponse{}
+		s.readCallback(t, dto.MonitorNewFlightsCallbackType, callbackRes)
+		assert.Equal(t, "Seattle", callbackRes.Flight.SourceLocation)
+		assert.Equal(t, 200.0, callbackRes.Flight.Airfare)
+		notified = append(notified, callbackRes.Flight.FlightIdentifier)
+	}
+	assert.ElementsMatch(t, flightIdentifiers, notified)
+}
+
+func TestNegativePriceNotNotified(t *testing.T) {
+	s := newSubscriber(t)
+	flightIdentifier := createTestFlight(t)
+	_, err := MonitorPriceUpdates(s.ctx, &dto.MonitorPriceUpdatesCallbackRequest{FlightIdentifier: flightIdentifier, LengthOfMonitorIntervalInSeconds: 60})
+	assert.Nil(t, err)
+
+	_, err = UpdateFlightPrice(context.Background(), &dto.UpdateFlightPriceRequest{FlightIdentifier: flightIdentifier, NewPrice: -1})
+	assert.IsType(t, &custom_errors.InvalidRequestError{}, err)
+	_, err = CreateFlight(context.Background(), &dto.CreateFlightRequest{
+		SourceLocation:      "Singapore",
+		DestinationLocation: "Tokyo",
+		DepartureTime:       1000,
+		ArrivalTime:         2000,
+		Airfare:             -1,
+		TotalAvailableSeats: 100,
+	})
+	assert.IsType(t, &custom_errors.InvalidRequestError{}, err)
+
+	flight, err := database.GetFlightRepository().GetFlight(flightIdentifier)
+	assert.Nil(t, err)
+	assert.Equal(t, 500.0, flight.Airfare)
+	_ = s.conn.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+	_, err = s.conn.Read(make([]byte, 512))
+	assert.NotNil(t, err, "price update was notified")
+}
@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/cyiafn/flight_information_system/server/dao"
+	"github.com/cyiafn/flight_information_system/server/database"
+	"github.com/cyiafn/flight_information_system/server/dto"
+)
+
+// CancelFlight cancels a flight and notifies the subscribers of the flight. The flight is kept so that its reservations
+// can still be looked up and cancelled, but no more seats can be reserved or held on it.
+func CancelFlight(_ context.Context, request any) (any, error) {
+	req := request.(*dto.CancelFlightRequest)
+
+	flight, err := database.GetFlightRepository().UpdateFlightStatus(req.FlightIdentifier, dao.CancelledFlightStatus, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	handleMonitorFlightStatusCallback(flight)
+
+	return nil, nil
+}
@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/cyiafn/flight_information_system/server/database"
+	"github.com/cyiafn/flight_information_system/server/dto"
+)
+
+// CancelReservation cancels a reservation made by the client and restores the seats reserved to the flight.
+func CancelReservation(ctx context.Context, request any) (any, error) {
+	req := request.(*dto.CancelReservationRequest)
+
+	// the client address of a reservation never changes, so it is checked before the reservation is cancelled
+	if _, err := getClientReservation(ctx, req.BookingIdentifier); err != nil {
+		return nil, err
+	}
+	reservation, flight, err := database.GetFlightRepository().CancelReservation(req.BookingIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	// seats are available again, so subscribers of the flight are notified
+	handleMonitorSeatUpdatesCallback(flight)
+
+	return &dto.CancelReservationResponse{
+		FlightIdentifier:    flight.FlightIdentifier,
+		SeatsCancelled:      reservation.SeatsReserved,
+		TotalAvailableSeats: flight.TotalAvailableSeats,
+	}, nil
+}
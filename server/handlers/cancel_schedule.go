@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/cyiafn/flight_information_system/server/changelog"
+	"github.com/cyiafn/flight_information_system/server/database"
+	"github.com/cyiafn/flight_information_system/server/dto"
+)
+
+// CancelSchedule cancels a schedule along with its future flights that have not been booked or held, flights that have
+// are kept so that existing reservations are honoured. Subscribers of the flights cancelled are notified.
+func CancelSchedule(_ context.Context, request any) (any, error) {
+	req := request.(*dto.CancelScheduleRequest)
+
+	// checks if the schedule exists before looking up its flights
+	if _, err := database.GetFlightRepository().GetSchedule(req.ScheduleIdentifier); err != nil {
+		return nil, err
+	}
+	futureFlights, err := getFutureFlightsOfSchedule(req.ScheduleIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	futureFlightIdentifiers := make([]int32, len(futureFlights))
+	for i, flight := range futureFlights {
+		futureFlightIdentifiers[i] = flight.FlightIdentifier
+	}
+
+	// the repository only deletes the flights that are still not booked or held
+	cancelledFlights, err := database.GetFlightRepository().CancelSchedule(req.ScheduleIdentifier, futureFlightIdentifiers)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &dto.CancelScheduleResponse{
+		FlightIdentifiersCancelled: make([]int32, len(cancelledFlights)),
+		FlightIdentifiersKept:      make([]int32, 0),
+	}
+	cancelled := make(map[int32]bool, len(cancelledFlights))
+	for i, flight := range cancelledFlights {
+		res.FlightIdentifiersCancelled[i] = flight.FlightIdentifier
+		cancelled[flight.FlightIdentifier] = true
+		// subscribers of a deleted flight are told that it is cancelled with no seats left before its changes are forgotten
+		handleMonitorFlightStatusCallback(flight)
+		handleMonitorSeatUpdatesCallback(flight)
+		changelog.Forget(flight.FlightIdentifier)
+	}
+	for _, flightIdentifier := range futureFlightIdentifiers {
+		if !cancelled[flightIdentifier] {
+			res.FlightIdentifiersKept = append(res.FlightIdentifiersKept, flightIdentifier)
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"github.com/cyiafn/flight_information_system/server/database"
+	"github.com/cyiafn/flight_information_system/server/dto"
+	"github.com/cyiafn/flight_information_system/server/server"
+)
+
+// ConfirmHold books the seats of an unexpired hold under a new reservation and returns the booking identifier. Holds can
+// only be confirmed by the client that made them.
+func ConfirmHold(ctx context.Context, request any) (any, error) {
+	req := request.(*dto.ConfirmHoldRequest)
+
+	// the seats were already taken off the flight when they were held, so subscribers are not notified
+	reservation, _, err := database.GetFlightRepository().ConfirmHold(req.HoldToken, server.GetIPAddr(ctx), time.Now().Unix())
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.ConfirmHoldResponse{
+		BookingIdentifier: reservation.BookingIdentifier,
+		SeatLabels:        reservation.SeatLabels,
+	}, nil
+}
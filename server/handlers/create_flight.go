@@ -2,10 +2,14 @@ package handlers
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/cyiafn/flight_information_system/server/custom_errors"
 	"github.com/cyiafn/flight_information_system/server/dao"
 	"github.com/cyiafn/flight_information_system/server/database"
 	"github.com/cyiafn/flight_information_system/server/dto"
+	"github.com/cyiafn/flight_information_system/server/locations"
+	"github.com/cyiafn/flight_information_system/server/logs"
 )
 
 /**
@@ -17,18 +21,49 @@ func CreateFlight(_ context.Context, request any) (any, error) {
 	// we cast the request object to the actual object.
 	req := request.(*dto.CreateFlightRequest)
 	res := &dto.CreateFlightResponse{}
+	if req.ArrivalTime <= req.DepartureTime {
+		return nil, custom_errors.NewInvalidRequestError("arrival time must be after departure time")
+	}
+	if req.TotalAvailableSeats <= 0 || req.TotalAvailableSeats > dao.MaxTotalSeats {
+		return nil, custom_errors.NewInvalidRequestError(fmt.Sprintf("total available seats must be from 1 to %d", dao.MaxTotalSeats))
+	}
+	if req.Airfare < 0 {
+		return nil, custom_errors.NewInvalidRequestError("airfare must not be negative")
+	}
+	sourceTimezone, err := resolveTimezone(req.SourceLocation, req.SourceTimezone)
+	if err != nil {
+		return nil, err
+	}
+	destinationTimezone, err := resolveTimezone(req.DestinationLocation, req.DestinationTimezone)
+	if err != nil {
+		return nil, err
+	}
 
-	id := database.GetLargestFlightID() + 1
-	database.NewFlight(&dao.Flight{
-		FlightIdentifier:    id,
-		SourceLocation:      req.SourceLocation,
-		DestinationLocation: req.DestinationLocation,
+	// flights are stored with the canonical city names so that they can be found by any name of the location
+	id, err := database.GetFlightRepository().CreateFlight(&dao.Flight{
+		SourceLocation:      locations.Normalise(req.SourceLocation),
+		DestinationLocation: locations.Normalise(req.DestinationLocation),
 		DepartureTime:       req.DepartureTime,
 		Airfare:             req.Airfare,
 		TotalAvailableSeats: req.TotalAvailableSeats,
+		ArrivalTime:         req.ArrivalTime,
+		SourceTimezone:      sourceTimezone,
+		DestinationTimezone: destinationTimezone,
+		Aircraft:            req.Aircraft,
 	})
+	if err != nil {
+		return nil, err
+	}
 
 	res.FlightIdentifier = id
 
+	// the stored flight is notified as the repository fills in defaults such as the status
+	flight, err := database.GetFlightRepository().GetFlight(id)
+	if err != nil {
+		logs.Warn("unable to get flight %d created to notify subscribers, err: %v", id, err)
+		return res, nil
+	}
+	handleMonitorNewFlightsCallback(flight)
+
 	return res, nil
 }
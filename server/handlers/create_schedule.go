@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/cyiafn/flight_information_system/server/dao"
+	"github.com/cyiafn/flight_information_system/server/database"
+	"github.com/cyiafn/flight_information_system/server/dto"
+	"github.com/cyiafn/flight_information_system/server/locations"
+	"github.com/cyiafn/flight_information_system/server/logs"
+	"github.com/cyiafn/flight_information_system/server/scheduling"
+)
+
+// CreateSchedule creates a recurring flight, a flight is created for every departure of the schedule up front so that
+// each departure can be searched for and booked like any other flight.
+func CreateSchedule(_ context.Context, request any) (any, error) {
+	req := request.(*dto.CreateScheduleRequest)
+	sourceTimezone, err := resolveTimezone(req.SourceLocation, req.SourceTimezone)
+	if err != nil {
+		return nil, err
+	}
+	destinationTimezone, err := resolveTimezone(req.DestinationLocation, req.DestinationTimezone)
+	if err != nil {
+		return nil, err
+	}
+	schedule := &dao.Schedule{
+		SourceLocation:      locations.Normalise(req.SourceLocation),
+		DestinationLocation: locations.Normalise(req.DestinationLocation),
+		SourceTimezone:      sourceTimezone,
+		DestinationTimezone: destinationTimezone,
+		DaysOfWeek:          req.DaysOfWeek,
+		LocalDepartureTime:  req.LocalDepartureTime,
+		DurationInSeconds:   req.DurationInSeconds,
+		StartDate:           req.StartDate,
+		EndDate:             req.EndDate,
+		Airfare:             req.Airfare,
+		TotalSeats:          req.TotalSeats,
+		Aircraft:            req.Aircraft,
+	}
+	if err := scheduling.Validate(schedule); err != nil {
+		return nil, err
+	}
+
+	departureTimes, err := scheduling.DepartureTimes(schedule)
+	if err != nil {
+		return nil, err
+	}
+	flights := make([]*dao.Flight, len(departureTimes))
+	for i, departureTime := range departureTimes {
+		flights[i] = scheduling.NewFlight(schedule, departureTime)
+	}
+
+	scheduleIdentifier, flightIdentifiers, err := database.GetFlightRepository().CreateSchedule(schedule, flights)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &dto.CreateScheduleResponse{
+		ScheduleIdentifier: scheduleIdentifier,
+		FlightIdentifiers:  flightIdentifiers,
+	}
+
+	// subscribers of the route are notified of every departure created, the stored flights are notified as the
+	// repository fills in defaults such as the status
+	scheduledFlights, err := database.GetFlightRepository().GetFlightsBySchedule(scheduleIdentifier)
+	if err != nil {
+		logs.Warn("unable to get flights of schedule %d created to notify subscribers, err: %v", scheduleIdentifier, err)
+		return res, nil
+	}
+	for _, flight := range scheduledFlights {
+		handleMonitorNewFlightsCallback(flight)
+	}
+
+	return res, nil
+}
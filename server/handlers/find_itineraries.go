@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cyiafn/flight_information_system/server/custom_errors"
+	"github.com/cyiafn/flight_information_system/server/database"
+	"github.com/cyiafn/flight_information_system/server/dto"
+	"github.com/cyiafn/flight_information_system/server/locations"
+	"github.com/cyiafn/flight_information_system/server/routing"
+)
+
+const (
+	// maxItineraryStops caps the number of connections as the number of itineraries grows exponentially with it
+	maxItineraryStops = 3
+	// defaultMinConnectionTimeInSeconds is the minimum connection time if none is requested
+	defaultMinConnectionTimeInSeconds = 60 * 60
+	// defaultItineraryLimit is the number of itineraries returned if none is requested
+	defaultItineraryLimit = 10
+	// maxItineraryLimit caps the number of itineraries returned
+	maxItineraryLimit = 50
+)
+
+// FindItineraries finds routes from source to destination with up to MaxStops connections, ranked by total airfare or
+// total duration. The route graph is built from all flights in the flight store on every request.
+func FindItineraries(_ context.Context, request any) (any, error) {
+	req := request.(*dto.FindItinerariesRequest)
+	criteria, err := newItineraryCriteria(req)
+	if err != nil {
+		return nil, err
+	}
+
+	flights, err := database.GetFlightRepository().GetAllFlights()
+	if err != nil {
+		return nil, err
+	}
+	itineraries := routing.NewRouteGraph(flights).FindItineraries(criteria)
+	if len(itineraries) == 0 {
+		return nil, custom_errors.NewNoMatchForSourceAndDestinationError(locations.Suggest(req.SourceLocation), locations.Suggest(req.DestinationLocation))
+	}
+
+	res := &dto.FindItinerariesResponse{
+		Itineraries: make([]dto.Itinerary, len(itineraries)),
+	}
+	for i, itinerary := range itineraries {
+		res.Itineraries[i] = dto.Itinerary{
+			Legs:          make([]dto.FlightInformation, len(itinerary.Legs)),
+			TotalAirfare:  itinerary.TotalAirfare,
+			TotalDuration: itinerary.TotalDuration,
+		}
+		for j, leg := range itinerary.Legs {
+			res.Itineraries[i].Legs[j] = newFlightInformation(leg)
+		}
+	}
+
+	return res, nil
+}
+
+// newItineraryCriteria validates the request and fills in the defaults of the search
+func newItineraryCriteria(req *dto.FindItinerariesRequest) (*routing.ItineraryCriteria, error) {
+	sourceLocation, destinationLocation := locations.Normalise(req.SourceLocation), locations.Normalise(req.DestinationLocation)
+	if sourceLocation == "" || destinationLocation == "" || sourceLocation == destinationLocation {
+		return nil, custom_errors.NewInvalidRequestError("source and destination locations must be provided and must be different")
+	}
+	if req.MaxStops < 0 || req.MaxStops > maxItineraryStops {
+		return nil, custom_errors.NewInvalidRequestError(fmt.Sprintf("max stops must be between 0 and %d", maxItineraryStops))
+	}
+	rankBy := routing.RankType(req.RankBy)
+	if rankBy == 0 {
+		rankBy = routing.TotalAirfareRank
+	}
+	if rankBy > routing.TotalDurationRank {
+		return nil, custom_errors.NewInvalidRequestError(fmt.Sprintf("unknown rank: %d", req.RankBy))
+	}
+	minConnectionTime := req.MinConnectionTimeInSeconds
+	if minConnectionTime == 0 {
+		minConnectionTime = defaultMinConnectionTimeInSeconds
+	}
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultItineraryLimit
+	}
+	if minConnectionTime < 0 || limit < 0 || limit > maxItineraryLimit {
+		return nil, custom_errors.NewInvalidRequestError(fmt.Sprintf("min connection time must not be negative and limit must be between 1 and %d", maxItineraryLimit))
+	}
+
+	return &routing.ItineraryCriteria{
+		SourceLocation:      sourceLocation,
+		DestinationLocation: destinationLocation,
+		MaxStops:            int(req.MaxStops),
+		MinConnectionTime:   minConnectionTime,
+		RankBy:              rankBy,
+		Limit:               int(limit),
+	}, nil
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cyiafn/flight_information_system/server/custom_errors"
+	"github.com/cyiafn/flight_information_system/server/dao"
+	"github.com/cyiafn/flight_information_system/server/dto"
+	"github.com/cyiafn/flight_information_system/server/locations"
+)
+
+/**
+Flight times are stored and sent as unix time. Each airport has an IANA timezone so that times can also be rendered in
+the local time of the airport, e.g. a flight departing Singapore at 08:00 local time arrives in Bali at 10:30 local time.
+*/
+
+// resolveTimezone validates the IANA timezone of an airport, it defaults to the timezone of the location if it is known
+// or UTC if it is not.
+func resolveTimezone(location, timezone string) (string, error) {
+	if timezone == "" {
+		if knownLocation, ok := locations.Resolve(location); ok {
+			return knownLocation.Timezone, nil
+		}
+		return time.UTC.String(), nil
+	}
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return "", custom_errors.NewInvalidRequestError(fmt.Sprintf("unknown timezone: %s", timezone))
+	}
+	return timezone, nil
+}
+
+// validateTimeFormat checks that the time format requested is known, 0 is treated as UnixTimeFormat
+func validateTimeFormat(timeFormat dto.TimeFormatType) error {
+	if timeFormat > dto.LocalTimeFormat {
+		return custom_errors.NewInvalidRequestError(fmt.Sprintf("unknown time format: %d", timeFormat))
+	}
+	return nil
+}
+
+// localTimes renders the departure and arrival times of the flight in the local time of each airport, both are empty
+// unless LocalTimeFormat is requested
+func localTimes(flight *dao.Flight, timeFormat dto.TimeFormatType) (string, string) {
+	if timeFormat != dto.LocalTimeFormat {
+		return "", ""
+	}
+	return formatLocalTime(flight.DepartureTime, flight.SourceTimezone), formatLocalTime(flight.ArrivalTime, flight.DestinationTimezone)
+}
+
+// formatLocalTime renders unix time in RFC 3339 in the timezone provided, flights stored before timezones were added
+// have no timezone and are rendered in UTC
+func formatLocalTime(unixTime int64, timezone string) string {
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		location = time.UTC
+	}
+	return time.Unix(unixTime, 0).In(location).Format(time.RFC3339)
+}
+
+// flightDuration is the number of seconds from departure to arrival, 0 for flights stored before arrival times were added
+func flightDuration(flight *dao.Flight) int64 {
+	if flight.ArrivalTime == 0 {
+		return 0
+	}
+	return flight.ArrivalTime - flight.DepartureTime
+}
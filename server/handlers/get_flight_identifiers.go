@@ -6,24 +6,28 @@ import (
 	"github.com/cyiafn/flight_information_system/server/custom_errors"
 	"github.com/cyiafn/flight_information_system/server/database"
 	"github.com/cyiafn/flight_information_system/server/dto"
+	"github.com/cyiafn/flight_information_system/server/locations"
 )
 
-// GetFlightIdentifiers simply gets all flight identifiers for a source and destination location
+// GetFlightIdentifiers simply gets all flight identifiers for a source and destination location. Locations may be
+// provided as IATA codes, city names or aliases in any case.
 func GetFlightIdentifiers(_ context.Context, request any) (any, error) {
 	req := request.(*dto.GetFlightIdentifiersRequest)
 	res := &dto.GetFlightIdentifiersResponse{
 		FlightIdentifiers: make([]int32, 0),
 	}
 
-	for _, flight := range database.GetAllFlights() {
-		flight := flight
-		if flight.SourceLocation == req.SourceLocation && flight.DestinationLocation == req.DestinationLocation {
-			res.FlightIdentifiers = append(res.FlightIdentifiers, flight.FlightIdentifier)
-		}
+	flights, err := database.GetFlightRepository().GetFlightsBySourceAndDestination(locations.Normalise(req.SourceLocation), locations.Normalise(req.DestinationLocation))
+	if err != nil {
+		return nil, err
+	}
+	for _, flight := range flights {
+		res.FlightIdentifiers = append(res.FlightIdentifiers, flight.FlightIdentifier)
 	}
 
 	if len(res.FlightIdentifiers) == 0 {
-		return nil, custom_errors.NewNoMatchForSourceAndDestinationError()
+		// suggestions are only made for locations that are not known, e.g. typos
+		return nil, custom_errors.NewNoMatchForSourceAndDestinationError(locations.Suggest(req.SourceLocation), locations.Suggest(req.DestinationLocation))
 	}
 
 	return res, nil
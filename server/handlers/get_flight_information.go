@@ -2,31 +2,36 @@ package handlers
 
 import (
 	"context"
-	"github.com/cyiafn/flight_information_system/server/custom_errors"
+
 	"github.com/cyiafn/flight_information_system/server/database"
 	"github.com/cyiafn/flight_information_system/server/dto"
 )
 
-// GetFlightInformation gets Airfare, DepartureTime and TotalAvailableSeats of a flight based on flightIDs
+// GetFlightInformation gets Airfare, TotalAvailableSeats and the schedule of a flight based on flightIDs, times are
+// rendered in the local time of the airports if requested
 func GetFlightInformation(_ context.Context, request any) (any, error) {
 	req := request.(*dto.GetFlightInformationRequest)
-	res := &dto.GetFlightInformationResponse{}
-
-	foundFlight := false
-	for _, flight := range database.GetAllFlights() {
-		flight := flight
-		if flight.FlightIdentifier == req.FlightIdentifier {
-			foundFlight = true
-			res.Airfare = flight.Airfare
-			res.DepartureTime = flight.DepartureTime
-			res.TotalAvailableSeats = flight.TotalAvailableSeats
-			break
-		}
+	if err := validateTimeFormat(req.TimeFormat); err != nil {
+		return nil, err
 	}
 
-	if !foundFlight {
-		return nil, custom_errors.NewNoSuchFlightIdentifierError()
+	flight, err := database.GetFlightRepository().GetFlight(req.FlightIdentifier)
+	if err != nil {
+		return nil, err
 	}
 
-	return res, nil
+	localDepartureTime, localArrivalTime := localTimes(flight, req.TimeFormat)
+	return &dto.GetFlightInformationResponse{
+		DepartureTime:       flight.DepartureTime,
+		Airfare:             flight.Airfare,
+		TotalAvailableSeats: flight.TotalAvailableSeats,
+		ArrivalTime:         flight.ArrivalTime,
+		DurationInSeconds:   flightDuration(flight),
+		SourceTimezone:      flight.SourceTimezone,
+		DestinationTimezone: flight.DestinationTimezone,
+		Aircraft:            flight.Aircraft,
+		LocalDepartureTime:  localDepartureTime,
+		LocalArrivalTime:    localArrivalTime,
+		Status:              uint8(flight.Status),
+	}, nil
 }
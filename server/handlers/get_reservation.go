@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/cyiafn/flight_information_system/server/custom_errors"
+	"github.com/cyiafn/flight_information_system/server/dao"
+	"github.com/cyiafn/flight_information_system/server/database"
+	"github.com/cyiafn/flight_information_system/server/dto"
+	"github.com/cyiafn/flight_information_system/server/server"
+)
+
+// GetReservation gets the details of a reservation based on its booking identifier.
+func GetReservation(ctx context.Context, request any) (any, error) {
+	req := request.(*dto.GetReservationRequest)
+
+	reservation, err := getClientReservation(ctx, req.BookingIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.GetReservationResponse{
+		BookingIdentifier: reservation.BookingIdentifier,
+		FlightIdentifier:  reservation.FlightIdentifier,
+		SeatsReserved:     reservation.SeatsReserved,
+		ClientAddress:     reservation.ClientAddress,
+		ReservationTime:   reservation.ReservationTime,
+		SeatLabels:        reservation.SeatLabels,
+	}, nil
+}
+
+// getClientReservation gets a reservation made by the client of the RPC call. Booking identifiers are sequential, so
+// reservations of other clients are treated as if they do not exist rather than letting any client guess them.
+func getClientReservation(ctx context.Context, bookingIdentifier int32) (*dao.Reservation, error) {
+	reservation, err := database.GetFlightRepository().GetReservation(bookingIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	if reservation.ClientAddress != server.GetIPAddr(ctx) {
+		return nil, custom_errors.NewNoSuchBookingIdentifierError()
+	}
+	return reservation, nil
+}
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/cyiafn/flight_information_system/server/database"
+	"github.com/cyiafn/flight_information_system/server/dto"
+)
+
+// GetSeatMap gets every seat of a flight with its cabin class and whether it is free, held or booked.
+func GetSeatMap(_ context.Context, request any) (any, error) {
+	req := request.(*dto.GetSeatMapRequest)
+
+	seats, err := database.GetFlightRepository().GetSeatMap(req.FlightIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &dto.GetSeatMapResponse{
+		FlightIdentifier: req.FlightIdentifier,
+		Seats:            make([]dto.SeatInformation, len(seats)),
+	}
+	for i, seat := range seats {
+		res.Seats[i] = dto.SeatInformation{
+			SeatLabel:  seat.SeatLabel,
+			CabinClass: uint8(seat.CabinClass),
+			Status:     uint8(seat.Status),
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cyiafn/flight_information_system/server/changelog"
+	"github.com/cyiafn/flight_information_system/server/custom_errors"
+	"github.com/cyiafn/flight_information_system/server/dao"
+	"github.com/cyiafn/flight_information_system/server/database"
+	"github.com/cyiafn/flight_information_system/server/dto"
+)
+
+// GetUpdatesSince gets the changes of a type to a flight after the sequence number provided, so that a subscriber that
+// missed callbacks can catch up. If some of the changes missed are no longer kept, the current state of the flight is
+// returned instead with the latest sequence number of the type.
+func GetUpdatesSince(_ context.Context, request any) (any, error) {
+	req := request.(*dto.GetUpdatesSinceRequest)
+	changeType := changelog.ChangeType(req.UpdateType)
+	if !changeType.IsValid() {
+		return nil, custom_errors.NewInvalidRequestError(fmt.Sprintf("unknown update type: %d", req.UpdateType))
+	}
+
+	// the change log is read before the flight so that the snapshot is at least as new as the changes read
+	changes, ok := changelog.Since(req.FlightIdentifier, changeType, req.SequenceNumber)
+	flight, err := database.GetFlightRepository().GetFlight(req.FlightIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	// no changes are returned for a subscriber that is behind if the change log was lost, e.g. on restart
+	latestSequenceNumber := changeType.SequenceNumber(flight)
+	if !ok || (len(changes) == 0 && req.SequenceNumber != latestSequenceNumber) {
+		snapshot := toFlightUpdate(flight)
+		snapshot.SequenceNumber = latestSequenceNumber
+		return &dto.GetUpdatesSinceResponse{
+			ResyncType: dto.SnapshotResync,
+			Updates:    []dto.FlightUpdate{snapshot},
+		}, nil
+	}
+
+	res := &dto.GetUpdatesSinceResponse{
+		ResyncType: dto.HistoryResync,
+		Updates:    make([]dto.FlightUpdate, len(changes)),
+	}
+	for i, change := range changes {
+		res.Updates[i] = toFlightUpdate(&change.Flight)
+		res.Updates[i].SequenceNumber = change.SequenceNumber
+		res.Updates[i].UpdateType = uint8(change.ChangeType)
+	}
+	return res, nil
+}
+
+// toFlightUpdate converts the state of a flight to a FlightUpdate without its sequence number and update type
+func toFlightUpdate(flight *dao.Flight) dto.FlightUpdate {
+	return dto.FlightUpdate{
+		TotalAvailableSeats: flight.TotalAvailableSeats,
+		Airfare:             flight.Airfare,
+		Status:              uint8(flight.Status),
+		DepartureTime:       flight.DepartureTime,
+		ArrivalTime:         flight.ArrivalTime,
+	}
+}
@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+
+	"github.com/cyiafn/flight_information_system/server/custom_errors"
+	"github.com/cyiafn/flight_information_system/server/database"
+	"github.com/cyiafn/flight_information_system/server/logs"
+)
+
+/**
+Unconfirmed holds are released once they expire. Expiries are kept in a single min-heap by expiry time, served by one
+goroutine that sleeps until the earliest expiry in the same way callback subscriptions expire, so the number of goroutines
+and timers does not grow with the number of holds. Holds confirmed in the meantime no longer exist and are left alone.
+*/
+
+// holdExpiry is a hold to be released once it expires
+type holdExpiry struct {
+	HoldToken  string
+	ExpiryTime time.Time
+}
+
+// holdExpiries releases the holds of every flight
+var holdExpiries *holdExpiryScheduler
+
+func init() {
+	holdExpiries = newHoldExpiryScheduler(releaseHold)
+}
+
+// holdExpiryScheduler releases holds as they expire.
+// This is CONCURRENT-SAFE
+type holdExpiryScheduler struct {
+	sync.Mutex
+	// expiries are the holds ordered by expiry time
+	expiries holdExpiryHeap
+	// release releases the hold, it is called from the expiry goroutine
+	release func(holdToken string)
+	// wake wakes the expiry goroutine when the earliest expiry may have changed
+	wake chan struct{}
+	// stop terminates the expiry goroutine
+	stop chan struct{}
+	// stopped is closed once the expiry goroutine has terminated
+	stopped chan struct{}
+}
+
+// newHoldExpiryScheduler instantiates an empty holdExpiryScheduler and starts its expiry goroutine
+func newHoldExpiryScheduler(release func(holdToken string)) *holdExpiryScheduler {
+	s := &holdExpiryScheduler{
+		release: release,
+		wake:    make(chan struct{}, 1),
+		stop:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go s.runExpiries()
+	return s
+}
+
+// Stop terminates the expiry goroutine, holds are no longer released after
+func (s *holdExpiryScheduler) Stop() {
+	close(s.stop)
+	<-s.stopped
+}
+
+// Schedule releases the hold at expiryTime, holds that have already expired are released immediately
+func (s *holdExpiryScheduler) Schedule(holdToken string, expiryTime time.Time) {
+	s.Lock()
+	defer s.Unlock()
+	heap.Push(&s.expiries, holdExpiry{HoldToken: holdToken, ExpiryTime: expiryTime})
+	// later expiries do not need to wake the goroutine, as it only sleeps until the earliest one
+	if s.expiries[0].HoldToken != holdToken {
+		return
+	}
+	select {
+	case s.wake <- struct{}{}:
+	default:
+		// a wake up is already pending
+	}
+}
+
+// Len is the number of holds that have not been released
+func (s *holdExpiryScheduler) Len() int {
+	s.Lock()
+	defer s.Unlock()
+	return len(s.expiries)
+}
+
+// runExpiries releases holds as they expire, sleeping until the earliest expiry in between
+func (s *holdExpiryScheduler) runExpiries() {
+	defer close(s.stopped)
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+	for {
+		expired, next := s.popExpired(time.Now())
+		for _, expiry := range expired {
+			s.release(expiry.HoldToken)
+		}
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		if next > 0 {
+			timer.Reset(next)
+		}
+		select {
+		case <-timer.C:
+		case <-s.wake:
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// popExpired removes the holds that have expired by now, returns them along with the time until the next expiry, or 0 if
+// there are no holds left
+func (s *holdExpiryScheduler) popExpired(now time.Time) ([]holdExpiry, time.Duration) {
+	s.Lock()
+	defer s.Unlock()
+	expired := make([]holdExpiry, 0)
+	for len(s.expiries) > 0 && !s.expiries[0].ExpiryTime.After(now) {
+		expired = append(expired, heap.Pop(&s.expiries).(holdExpiry))
+	}
+	if len(s.expiries) == 0 {
+		return expired, 0
+	}
+	return expired, s.expiries[0].ExpiryTime.Sub(now)
+}
+
+// getHoldReleaseTime is when a hold expiring at expiryTime (unix seconds) is released, which is once it can no longer be
+// confirmed as holds can be confirmed up to the end of the second they expire in
+func getHoldReleaseTime(expiryTime int64) time.Time {
+	return time.Unix(expiryTime+1, 0)
+}
+
+// releaseHold releases the hold and notifies the subscribers of its flight, unless it has been confirmed already
+func releaseHold(holdToken string) {
+	hold, flight, err := database.GetFlightRepository().ReleaseHold(holdToken)
+	if _, ok := err.(*custom_errors.NoSuchHoldTokenError); ok {
+		return
+	}
+	if err != nil {
+		logs.Warn("unable to release hold: %s, err: %v", holdToken, err)
+		return
+	}
+	logs.Info("hold: %s has expired, released seats: %v of flight: %v", holdToken, hold.SeatLabels, hold.FlightIdentifier)
+
+	// seats are available again, so subscribers of the flight are notified
+	handleMonitorSeatUpdatesCallback(flight)
+}
+
+// holdExpiryHeap is a min-heap of holds by expiry time, it implements heap.Interface
+type holdExpiryHeap []holdExpiry
+
+func (h *holdExpiryHeap) Len() int {
+	return len(*h)
+}
+
+func (h *holdExpiryHeap) Less(i, j int) bool {
+	return (*h)[i].ExpiryTime.Before((*h)[j].ExpiryTime)
+}
+
+func (h *holdExpiryHeap) Swap(i, j int) {
+	(*h)[i], (*h)[j] = (*h)[j], (*h)[i]
+}
+
+func (h *holdExpiryHeap) Push(x any) {
+	*h = append(*h, x.(holdExpiry))
+}
+
+func (h *holdExpiryHeap) Pop() any {
+	old := *h
+	expiry := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return expiry
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/cyiafn/flight_information_system/server/custom_errors"
+	"github.com/cyiafn/flight_information_system/server/dao"
+	"github.com/cyiafn/flight_information_system/server/database"
+	"github.com/cyiafn/flight_information_system/server/dto"
+	"github.com/cyiafn/flight_information_system/server/logs"
+	"github.com/cyiafn/flight_information_system/server/server"
+)
+
+const (
+	// maxHoldDurationInSeconds caps how long seats can be held without being confirmed
+	maxHoldDurationInSeconds = 15 * 60
+	// holdTokenBytesLength is the number of random bytes in a hold token, enough that hold tokens cannot be guessed
+	holdTokenBytesLength = 16
+)
+
+// HoldSeats holds seats on a flight for the duration requested and returns a hold token, the seats can be booked with
+// ConfirmHold before the hold expires. Unconfirmed holds are released automatically once they expire.
+func HoldSeats(ctx context.Context, request any) (any, error) {
+	req := request.(*dto.HoldSeatsRequest)
+	if len(req.SeatLabels) == 0 && (req.SeatsToHold <= 0 || req.SeatsToHold > dao.MaxTotalSeats) {
+		return nil, custom_errors.NewInvalidRequestError(fmt.Sprintf("seats to hold must be from 1 to %d if no seat labels are provided", dao.MaxTotalSeats))
+	}
+	if req.HoldDurationInSeconds <= 0 || req.HoldDurationInSeconds > maxHoldDurationInSeconds {
+		return nil, custom_errors.NewInvalidRequestError(fmt.Sprintf("hold duration must be between 1 and %d seconds", maxHoldDurationInSeconds))
+	}
+
+	holdToken, err := newHoldToken()
+	if err != nil {
+		logs.Warn("unable to generate hold token, err: %v", err)
+		return nil, err
+	}
+	hold, flight, err := database.GetFlightRepository().HoldSeats(&dao.Hold{
+		HoldToken:        holdToken,
+		FlightIdentifier: req.FlightIdentifier,
+		SeatsHeld:        req.SeatsToHold,
+		ClientAddress:    server.GetIPAddr(ctx),
+		ExpiryTime:       time.Now().Add(time.Duration(req.HoldDurationInSeconds) * time.Second).Unix(),
+		SeatLabels:       req.SeatLabels,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// held seats are no longer available, so subscribers of the flight are notified
+	handleMonitorSeatUpdatesCallback(flight)
+	holdExpiries.Schedule(hold.HoldToken, getHoldReleaseTime(hold.ExpiryTime))
+
+	return &dto.HoldSeatsResponse{
+		HoldToken:  hold.HoldToken,
+		SeatLabels: hold.SeatLabels,
+		ExpiryTime: hold.ExpiryTime,
+	}, nil
+}
+
+// ResumeHoldExpiries schedules the release of holds already in the flight store, e.g. holds persisted in postgres
+// before a restart. This should be called once on boot.
+func ResumeHoldExpiries() error {
+	holds, err := database.GetFlightRepository().GetHolds()
+	if err != nil {
+		return err
+	}
+	for _, hold := range holds {
+		// holds that have already expired are released immediately
+		holdExpiries.Schedule(hold.HoldToken, getHoldReleaseTime(hold.ExpiryTime))
+	}
+	return nil
+}
+
+// newHoldToken generates a random hex hold token, which cannot be guessed from the hold tokens other clients receive
+func newHoldToken() (string, error) {
+	b := make([]byte, holdTokenBytesLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
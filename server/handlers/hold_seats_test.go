@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"context"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cyiafn/flight_information_system/server/custom_errors"
+	"github.com/cyiafn/flight_information_system/server/dao"
+	"github.com/cyiafn/flight_information_system/server/database"
+	"github.com/cyiafn/flight_information_system/server/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfirmHoldOfAnotherClient(t *testing.T) {
+	database.Init(database.NewInMemoryFlightRepository())
+	flightIdentifier := createTestFlight(t)
+	ctx := context.WithValue(context.Background(), "addr", "127.0.0.1:5000")
+	res, err := HoldSeats(ctx, &dto.HoldSeatsRequest{FlightIdentifier: flightIdentifier, SeatsToHold: 2, HoldDurationInSeconds: 60})
+	assert.Nil(t, err)
+	holdToken := res.(*dto.HoldSeatsResponse).HoldToken
+	assert.Len(t, holdToken, 2*holdTokenBytesLength)
+
+	_, err = ConfirmHold(context.WithValue(context.Background(), "addr", "127.0.0.1:5001"), &dto.ConfirmHoldRequest{HoldToken: holdToken})
+	assert.IsType(t, &custom_errors.NoSuchHoldTokenError{}, err)
+	confirmRes, err := ConfirmHold(ctx, &dto.ConfirmHoldRequest{HoldToken: holdToken})
+	assert.Nil(t, err)
+	assert.Equal(t, res.(*dto.HoldSeatsResponse).SeatLabels, confirmRes.(*dto.ConfirmHoldResponse).SeatLabels)
+}
+
+func TestHoldExpiryScheduler(t *testing.T) {
+	var mu sync.Mutex
+	released := make([]string, 0)
+	done := make(chan struct{})
+	s := newHoldExpiryScheduler(func(holdToken string) {
+		mu.Lock()
+		defer mu.Unlock()
+		released = append(released, holdToken)
+		if len(released) == 3 {
+			close(done)
+		}
+	})
+	defer s.Stop()
+
+	now := time.Now()
+	s.Schedule("later", now.Add(40*time.Millisecond))
+	s.Schedule("sooner", now.Add(20*time.Millisecond))
+	// holds that have already expired, e.g. on boot, are released immediately
+	s.Schedule("expired", now.Add(-time.Second))
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("holds were not released")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"expired", "sooner", "later"}, released)
+	assert.Equal(t, 0, s.Len())
+}
+
+func TestSeatCountsAboveMaxTotalSeats(t *testing.T) {
+	database.Init(database.NewInMemoryFlightRepository())
+	flightIdentifier := createTestFlight(t)
+	ctx := context.WithValue(context.Background(), "addr", "127.0.0.1:5000")
+
+	_, err := MakeSeatReservation(ctx, &dto.MakeSeatReservationRequest{FlightIdentifier: flightIdentifier, SeatsToReserve: math.MaxInt32})
+	assert.IsType(t, &custom_errors.InvalidRequestError{}, err)
+	_, err = HoldSeats(ctx, &dto.HoldSeatsRequest{FlightIdentifier: flightIdentifier, SeatsToHold: dao.MaxTotalSeats + 1, HoldDurationInSeconds: 60})
+	assert.IsType(t, &custom_errors.InvalidRequestError{}, err)
+}
@@ -2,36 +2,39 @@ package handlers
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/cyiafn/flight_information_system/server/custom_errors"
+	"github.com/cyiafn/flight_information_system/server/dao"
 	"github.com/cyiafn/flight_information_system/server/database"
 	"github.com/cyiafn/flight_information_system/server/dto"
+	"github.com/cyiafn/flight_information_system/server/server"
 )
 
-// MakeSeatReservation makes a reservation for a flight identifier.
-func MakeSeatReservation(_ context.Context, request any) (any, error) {
+// MakeSeatReservation makes a reservation for a flight identifier and returns the booking identifier of the reservation.
+func MakeSeatReservation(ctx context.Context, request any) (any, error) {
 	req := request.(*dto.MakeSeatReservationRequest)
-
-	foundFlight := false
-	for _, flight := range database.GetAllFlights() {
-		flight := flight
-		if flight.FlightIdentifier != req.FlightIdentifier {
-			continue
-		}
-
-		foundFlight = true
-		if flight.TotalAvailableSeats < req.SeatsToReserve {
-			return nil, custom_errors.NewInsufficientNumberOfAvailableSeatsError()
-		}
-		flight.TotalAvailableSeats -= req.SeatsToReserve
-
-		// handle the callback in the rpc handler in charge of callback
-		handleMonitorSeatUpdatesCallback(flight)
+	if req.SeatsToReserve <= 0 || req.SeatsToReserve > dao.MaxTotalSeats {
+		return nil, custom_errors.NewInvalidRequestError(fmt.Sprintf("seats to reserve must be from 1 to %d", dao.MaxTotalSeats))
 	}
 
-	if !foundFlight {
-		return nil, custom_errors.NewNoSuchFlightIdentifierError()
+	// the check for available seats and the reservation is done atomically by the repository
+	reservation, flight, err := database.GetFlightRepository().MakeReservation(&dao.Reservation{
+		FlightIdentifier: req.FlightIdentifier,
+		SeatsReserved:    req.SeatsToReserve,
+		ClientAddress:    server.GetIPAddr(ctx),
+		ReservationTime:  time.Now().Unix(),
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return nil, nil
+	// handle the callback in the rpc handler in charge of callback
+	handleMonitorSeatUpdatesCallback(flight)
+
+	return &dto.MakeSeatReservationResponse{
+		BookingIdentifier: reservation.BookingIdentifier,
+		SeatLabels:        reservation.SeatLabels,
+	}, nil
 }
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/cyiafn/flight_information_system/server/callback"
+	"github.com/cyiafn/flight_information_system/server/changelog"
+	"github.com/cyiafn/flight_information_system/server/dao"
+	"github.com/cyiafn/flight_information_system/server/database"
+	"github.com/cyiafn/flight_information_system/server/dto"
+	"github.com/cyiafn/flight_information_system/server/logs"
+)
+
+// monitorFlightStatusCallbackClient is an instance of the callback client for monitoring the status of flights
+var monitorFlightStatusCallbackClient *callback.Client[int32]
+
+func init() {
+	// initialises the client on start
+	monitorFlightStatusCallbackClient = callback.NewClient[int32](dto.MonitorFlightStatusCallbackType)
+}
+
+// MonitorFlightStatus subscribes the client of the RPC call to changes in the status of a flight, e.g. delays and
+// cancellations, for the time they are provided
+func MonitorFlightStatus(ctx context.Context, request any) (any, error) {
+	req := request.(*dto.MonitorFlightStatusCallbackRequest)
+	monitorInterval, err := getMonitorInterval(req.LengthOfMonitorIntervalInSeconds)
+	if err != nil {
+		return nil, err
+	}
+	// checks if that flight identifier exists
+	if _, err := database.GetFlightRepository().GetFlight(req.FlightIdentifier); err != nil {
+		return nil, err
+	}
+
+	subscriptionIdentifier := monitorFlightStatusCallbackClient.Subscribe(ctx, req.FlightIdentifier, monitorInterval)
+
+	return &dto.MonitorFlightStatusResponse{SubscriptionIdentifier: subscriptionIdentifier}, nil
+}
+
+// handleMonitorFlightStatusCallback notifies all subscribers of a flight identifier that the status of the flight has
+// changed
+func handleMonitorFlightStatusCallback(flight *dao.Flight) {
+	change := changelog.Record(changelog.StatusChange, flight)
+	res := &dto.MonitorFlightStatusCallbackResponse{
+		FlightIdentifier: flight.FlightIdentifier,
+		Status:           uint8(flight.Status),
+		DepartureTime:    flight.DepartureTime,
+		ArrivalTime:      flight.ArrivalTime,
+		SequenceNumber:   change.SequenceNumber,
+	}
+	err := monitorFlightStatusCallbackClient.Notify(flight.FlightIdentifier, dto.MonitorFlightStatusCallbackType, res, nil)
+	if err != nil {
+		logs.Warn("failure to deliver callback for 1 or more clients: %v", err)
+	}
+}
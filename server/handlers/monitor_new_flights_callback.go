@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/cyiafn/flight_information_system/server/callback"
+	"github.com/cyiafn/flight_information_system/server/dao"
+	"github.com/cyiafn/flight_information_system/server/dto"
+	"github.com/cyiafn/flight_information_system/server/locations"
+	"github.com/cyiafn/flight_information_system/server/logs"
+)
+
+// flightRoute is the key subscribers of new flights subscribe to, locations are the canonical city names
+type flightRoute struct {
+	SourceLocation      string
+	DestinationLocation string
+}
+
+// monitorNewFlightsCallbackClient is an instance of the callback client for monitoring new flights on a route
+var monitorNewFlightsCallbackClient *callback.Client[flightRoute]
+
+func init() {
+	// initialises the client on start
+	monitorNewFlightsCallbackClient = callback.NewClient[flightRoute](dto.MonitorNewFlightsCallbackType)
+}
+
+// MonitorNewFlights subscribes the client of the RPC call to flights created on a route for the time they are provided.
+// The route does not need to have any flights yet.
+func MonitorNewFlights(ctx context.Context, request any) (any, error) {
+	req := request.(*dto.MonitorNewFlightsCallbackRequest)
+	monitorInterval, err := getMonitorInterval(req.LengthOfMonitorIntervalInSeconds)
+	if err != nil {
+		return nil, err
+	}
+
+	// flights are stored with the canonical city names, so the subscription is too
+	route := flightRoute{
+		SourceLocation:      locations.Normalise(req.SourceLocation),
+		DestinationLocation: locations.Normalise(req.DestinationLocation),
+	}
+	subscriptionIdentifier := monitorNewFlightsCallbackClient.Subscribe(ctx, route, monitorInterval)
+
+	return &dto.MonitorNewFlightsResponse{SubscriptionIdentifier: subscriptionIdentifier}, nil
+}
+
+// handleMonitorNewFlightsCallback notifies all subscribers of the route of a flight that the flight has been created
+func handleMonitorNewFlightsCallback(flight *dao.Flight) {
+	route := flightRoute{SourceLocation: flight.SourceLocation, DestinationLocation: flight.DestinationLocation}
+	res := &dto.MonitorNewFlightsCallbackResponse{Flight: newFlightInformation(flight)}
+	err := monitorNewFlightsCallbackClient.Notify(route, dto.MonitorNewFlightsCallbackType, res, nil)
+	if err != nil {
+		logs.Warn("failure to deliver callback for 1 or more clients: %v", err)
+	}
+}
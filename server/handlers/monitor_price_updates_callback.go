@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/cyiafn/flight_information_system/server/callback"
+	"github.com/cyiafn/flight_information_system/server/changelog"
+	"github.com/cyiafn/flight_information_system/server/dao"
+	"github.com/cyiafn/flight_information_system/server/database"
+	"github.com/cyiafn/flight_information_system/server/dto"
+	"github.com/cyiafn/flight_information_system/server/logs"
+)
+
+// monitorPriceUpdatesCallbackClient is an instance of the callback client for monitoring the airfare of flights
+var monitorPriceUpdatesCallbackClient *callback.Client[int32]
+
+func init() {
+	// initialises the client on start
+	monitorPriceUpdatesCallbackClient = callback.NewClient[int32](dto.MonitorPriceUpdatesCallbackType)
+}
+
+// MonitorPriceUpdates subscribes the client of the RPC call to changes in the airfare of a flight for the time they are provided
+func MonitorPriceUpdates(ctx context.Context, request any) (any, error) {
+	req := request.(*dto.MonitorPriceUpdatesCallbackRequest)
+	monitorInterval, err := getMonitorInterval(req.LengthOfMonitorIntervalInSeconds)
+	if err != nil {
+		return nil, err
+	}
+	// checks if that flight identifier exists
+	if _, err := database.GetFlightRepository().GetFlight(req.FlightIdentifier); err != nil {
+		return nil, err
+	}
+
+	subscriptionIdentifier := monitorPriceUpdatesCallbackClient.Subscribe(ctx, req.FlightIdentifier, monitorInterval)
+
+	return &dto.MonitorPriceUpdatesResponse{SubscriptionIdentifier: subscriptionIdentifier}, nil
+}
+
+// handleMonitorPriceUpdatesCallback notifies all subscribers of a flight identifier of the new airfare of the flight
+func handleMonitorPriceUpdatesCallback(flight *dao.Flight) {
+	change := changelog.Record(changelog.AirfareChange, flight)
+	res := &dto.MonitorPriceUpdatesCallbackResponse{
+		FlightIdentifier: flight.FlightIdentifier,
+		Airfare:          flight.Airfare,
+		SequenceNumber:   change.SequenceNumber,
+	}
+	err := monitorPriceUpdatesCallbackClient.Notify(flight.FlightIdentifier, dto.MonitorPriceUpdatesCallbackType, res, nil)
+	if err != nil {
+		logs.Warn("failure to deliver callback for 1 or more clients: %v", err)
+	}
+}
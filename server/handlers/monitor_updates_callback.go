@@ -2,12 +2,10 @@ package handlers
 
 import (
 	"context"
-	"github.com/cyiafn/flight_information_system/server/custom_errors"
 	"github.com/cyiafn/flight_information_system/server/database"
-	"github.com/cyiafn/flight_information_system/server/utils/predicates"
-	"time"
 
 	"github.com/cyiafn/flight_information_system/server/callback"
+	"github.com/cyiafn/flight_information_system/server/changelog"
 	"github.com/cyiafn/flight_information_system/server/dao"
 	"github.com/cyiafn/flight_information_system/server/dto"
 	"github.com/cyiafn/flight_information_system/server/logs"
@@ -18,29 +16,31 @@ var monitorSeatUpdatesCallbackClient *callback.Client[int32]
 
 func init() {
 	// initialises the client on start
-	monitorSeatUpdatesCallbackClient = callback.NewClient[int32]()
+	monitorSeatUpdatesCallbackClient = callback.NewClient[int32](dto.MonitorSeatUpdatesCallbackType)
 }
 
 // MonitorSeatUpdates simply subscribes the client of the RPC call to changes in a particular flight identifier for the time they are provided
 func MonitorSeatUpdates(ctx context.Context, request any) (any, error) {
 	req := request.(*dto.MonitorSeatUpdatesCallbackRequest)
+	monitorInterval, err := getMonitorInterval(req.LengthOfMonitorIntervalInSeconds)
+	if err != nil {
+		return nil, err
+	}
 	// checks if that flight identifier exists
-	exists := predicates.One(database.GetAllFlights(), func(flight *dao.Flight) bool {
-		return flight.FlightIdentifier == req.FlightIdentifier
-	})
-	if !exists {
-		return nil, custom_errors.NewNoSuchFlightIdentifierError()
+	if _, err := database.GetFlightRepository().GetFlight(req.FlightIdentifier); err != nil {
+		return nil, err
 	}
 
 	// we subscribe to that flight identifier for changes in seats
-	monitorSeatUpdatesCallbackClient.Subscribe(ctx, req.FlightIdentifier, time.Duration(req.LengthOfMonitorIntervalInSeconds)*time.Second)
+	subscriptionIdentifier := monitorSeatUpdatesCallbackClient.Subscribe(ctx, req.FlightIdentifier, monitorInterval)
 
-	return nil, nil
+	return &dto.MonitorSeatUpdatesResponse{SubscriptionIdentifier: subscriptionIdentifier}, nil
 }
 
 // handleMonitorSeatUpdateCallback simply just tells the callback client to notify all subscribers of a flight identifier
 func handleMonitorSeatUpdatesCallback(flight *dao.Flight) {
-	res := &dto.MonitorSeatUpdatesCallbackResponse{TotalAvailableSeats: flight.TotalAvailableSeats}
+	change := changelog.Record(changelog.SeatsChange, flight)
+	res := &dto.MonitorSeatUpdatesCallbackResponse{FlightIdentifier: flight.FlightIdentifier, TotalAvailableSeats: flight.TotalAvailableSeats, SequenceNumber: change.SequenceNumber}
 	err := monitorSeatUpdatesCallbackClient.Notify(flight.FlightIdentifier, dto.MonitorSeatUpdatesCallbackType, res, nil)
 	if err != nil {
 		logs.Warn("failure to deliver callback for 1 or more clients: %v", err)
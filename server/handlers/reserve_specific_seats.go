@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"github.com/cyiafn/flight_information_system/server/custom_errors"
+	"github.com/cyiafn/flight_information_system/server/dao"
+	"github.com/cyiafn/flight_information_system/server/database"
+	"github.com/cyiafn/flight_information_system/server/dto"
+	"github.com/cyiafn/flight_information_system/server/server"
+)
+
+// ReserveSpecificSeats books all the seats requested in a single reservation, or none of them if any is unavailable.
+// The unavailable seats are returned to the user in the SeatsUnavailableError.
+func ReserveSpecificSeats(ctx context.Context, request any) (any, error) {
+	req := request.(*dto.ReserveSpecificSeatsRequest)
+	if len(req.SeatLabels) == 0 {
+		return nil, custom_errors.NewInvalidRequestError("at least 1 seat label must be provided")
+	}
+
+	reservation, flight, err := database.GetFlightRepository().MakeReservation(&dao.Reservation{
+		FlightIdentifier: req.FlightIdentifier,
+		ClientAddress:    server.GetIPAddr(ctx),
+		ReservationTime:  time.Now().Unix(),
+		SeatLabels:       req.SeatLabels,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// handle the callback in the rpc handler in charge of callback
+	handleMonitorSeatUpdatesCallback(flight)
+
+	return &dto.ReserveSpecificSeatsResponse{
+		BookingIdentifier: reservation.BookingIdentifier,
+		SeatLabels:        reservation.SeatLabels,
+	}, nil
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cyiafn/flight_information_system/server/custom_errors"
+	"github.com/cyiafn/flight_information_system/server/dao"
+	"github.com/cyiafn/flight_information_system/server/database"
+	"github.com/cyiafn/flight_information_system/server/dto"
+	"github.com/cyiafn/flight_information_system/server/locations"
+)
+
+const (
+	// defaultSearchLimit is the page size if none is requested
+	defaultSearchLimit = 20
+	// maxSearchLimit caps the page size so that responses stay within a handful of byte buffers
+	maxSearchLimit = 100
+)
+
+// SearchFlights gets a page of flights matching the filters requested, sorted in the order requested.
+func SearchFlights(_ context.Context, request any) (any, error) {
+	req := request.(*dto.SearchFlightsRequest)
+	criteria, err := newFlightSearchCriteria(req)
+	if err != nil {
+		return nil, err
+	}
+
+	flights, totalMatches, err := database.GetFlightRepository().SearchFlights(criteria)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &dto.SearchFlightsResponse{
+		Flights:      make([]dto.FlightInformation, len(flights)),
+		TotalMatches: totalMatches,
+	}
+	for i, flight := range flights {
+		res.Flights[i] = newFlightInformation(flight)
+	}
+
+	return res, nil
+}
+
+// newFlightSearchCriteria validates the request and fills in the defaults of the search
+func newFlightSearchCriteria(req *dto.SearchFlightsRequest) (*database.FlightSearchCriteria, error) {
+	sortOrder := database.FlightSortOrderType(req.SortOrder)
+	if sortOrder == 0 {
+		sortOrder = database.FlightIdentifierSortOrder
+	}
+	if sortOrder > database.DepartureTimeSortOrder {
+		return nil, custom_errors.NewInvalidRequestError(fmt.Sprintf("unknown sort order: %d", req.SortOrder))
+	}
+	if req.DepartureTimeTo != 0 && req.DepartureTimeFrom > req.DepartureTimeTo {
+		return nil, custom_errors.NewInvalidRequestError("departure time from must not be after departure time to")
+	}
+	if req.MaxAirfare < 0 || req.MinAvailableSeats < 0 {
+		return nil, custom_errors.NewInvalidRequestError("max airfare and min available seats must not be negative")
+	}
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultSearchLimit
+	}
+	if req.Offset < 0 || limit < 0 || limit > maxSearchLimit {
+		return nil, custom_errors.NewInvalidRequestError(fmt.Sprintf("offset must not be negative and limit must be between 1 and %d", maxSearchLimit))
+	}
+
+	return &database.FlightSearchCriteria{
+		SourceLocation:      locations.Normalise(req.SourceLocation),
+		DestinationLocation: locations.Normalise(req.DestinationLocation),
+		DepartureTimeFrom:   req.DepartureTimeFrom,
+		DepartureTimeTo:     req.DepartureTimeTo,
+		MaxAirfare:          req.MaxAirfare,
+		MinAvailableSeats:   req.MinAvailableSeats,
+		SortOrder:           sortOrder,
+		Offset:              int(req.Offset),
+		Limit:               int(limit),
+	}, nil
+}
+
+// newFlightInformation maps a flight to the flight information returned to the user
+func newFlightInformation(flight *dao.Flight) dto.FlightInformation {
+	return dto.FlightInformation{
+		FlightIdentifier:    flight.FlightIdentifier,
+		SourceLocation:      flight.SourceLocation,
+		DestinationLocation: flight.DestinationLocation,
+		DepartureTime:       flight.DepartureTime,
+		Airfare:             flight.Airfare,
+		TotalAvailableSeats: flight.TotalAvailableSeats,
+		ArrivalTime:         flight.ArrivalTime,
+		DurationInSeconds:   flightDuration(flight),
+		Status:              uint8(flight.Status),
+	}
+}
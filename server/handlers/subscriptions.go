@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cyiafn/flight_information_system/server/callback"
+	"github.com/cyiafn/flight_information_system/server/custom_errors"
+	"github.com/cyiafn/flight_information_system/server/dto"
+	"github.com/cyiafn/flight_information_system/server/server"
+)
+
+// maxMonitorIntervalInSeconds caps how long a subscription lasts before it has to be renewed, which also keeps the
+// interval from overflowing a time.Duration
+const maxMonitorIntervalInSeconds = 24 * 60 * 60
+
+// Unsubscribe stops the callbacks of a subscription of the client of the RPC call before it expires
+func Unsubscribe(ctx context.Context, request any) (any, error) {
+	req := request.(*dto.UnsubscribeRequest)
+
+	if err := callback.Unsubscribe(server.GetIPAddr(ctx), req.SubscriptionIdentifier); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+// RenewSubscription extends (or shortens) a subscription of the client of the RPC call to expire after the interval
+// provided from now
+func RenewSubscription(ctx context.Context, request any) (any, error) {
+	req := request.(*dto.RenewSubscriptionRequest)
+
+	monitorInterval, err := getMonitorInterval(req.LengthOfMonitorIntervalInSeconds)
+	if err != nil {
+		return nil, err
+	}
+	sub, err := callback.RenewSubscription(server.GetIPAddr(ctx), req.SubscriptionIdentifier, monitorInterval)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.RenewSubscriptionResponse{Subscription: newSubscriptionInformation(sub)}, nil
+}
+
+// ListMySubscriptions lists the subscriptions of the client of the RPC call, across every callback type
+func ListMySubscriptions(ctx context.Context, _ any) (any, error) {
+	subs := callback.ListSubscriptions(server.GetIPAddr(ctx))
+
+	res := &dto.ListMySubscriptionsResponse{Subscriptions: make([]dto.SubscriptionInformation, len(subs))}
+	for i, sub := range subs {
+		res.Subscriptions[i] = newSubscriptionInformation(sub)
+	}
+	return res, nil
+}
+
+// getMonitorInterval converts the length of a monitor interval requested to a duration, it must be between 1 second and
+// maxMonitorIntervalInSeconds
+func getMonitorInterval(lengthInSeconds int64) (time.Duration, error) {
+	if lengthInSeconds <= 0 || lengthInSeconds > maxMonitorIntervalInSeconds {
+		return 0, custom_errors.NewInvalidRequestError(fmt.Sprintf("length of monitor interval must be between 1 and %d seconds", maxMonitorIntervalInSeconds))
+	}
+	return time.Duration(lengthInSeconds) * time.Second, nil
+}
+
+// newSubscriptionInformation converts a subscription to its DTO
+func newSubscriptionInformation(sub callback.Subscription) dto.SubscriptionInformation {
+	return dto.SubscriptionInformation{
+		SubscriptionIdentifier: sub.SubscriptionIdentifier,
+		CallbackType:           uint8(sub.CallbackType),
+		Item:                   sub.Item,
+		ExpiryTime:             sub.ExpiryTime.Unix(),
+	}
+}
@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/cyiafn/flight_information_system/server/custom_errors"
+	"github.com/cyiafn/flight_information_system/server/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetMonitorInterval(t *testing.T) {
+	monitorInterval, err := getMonitorInterval(maxMonitorIntervalInSeconds)
+	assert.Nil(t, err)
+	assert.Equal(t, 24*time.Hour, monitorInterval)
+
+	// lengths that would overflow to a negative duration are rejected rather than expiring immediately
+	for _, lengthInSeconds := range []int64{0, -1, maxMonitorIntervalInSeconds + 1, math.MaxInt64 / int64(time.Second) * 2} {
+		_, err := getMonitorInterval(lengthInSeconds)
+		assert.IsType(t, &custom_errors.InvalidRequestError{}, err, "length of monitor interval: %d", lengthInSeconds)
+	}
+}
+
+func TestRenewSubscriptionAboveMaxMonitorInterval(t *testing.T) {
+	s := newSubscriber(t)
+	flightIdentifier := createTestFlight(t)
+	res, err := MonitorPriceUpdates(s.ctx, &dto.MonitorPriceUpdatesCallbackRequest{FlightIdentifier: flightIdentifier, LengthOfMonitorIntervalInSeconds: 60})
+	assert.Nil(t, err)
+	subscriptionIdentifier := res.(*dto.MonitorPriceUpdatesResponse).SubscriptionIdentifier
+
+	_, err = RenewSubscription(s.ctx, &dto.RenewSubscriptionRequest{SubscriptionIdentifier: subscriptionIdentifier, LengthOfMonitorIntervalInSeconds: 10_000_000_000})
+	assert.IsType(t, &custom_errors.InvalidRequestError{}, err)
+	// the subscription is left as it was
+	_, err = Unsubscribe(s.ctx, &dto.UnsubscribeRequest{SubscriptionIdentifier: subscriptionIdentifier})
+	assert.Nil(t, err)
+}
@@ -11,28 +11,34 @@ import (
 // UpdateFlightPrice updates the flight prices for a particular flight
 func UpdateFlightPrice(_ context.Context, request any) (any, error) {
 	req := request.(*dto.UpdateFlightPriceRequest)
-	res := &dto.UpdateFlightPriceResponse{}
-
-	found := false
-	for _, flight := range database.GetAllFlights() {
-		flight := flight
-		if flight.FlightIdentifier == req.FlightIdentifier {
-			found = true
-			flight.Airfare = req.NewPrice
-
-			res.FlightIdentifier = req.FlightIdentifier
-			res.DepartureTime = flight.DepartureTime
-			res.Airfare = flight.Airfare
-			res.SourceLocation = flight.SourceLocation
-			res.DestinationLocation = flight.DestinationLocation
-			res.TotalAvailableSeats = flight.TotalAvailableSeats
-			break
-		}
+	if err := validateTimeFormat(req.TimeFormat); err != nil {
+		return nil, err
+	}
+	if req.NewPrice < 0 {
+		return nil, custom_errors.NewInvalidRequestError("new price must not be negative")
 	}
 
-	if !found {
-		return nil, custom_errors.NewNoSuchFlightIdentifierError()
+	flight, err := database.GetFlightRepository().UpdateAirfare(req.FlightIdentifier, req.NewPrice)
+	if err != nil {
+		return nil, err
 	}
+	handleMonitorPriceUpdatesCallback(flight)
 
-	return res, nil
+	localDepartureTime, localArrivalTime := localTimes(flight, req.TimeFormat)
+	return &dto.UpdateFlightPriceResponse{
+		FlightIdentifier:    flight.FlightIdentifier,
+		SourceLocation:      flight.SourceLocation,
+		DestinationLocation: flight.DestinationLocation,
+		DepartureTime:       flight.DepartureTime,
+		Airfare:             flight.Airfare,
+		TotalAvailableSeats: flight.TotalAvailableSeats,
+		ArrivalTime:         flight.ArrivalTime,
+		DurationInSeconds:   flightDuration(flight),
+		SourceTimezone:      flight.SourceTimezone,
+		DestinationTimezone: flight.DestinationTimezone,
+		Aircraft:            flight.Aircraft,
+		LocalDepartureTime:  localDepartureTime,
+		LocalArrivalTime:    localArrivalTime,
+		Status:              uint8(flight.Status),
+	}, nil
 }
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cyiafn/flight_information_system/server/custom_errors"
+	"github.com/cyiafn/flight_information_system/server/dao"
+	"github.com/cyiafn/flight_information_system/server/database"
+	"github.com/cyiafn/flight_information_system/server/dto"
+)
+
+// UpdateFlightStatus updates the status of a flight, e.g. to delay it to a new departure time, and notifies the subscribers
+// of the flight. Once a flight has been cancelled or has departed, its status can no longer be updated.
+func UpdateFlightStatus(_ context.Context, request any) (any, error) {
+	req := request.(*dto.UpdateFlightStatusRequest)
+	if err := validateTimeFormat(req.TimeFormat); err != nil {
+		return nil, err
+	}
+	status := dao.FlightStatusType(req.Status)
+	if status < dao.ScheduledFlightStatus || status > dao.DepartedFlightStatus {
+		return nil, custom_errors.NewInvalidRequestError(fmt.Sprintf("unknown flight status: %d", req.Status))
+	}
+
+	flight, err := database.GetFlightRepository().GetFlight(req.FlightIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	departureTime, arrivalTime := req.DepartureTime, req.ArrivalTime
+	// the flight keeps its duration if only the new departure time is provided
+	if departureTime != 0 && arrivalTime == 0 && flight.ArrivalTime != 0 {
+		arrivalTime = departureTime + flightDuration(flight)
+	}
+	if err := validateFlightTimes(flight, departureTime, arrivalTime); err != nil {
+		return nil, err
+	}
+
+	flight, err = database.GetFlightRepository().UpdateFlightStatus(req.FlightIdentifier, status, departureTime, arrivalTime)
+	if err != nil {
+		return nil, err
+	}
+	handleMonitorFlightStatusCallback(flight)
+
+	localDepartureTime, localArrivalTime := localTimes(flight, req.TimeFormat)
+	return &dto.UpdateFlightStatusResponse{
+		FlightIdentifier:   flight.FlightIdentifier,
+		Status:             uint8(flight.Status),
+		DepartureTime:      flight.DepartureTime,
+		ArrivalTime:        flight.ArrivalTime,
+		LocalDepartureTime: localDepartureTime,
+		LocalArrivalTime:   localArrivalTime,
+	}, nil
+}
+
+// validateFlightTimes checks that the flight still arrives after it departs with the new times, times that are 0 are unchanged
+func validateFlightTimes(flight *dao.Flight, departureTime, arrivalTime int64) error {
+	if departureTime == 0 {
+		departureTime = flight.DepartureTime
+	}
+	if arrivalTime == 0 {
+		arrivalTime = flight.ArrivalTime
+	}
+	// flights stored before arrival times were added have no arrival time
+	if arrivalTime != 0 && arrivalTime <= departureTime {
+		return custom_errors.NewInvalidRequestError("arrival time must be after departure time")
+	}
+	return nil
+}
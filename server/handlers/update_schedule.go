@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"github.com/cyiafn/flight_information_system/server/dao"
+	"github.com/cyiafn/flight_information_system/server/database"
+	"github.com/cyiafn/flight_information_system/server/dto"
+	"github.com/cyiafn/flight_information_system/server/scheduling"
+)
+
+// UpdateSchedule updates the departure time, duration, airfare or aircraft of a schedule. The update is applied to future
+// flights of the schedule that have not been booked or held, flights that have are left as they are.
+func UpdateSchedule(_ context.Context, request any) (any, error) {
+	req := request.(*dto.UpdateScheduleRequest)
+
+	schedule, err := database.GetFlightRepository().GetSchedule(req.ScheduleIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	if req.LocalDepartureTime != "" {
+		schedule.LocalDepartureTime = req.LocalDepartureTime
+	}
+	if req.DurationInSeconds != 0 {
+		schedule.DurationInSeconds = req.DurationInSeconds
+	}
+	if req.Airfare != 0 {
+		schedule.Airfare = req.Airfare
+	}
+	if req.Aircraft != "" {
+		schedule.Aircraft = req.Aircraft
+	}
+	if err := scheduling.Validate(schedule); err != nil {
+		return nil, err
+	}
+
+	futureFlights, err := getFutureFlightsOfSchedule(schedule.ScheduleIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	updatedFlights := make([]*dao.Flight, len(futureFlights))
+	for i, flight := range futureFlights {
+		departureTime, err := scheduling.Reschedule(flight.DepartureTime, schedule)
+		if err != nil {
+			return nil, err
+		}
+		updatedFlights[i] = scheduling.NewFlight(schedule, departureTime)
+		updatedFlights[i].FlightIdentifier = flight.FlightIdentifier
+	}
+
+	// the repository only updates the flights that are still not booked or held
+	updatedFlights, err = database.GetFlightRepository().UpdateSchedule(schedule, updatedFlights)
+	if err != nil {
+		return nil, err
+	}
+
+	// the airfare of every flight updated is rewritten with the next sequence number, so their subscribers are notified
+	// even if the airfare is the same
+	for _, flight := range updatedFlights {
+		handleMonitorPriceUpdatesCallback(flight)
+	}
+
+	res := &dto.UpdateScheduleResponse{
+		FlightIdentifiersUpdated:    make([]int32, len(updatedFlights)),
+		FlightIdentifiersNotUpdated: make([]int32, 0),
+	}
+	updated := make(map[int32]bool, len(updatedFlights))
+	for i, flight := range updatedFlights {
+		res.FlightIdentifiersUpdated[i] = flight.FlightIdentifier
+		updated[flight.FlightIdentifier] = true
+	}
+	for _, flight := range futureFlights {
+		if !updated[flight.FlightIdentifier] {
+			res.FlightIdentifiersNotUpdated = append(res.FlightIdentifiersNotUpdated, flight.FlightIdentifier)
+		}
+	}
+
+	return res, nil
+}
+
+// getFutureFlightsOfSchedule gets the flights of a schedule that have not departed yet
+func getFutureFlightsOfSchedule(scheduleIdentifier int32) ([]*dao.Flight, error) {
+	flights, err := database.GetFlightRepository().GetFlightsBySchedule(scheduleIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().Unix()
+	output := make([]*dao.Flight, 0, len(flights))
+	for _, flight := range flights {
+		if flight.DepartureTime > now {
+			output = append(output, flight)
+		}
+	}
+	return output, nil
+}
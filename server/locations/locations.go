@@ -0,0 +1,46 @@
+package locations
+
+import (
+	// the timezone database is embedded so that timezones can be loaded on hosts without it
+	_ "time/tzdata"
+)
+
+// registry is the registry of all locations served, used by the package level functions
+var registry *Registry
+
+func init() {
+	// initialises the registry on start
+	registry = NewRegistry(defaultLocations)
+}
+
+// Resolve gets the location known by the name from the registry of all locations served
+func Resolve(name string) (*Location, bool) {
+	return registry.Resolve(name)
+}
+
+// Normalise gets the canonical city name of the location known by the name, unknown names are returned trimmed
+func Normalise(name string) string {
+	return registry.Normalise(name)
+}
+
+// Suggest gets the canonical city names of the locations served closest to an unknown name
+func Suggest(name string) []string {
+	return registry.Suggest(name)
+}
+
+// defaultLocations are the locations served, these are simply hardcoded
+var defaultLocations = []*Location{
+	{Code: "SIN", City: "Singapore", Aliases: []string{"Changi"}, Timezone: "Asia/Singapore"},
+	{Code: "SFO", City: "San Francisco", Aliases: []string{"SF", "San Fran"}, Timezone: "America/Los_Angeles"},
+	// Kular Lumpur is a misspelling that used to be in the seeded flights
+	{Code: "KUL", City: "Kuala Lumpur", Aliases: []string{"KL", "Kular Lumpur"}, Timezone: "Asia/Kuala_Lumpur"},
+	{Code: "DPS", City: "Bali", Aliases: []string{"Denpasar"}, Timezone: "Asia/Makassar"},
+	{Code: "TYO", City: "Tokyo", Aliases: []string{"HND", "NRT", "Haneda", "Narita"}, Timezone: "Asia/Tokyo"},
+	{Code: "SEL", City: "Seoul", Aliases: []string{"ICN", "GMP", "Incheon"}, Timezone: "Asia/Seoul"},
+	{Code: "SHA", City: "Shanghai", Aliases: []string{"PVG", "Pudong"}, Timezone: "Asia/Shanghai"},
+	{Code: "HKG", City: "Hong Kong", Timezone: "Asia/Hong_Kong"},
+	{Code: "BKK", City: "Bangkok", Aliases: []string{"DMK", "Suvarnabhumi"}, Timezone: "Asia/Bangkok"},
+	{Code: "SYD", City: "Sydney", Timezone: "Australia/Sydney"},
+	{Code: "LON", City: "London", Aliases: []string{"LHR", "LGW", "Heathrow", "Gatwick"}, Timezone: "Europe/London"},
+	{Code: "NYC", City: "New York", Aliases: []string{"JFK", "EWR", "LGA", "New York City"}, Timezone: "America/New_York"},
+}
@@ -0,0 +1,145 @@
+package locations
+
+import (
+	"sort"
+	"strings"
+)
+
+/**
+The location registry contains every location flights fly between. Users may refer to a location by its IATA code, its
+city name or any of its aliases in any case, e.g. "SIN", "singapore" or "Singapore", and these are normalised to the
+canonical city name that flights are stored with.
+
+Names that are not in the registry are not rejected, they are only trimmed, so that flights can still be created to
+new locations.
+*/
+
+// maxSuggestions is the maximum number of "did you mean" suggestions returned for an unknown location
+const maxSuggestions = 3
+
+// Location is a city that flights fly from or to
+type Location struct {
+	// Code is the IATA code of the city, e.g. SIN
+	Code string
+	// City is the canonical name of the city, flights are stored with this name
+	City string
+	// Aliases are other names the city is known by, e.g. airport codes and common misspellings
+	Aliases []string
+	// Timezone is the IANA timezone of the city, e.g. Asia/Singapore
+	Timezone string
+}
+
+// Registry looks up locations by any of their names
+type Registry struct {
+	// byName are the locations keyed by the normalised code, city and aliases
+	byName map[string]*Location
+}
+
+// NewRegistry instantiates a registry of the locations provided
+func NewRegistry(locations []*Location) *Registry {
+	r := &Registry{byName: make(map[string]*Location)}
+	for _, location := range locations {
+		r.byName[normaliseName(location.Code)] = location
+		r.byName[normaliseName(location.City)] = location
+		for _, alias := range location.Aliases {
+			r.byName[normaliseName(alias)] = location
+		}
+	}
+	return r
+}
+
+// Resolve gets the location known by the name, ignoring case and extra whitespace
+func (r *Registry) Resolve(name string) (*Location, bool) {
+	location, ok := r.byName[normaliseName(name)]
+	return location, ok
+}
+
+// Normalise gets the canonical city name of the location known by the name, unknown names are returned trimmed
+func (r *Registry) Normalise(name string) string {
+	if location, ok := r.Resolve(name); ok {
+		return location.City
+	}
+	return strings.TrimSpace(name)
+}
+
+// Suggest gets the canonical city names of the locations closest to an unknown name, closest first. Nil is returned if
+// the name is known or if no location is close enough.
+func (r *Registry) Suggest(name string) []string {
+	normalisedName := normaliseName(name)
+	if _, ok := r.byName[normalisedName]; ok || normalisedName == "" {
+		return nil
+	}
+
+	// a location is as close as the closest of its names
+	maxDistance := len([]rune(normalisedName)) / 3
+	if maxDistance < 1 {
+		maxDistance = 1
+	}
+	distances := make(map[string]int)
+	for knownName, location := range r.byName {
+		distance := levenshteinDistance(normalisedName, knownName)
+		if distance > maxDistance {
+			continue
+		}
+		if closest, ok := distances[location.City]; !ok || distance < closest {
+			distances[location.City] = distance
+		}
+	}
+
+	output := make([]string, 0, len(distances))
+	for city := range distances {
+		output = append(output, city)
+	}
+	sort.Slice(output, func(i, j int) bool {
+		if distances[output[i]] != distances[output[j]] {
+			return distances[output[i]] < distances[output[j]]
+		}
+		return output[i] < output[j]
+	})
+	if len(output) > maxSuggestions {
+		output = output[:maxSuggestions]
+	}
+	if len(output) == 0 {
+		return nil
+	}
+	return output
+}
+
+// normaliseName lowercases the name and collapses whitespace so that names can be compared
+func normaliseName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// levenshteinDistance is the minimum number of single character insertions, deletions and substitutions to change a into b
+func levenshteinDistance(a, b string) int {
+	runesA, runesB := []rune(a), []rune(b)
+	// we only keep the previous row of the dynamic programming table
+	previous := make([]int, len(runesB)+1)
+	current := make([]int, len(runesB)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(runesA); i++ {
+		current[0] = i
+		for j := 1; j <= len(runesB); j++ {
+			substitutionCost := 1
+			if runesA[i-1] == runesB[j-1] {
+				substitutionCost = 0
+			}
+			current[j] = minimum(previous[j]+1, current[j-1]+1, previous[j-1]+substitutionCost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(runesB)]
+}
+
+// minimum gets the smallest of the values
+func minimum(values ...int) int {
+	output := values[0]
+	for _, value := range values[1:] {
+		if value < output {
+			output = value
+		}
+	}
+	return output
+}
@@ -0,0 +1,70 @@
+package locations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry(defaultLocations)
+
+	tests := []struct {
+		Name                string
+		Input               string
+		ExpectedNormalised  string
+		ExpectedSuggestions []string
+	}{
+		{
+			Name:               "canonical city name",
+			Input:              "Singapore",
+			ExpectedNormalised: "Singapore",
+		},
+		{
+			Name:               "lowercase with extra whitespace",
+			Input:              "  san   francisco ",
+			ExpectedNormalised: "San Francisco",
+		},
+		{
+			Name:               "IATA code",
+			Input:              "sin",
+			ExpectedNormalised: "Singapore",
+		},
+		{
+			Name:               "alias",
+			Input:              "Kular Lumpur",
+			ExpectedNormalised: "Kuala Lumpur",
+		},
+		{
+			Name:                "typo",
+			Input:               "Singapor",
+			ExpectedNormalised:  "Singapor",
+			ExpectedSuggestions: []string{"Singapore"},
+		},
+		{
+			Name:                "typo of a code",
+			Input:               "SNN",
+			ExpectedNormalised:  "SNN",
+			ExpectedSuggestions: []string{"Singapore"},
+		},
+		{
+			Name:                "typo of a code close to several locations",
+			Input:               "SEA",
+			ExpectedNormalised:  "SEA",
+			ExpectedSuggestions: []string{"Seoul", "Shanghai"},
+		},
+		{
+			Name:               "unknown location with nothing close",
+			Input:              "Reykjavik",
+			ExpectedNormalised: "Reykjavik",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.Name, func(t *testing.T) {
+			assert.Equal(t, test.ExpectedNormalised, registry.Normalise(test.Input))
+			assert.Equal(t, test.ExpectedSuggestions, registry.Suggest(test.Input))
+		})
+	}
+}
@@ -1,19 +1,16 @@
 package main
 
 import (
-	"context"
 	"flag"
 	"github.com/cyiafn/flight_information_system/server/database"
-	"github.com/cyiafn/flight_information_system/server/dto"
 	"github.com/cyiafn/flight_information_system/server/handlers"
+	"github.com/cyiafn/flight_information_system/server/logs"
 	"github.com/cyiafn/flight_information_system/server/server"
 	"github.com/cyiafn/flight_information_system/server/utils"
 )
 
-// startup initialisation
-func init() {
-	database.PopulateFlights()
-}
+// postgresDSNKey for env var
+const postgresDSNKey = "POSTGRES_DSN"
 
 // entry point
 func main() {
@@ -23,18 +20,50 @@ func main() {
 	utils.GracefulShutdown(server.SpinDown)
 	// boots up the server
 	atMostOnce := flag.String("amo", "true", "at most once invocation")
+	callbackSender := flag.String("callbacks", "listener", "sends callbacks from the listener's port (listener) or by dialing a new socket for each (dial)")
+	flightStore := flag.String("db", "memory", "flight store, memory or postgres (dsn is read from the POSTGRES_DSN env var)")
 	flag.Parse()
 
-	server.Boot(routes, *atMostOnce == "true")
+	// selects and populates the flight store
+	database.Init(newFlightRepository(*flightStore))
+	if err := database.PopulateFlights(database.GetFlightRepository()); err != nil {
+		logs.Fatal("unable to populate flights, err: %v", err)
+	}
+	if err := handlers.ResumeHoldExpiries(); err != nil {
+		logs.Fatal("unable to schedule expiry of seat holds, err: %v", err)
+	}
+
+	server.Boot(routes, *atMostOnce == "true", newCallbackSenderType(*callbackSender))
+}
+
+// newCallbackSenderType selects how callbacks are sent based on the callbacks flag
+func newCallbackSenderType(callbackSender string) server.CallbackSenderType {
+	switch callbackSender {
+	case "listener":
+		return server.ListenerCallbackSender
+	case "dial":
+		return server.DialCallbackSender
+	}
+	logs.Fatal("unknown callback sender: %s", callbackSender)
+	return 0
 }
 
-// routes are the  routes from request to handlers
-var routes = map[dto.RequestType]func(ctx context.Context, request any) (any, error){
-	dto.PingRequestType:                 handlers.Ping,
-	dto.GetFlightIdentifiersRequestType: handlers.GetFlightIdentifiers,
-	dto.GetFlightInformationRequestType: handlers.GetFlightInformation,
-	dto.MakeSeatReservationRequestType:  handlers.MakeSeatReservation,
-	dto.MonitorSeatUpdatesRequestType:   handlers.MonitorSeatUpdates,
-	dto.UpdateFlightPriceRequestType:    handlers.UpdateFlightPrice,
-	dto.CreateFlightRequestType:         handlers.CreateFlight,
+// newFlightRepository instantiates the flight store based on the db flag
+func newFlightRepository(flightStore string) database.FlightRepository {
+	switch flightStore {
+	case "memory":
+		return database.NewInMemoryFlightRepository()
+	case "postgres":
+		dsn, ok := utils.GetEnvStr(postgresDSNKey)
+		if !ok {
+			logs.Fatal("%s env var must be set to use the postgres flight store", postgresDSNKey)
+		}
+		repository, err := database.NewPostgresFlightRepository(dsn)
+		if err != nil {
+			logs.Fatal("unable to instantiate postgres flight store, err: %v", err)
+		}
+		return repository
+	}
+	logs.Fatal("unknown flight store: %s", flightStore)
+	return nil
 }
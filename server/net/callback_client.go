@@ -9,9 +9,25 @@ import (
 /**
 This callback_client is used to only send data back to the subscriber.
 Here, we open and close connections as needed only and do not define a singular port so that we can
-concurrently broadcast to all subscribers.
+concurrently broadcast to all subscribers. Note that the data comes from an ephemeral port rather than the listener's, see
+UDPListener.SendTo for sending from the listener's port instead.
 */
 
+// Sender sends a payload to an IP:port
+type Sender interface {
+	SendTo(data []byte, addr string) error
+}
+
+var _ Sender = DialSender{}
+
+// DialSender is a Sender that dials a new UDP socket for every payload sent, using SendData
+type DialSender struct{}
+
+// SendTo sends a payload to an IP:port
+func (DialSender) SendTo(data []byte, addr string) error {
+	return SendData(data, addr)
+}
+
 // SendData simply sends a payload to an IP:port
 func SendData(data []byte, addr string) error {
 	if len(data) == 0 {
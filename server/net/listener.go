@@ -5,13 +5,16 @@ import (
 	"fmt"
 	"github.com/cyiafn/flight_information_system/server/utils"
 	"net"
+	"sync"
 
 	"github.com/cyiafn/flight_information_system/server/logs"
+	"github.com/pkg/errors"
 )
 
 // Validate interface compliance for listener at compile time.
 // Useful to swap for TCP listener in the future
 var _ Listener = (*UDPListener)(nil)
+var _ Sender = (*UDPListener)(nil)
 
 const (
 	// address of listener of server
@@ -26,6 +29,8 @@ const (
 type Listener interface {
 	StartListening()
 	StopListening()
+	// Sender sends data from the listener's own socket, so that it comes from the same IP:Port requests are sent to
+	Sender() Sender
 }
 
 // NewUDPListener instantiates a listener.
@@ -38,7 +43,10 @@ func NewUDPListener(port int, requestHandler func(ctx context.Context, request [
 }
 
 // UDPListener is a UDP listener.
+// This is CONCURRENT-SAFE
 type UDPListener struct {
+	// RWMutex guards listener, which is set once listening starts and read by callbacks sent from other goroutines
+	sync.RWMutex
 	// listener stores the actual listener object
 	listener net.PacketConn
 	// Port is the port of the listener
@@ -59,7 +67,9 @@ func (u *UDPListener) StartListening() {
 	if err != nil {
 		logs.Fatal("unable to start udp listener, err: %v", err)
 	}
+	u.Lock()
 	u.listener = udpServer
+	u.Unlock()
 
 	logs.Info("Good day, listener booted up.")
 
@@ -68,10 +78,11 @@ func (u *UDPListener) StartListening() {
 }
 
 func (u *UDPListener) listen() {
+	listener := u.getListener()
 	for {
 		buf := make([]byte, DefaultByteBufferSize)
 		// blocks until there is data being read from buffer
-		n, addr, err := u.listener.ReadFrom(buf)
+		n, addr, err := listener.ReadFrom(buf)
 		if err != nil {
 			if err.Error() == fmt.Sprintf("read udp [::]:%v: use of closed network connection", u.Port) {
 				return
@@ -85,12 +96,14 @@ func (u *UDPListener) listen() {
 		// we add the IP address:port of the request to the context object
 		ctx := context.WithValue(context.Background(), "addr", addr.String())
 		// spawn a go routine to process each incoming data
-		go u.handleIncomingData(ctx, buf, addr)
+		go u.handleIncomingData(ctx, listener, buf, addr)
 	}
 }
 
 // handleIncomingData handles all incoming data and processes data to return
-func (u *UDPListener) handleIncomingData(ctx context.Context, buf []byte, addr net.Addr) {
+func (u *UDPListener) handleIncomingData(ctx context.Context, listener net.PacketConn, buf []byte, addr net.Addr) {
+	// a panic while handling one request must not terminate the server
+	defer utils.HandlePanic()
 	// passes the request to the requestHandler (server callback function) outlined during instantiation of this object
 	// this will return a response and whether the request was processed or not
 	resp, processed := u.RequestHandler(ctx, buf)
@@ -107,7 +120,7 @@ func (u *UDPListener) handleIncomingData(ctx context.Context, buf []byte, addr n
 	// for each byte array buffer, we send it back to the client
 	for _, buf := range resp {
 		buf := buf
-		_, err := u.listener.WriteTo(buf, addr)
+		_, err := listener.WriteTo(buf, addr)
 		if err != nil {
 			logs.Error("unable to reply, err: %v", err)
 			return
@@ -116,15 +129,49 @@ func (u *UDPListener) handleIncomingData(ctx context.Context, buf []byte, addr n
 
 }
 
+// Sender returns the listener itself, which sends through the listener's socket once it has started listening
+func (u *UDPListener) Sender() Sender {
+	return u
+}
+
+// SendTo sends a payload to an IP:Port from the listener's socket, the same way replies are sent
+func (u *UDPListener) SendTo(data []byte, addr string) error {
+	if len(data) == 0 {
+		logs.Warn("no data sent, payload had nothing")
+		return nil
+	}
+	listener := u.getListener()
+	if listener == nil {
+		return errors.Errorf("listener has not started listening")
+	}
+
+	udpAddr, err := net.ResolveUDPAddr("udp", addr)
+	if err != nil {
+		logs.Error("unable to resolve UDP address %s, err: %v", addr, err)
+		return err
+	}
+	if _, err = listener.WriteTo(data, udpAddr); err != nil {
+		logs.Error("error sending payload: %v", err)
+	}
+	return err
+}
+
 // StopListening gracefully closes the listener, freeing up the port and terminating the listeners services
 func (u *UDPListener) StopListening() {
-	err := u.listener.Close()
+	err := u.getListener().Close()
 	if err != nil {
 		logs.Warn("unable to close listener, err: %v, you might need to restart your computer")
 	}
 	logs.Info("Listener stopped")
 }
 
+// getListener gets the listener's socket, nil until it has started listening
+func (u *UDPListener) getListener() net.PacketConn {
+	u.RLock()
+	defer u.RUnlock()
+	return u.listener
+}
+
 func getIPAddress() string {
 	port, ok := utils.GetEnvStr(udpAddressEnvKey)
 	if !ok {
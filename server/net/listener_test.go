@@ -0,0 +1,47 @@
+package net
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUDPListenerSendTo(t *testing.T) {
+	listenerConn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.ParseIP("127.0.0.1")})
+	assert.Nil(t, err)
+	defer listenerConn.Close()
+	subscriberConn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.ParseIP("127.0.0.1")})
+	assert.Nil(t, err)
+	defer subscriberConn.Close()
+
+	t.Run("sends from the listener's port", func(t *testing.T) {
+		sender := (&UDPListener{listener: listenerConn}).Sender()
+		assert.Nil(t, sender.SendTo([]byte("callback"), subscriberConn.LocalAddr().String()))
+
+		buf := make([]byte, DefaultByteBufferSize)
+		assert.Nil(t, subscriberConn.SetReadDeadline(time.Now().Add(time.Second)))
+		n, from, err := subscriberConn.ReadFromUDP(buf)
+		assert.Nil(t, err)
+		assert.Equal(t, "callback", string(buf[:n]))
+		assert.Equal(t, listenerConn.LocalAddr().String(), from.String())
+	})
+
+	t.Run("errors before listening", func(t *testing.T) {
+		sender := (&UDPListener{}).Sender()
+		assert.NotNil(t, sender.SendTo([]byte("callback"), subscriberConn.LocalAddr().String()))
+	})
+
+	t.Run("sends while starting to listen", func(t *testing.T) {
+		listener := NewUDPListener(0, func(ctx context.Context, request []byte) ([][]byte, bool) { return nil, false })
+		sender := listener.Sender()
+		// the listener is left listening, as StartListening blocks until the process exits
+		go listener.StartListening()
+
+		assert.Eventually(t, func() bool {
+			return sender.SendTo([]byte("callback"), subscriberConn.LocalAddr().String()) == nil
+		}, time.Second, time.Millisecond)
+	})
+}
@@ -0,0 +1,42 @@
+// Code generated by rpcgen from flight_information_system.idl. DO NOT EDIT.
+
+package main
+
+import (
+	"context"
+
+	"github.com/cyiafn/flight_information_system/server/dto"
+	"github.com/cyiafn/flight_information_system/server/handlers"
+)
+
+// routes are the routes from request to handlers
+var routes = map[dto.RequestType]func(ctx context.Context, request any) (any, error){
+	dto.PingRequestType:                 handlers.Ping,
+	dto.GetFlightIdentifiersRequestType: handlers.GetFlightIdentifiers,
+	dto.GetFlightInformationRequestType: handlers.GetFlightInformation,
+	dto.MakeSeatReservationRequestType:  handlers.MakeSeatReservation,
+	dto.MonitorSeatUpdatesRequestType:   handlers.MonitorSeatUpdates,
+	dto.UpdateFlightPriceRequestType:    handlers.UpdateFlightPrice,
+	dto.CreateFlightRequestType:         handlers.CreateFlight,
+	dto.CancelReservationRequestType:    handlers.CancelReservation,
+	dto.GetReservationRequestType:       handlers.GetReservation,
+	dto.GetSeatMapRequestType:           handlers.GetSeatMap,
+	dto.ReserveSpecificSeatsRequestType: handlers.ReserveSpecificSeats,
+	dto.HoldSeatsRequestType:            handlers.HoldSeats,
+	dto.ConfirmHoldRequestType:          handlers.ConfirmHold,
+	dto.SearchFlightsRequestType:        handlers.SearchFlights,
+	dto.FindItinerariesRequestType:      handlers.FindItineraries,
+	dto.CreateScheduleRequestType:       handlers.CreateSchedule,
+	dto.UpdateScheduleRequestType:       handlers.UpdateSchedule,
+	dto.CancelScheduleRequestType:       handlers.CancelSchedule,
+	dto.UpdateFlightStatusRequestType:   handlers.UpdateFlightStatus,
+	dto.CancelFlightRequestType:         handlers.CancelFlight,
+	dto.MonitorPriceUpdatesRequestType:  handlers.MonitorPriceUpdates,
+	dto.MonitorNewFlightsRequestType:    handlers.MonitorNewFlights,
+	dto.MonitorFlightStatusRequestType:  handlers.MonitorFlightStatus,
+	dto.AckCallbackRequestType:          handlers.AckCallback,
+	dto.GetUpdatesSinceRequestType:      handlers.GetUpdatesSince,
+	dto.UnsubscribeRequestType:          handlers.Unsubscribe,
+	dto.RenewSubscriptionRequestType:    handlers.RenewSubscription,
+	dto.ListMySubscriptionsRequestType:  handlers.ListMySubscriptions,
+}
@@ -0,0 +1,181 @@
+package routing
+
+import (
+	"container/heap"
+	"sort"
+
+	"github.com/cyiafn/flight_information_system/server/dao"
+)
+
+/**
+The route graph has a node for every location and an edge for every flight between two locations. Itineraries are found
+with a best-first search: partial itineraries are explored from a priority queue ordered by their rank (total airfare or
+total duration), and as extending an itinerary never lowers either of them, itineraries reaching the destination are
+found in rank order and the search stops as soon as enough have been found.
+*/
+
+// RankType is what itineraries are ranked by, lowest first
+type RankType uint8
+
+const (
+	TotalAirfareRank RankType = iota + 1
+	TotalDurationRank
+)
+
+// ItineraryCriteria are the constraints and ranking of the itineraries to find
+type ItineraryCriteria struct {
+	SourceLocation      string
+	DestinationLocation string
+	// MaxStops is the maximum number of connections, 0 only allows direct flights
+	MaxStops int
+	// MinConnectionTime is the minimum number of seconds between the arrival of a leg and the departure of the next leg
+	MinConnectionTime int64
+	RankBy            RankType
+	// Limit is the maximum number of itineraries returned
+	Limit int
+}
+
+// Itinerary is a route from source to destination through one or more flights
+type Itinerary struct {
+	Legs         []*dao.Flight
+	TotalAirfare float64
+	// TotalDuration is the number of seconds from the departure of the first leg to the arrival of the last leg
+	TotalDuration int64
+}
+
+// RouteGraph is the graph of flights between locations
+type RouteGraph struct {
+	// departures are the flights departing from each location ordered by departure time
+	departures map[string][]*dao.Flight
+}
+
+// NewRouteGraph builds a route graph from flights, flights without any available seats or that cannot be booked are left out
+func NewRouteGraph(flights []*dao.Flight) *RouteGraph {
+	g := &RouteGraph{departures: make(map[string][]*dao.Flight)}
+	for _, flight := range flights {
+		if flight.TotalAvailableSeats <= 0 || !flight.IsBookable() {
+			continue
+		}
+		g.departures[flight.SourceLocation] = append(g.departures[flight.SourceLocation], flight)
+	}
+	for _, flights := range g.departures {
+		flights := flights
+		sort.SliceStable(flights, func(i, j int) bool { return flights[i].DepartureTime < flights[j].DepartureTime })
+	}
+	return g
+}
+
+// FindItineraries finds up to Limit itineraries matching the criteria in rank order. Itineraries never visit a location twice.
+func (g *RouteGraph) FindItineraries(criteria *ItineraryCriteria) []*Itinerary {
+	queue := &itineraryQueue{rankBy: criteria.RankBy}
+	for _, flight := range g.departures[criteria.SourceLocation] {
+		heap.Push(queue, (&Itinerary{}).extend(flight))
+	}
+
+	output := make([]*Itinerary, 0)
+	for queue.Len() > 0 && len(output) < criteria.Limit {
+		itinerary := heap.Pop(queue).(*Itinerary)
+		lastLeg := itinerary.Legs[len(itinerary.Legs)-1]
+		if lastLeg.DestinationLocation == criteria.DestinationLocation {
+			output = append(output, itinerary)
+			continue
+		}
+		if len(itinerary.Legs) > criteria.MaxStops {
+			continue
+		}
+
+		// departures are ordered by departure time, so we skip straight to the first one we can connect to
+		departures := g.departures[lastLeg.DestinationLocation]
+		earliestDeparture := arrivalTime(lastLeg) + criteria.MinConnectionTime
+		first := sort.Search(len(departures), func(i int) bool { return departures[i].DepartureTime >= earliestDeparture })
+		for _, flight := range departures[first:] {
+			if itinerary.visits(flight.DestinationLocation) {
+				continue
+			}
+			heap.Push(queue, itinerary.extend(flight))
+		}
+	}
+	return output
+}
+
+// extend makes a new itinerary with the flight added as the last leg
+func (i *Itinerary) extend(flight *dao.Flight) *Itinerary {
+	legs := make([]*dao.Flight, len(i.Legs), len(i.Legs)+1)
+	copy(legs, i.Legs)
+	legs = append(legs, flight)
+	return &Itinerary{
+		Legs:          legs,
+		TotalAirfare:  i.TotalAirfare + flight.Airfare,
+		TotalDuration: arrivalTime(flight) - legs[0].DepartureTime,
+	}
+}
+
+// arrivalTime gets the arrival time of the flight, flights stored before arrival times were added are treated as
+// arriving when they depart
+func arrivalTime(flight *dao.Flight) int64 {
+	if flight.ArrivalTime == 0 {
+		return flight.DepartureTime
+	}
+	return flight.ArrivalTime
+}
+
+// visits checks if the itinerary has departed from the location, the location arrived at is checked by the caller
+func (i *Itinerary) visits(location string) bool {
+	for _, leg := range i.Legs {
+		if leg.SourceLocation == location {
+			return true
+		}
+	}
+	return false
+}
+
+// itineraryQueue is a min-heap of itineraries by rank, it implements heap.Interface
+type itineraryQueue struct {
+	itineraries []*Itinerary
+	rankBy      RankType
+}
+
+func (q *itineraryQueue) Len() int {
+	return len(q.itineraries)
+}
+
+// Less ranks by the criteria first, then by fewer legs, then by the other criteria, then by flight identifiers so that
+// the order is deterministic
+func (q *itineraryQueue) Less(i, j int) bool {
+	a, b := q.itineraries[i], q.itineraries[j]
+	primaryA, secondaryA := a.TotalAirfare, float64(a.TotalDuration)
+	primaryB, secondaryB := b.TotalAirfare, float64(b.TotalDuration)
+	if q.rankBy == TotalDurationRank {
+		primaryA, secondaryA = secondaryA, primaryA
+		primaryB, secondaryB = secondaryB, primaryB
+	}
+	if primaryA != primaryB {
+		return primaryA < primaryB
+	}
+	if len(a.Legs) != len(b.Legs) {
+		return len(a.Legs) < len(b.Legs)
+	}
+	if secondaryA != secondaryB {
+		return secondaryA < secondaryB
+	}
+	for k := range a.Legs {
+		if a.Legs[k].FlightIdentifier != b.Legs[k].FlightIdentifier {
+			return a.Legs[k].FlightIdentifier < b.Legs[k].FlightIdentifier
+		}
+	}
+	return false
+}
+
+func (q *itineraryQueue) Swap(i, j int) {
+	q.itineraries[i], q.itineraries[j] = q.itineraries[j], q.itineraries[i]
+}
+
+func (q *itineraryQueue) Push(x any) {
+	q.itineraries = append(q.itineraries, x.(*Itinerary))
+}
+
+func (q *itineraryQueue) Pop() any {
+	last := q.itineraries[len(q.itineraries)-1]
+	q.itineraries = q.itineraries[:len(q.itineraries)-1]
+	return last
+}
@@ -0,0 +1,93 @@
+package routing
+
+import (
+	"testing"
+
+	"github.com/cyiafn/flight_information_system/server/dao"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouteGraphFindItineraries(t *testing.T) {
+	flights := []*dao.Flight{
+		{FlightIdentifier: 1, SourceLocation: "Singapore", DestinationLocation: "Tokyo", DepartureTime: 1000, Airfare: 500, TotalAvailableSeats: 10},
+		{FlightIdentifier: 2, SourceLocation: "Tokyo", DestinationLocation: "Seoul", DepartureTime: 5000, Airfare: 200, TotalAvailableSeats: 10},
+		{FlightIdentifier: 3, SourceLocation: "Singapore", DestinationLocation: "Seoul", DepartureTime: 2000, Airfare: 900, TotalAvailableSeats: 10},
+		{FlightIdentifier: 4, SourceLocation: "Singapore", DestinationLocation: "Bali", DepartureTime: 1000, Airfare: 100, TotalAvailableSeats: 10},
+		{FlightIdentifier: 5, SourceLocation: "Bali", DestinationLocation: "Tokyo", DepartureTime: 2000, Airfare: 100, TotalAvailableSeats: 10},
+		// departs too soon after flight 1 arrives in Tokyo for most connection times
+		{FlightIdentifier: 6, SourceLocation: "Tokyo", DestinationLocation: "Seoul", DepartureTime: 1500, Airfare: 50, TotalAvailableSeats: 10},
+		// fully booked flights are left out
+		{FlightIdentifier: 7, SourceLocation: "Singapore", DestinationLocation: "Seoul", DepartureTime: 1000, Airfare: 1, TotalAvailableSeats: 0},
+		// loops back to the source
+		{FlightIdentifier: 8, SourceLocation: "Tokyo", DestinationLocation: "Singapore", DepartureTime: 3000, Airfare: 1, TotalAvailableSeats: 10},
+	}
+
+	tests := []struct {
+		Name                      string
+		Criteria                  ItineraryCriteria
+		ExpectedFlightIdentifiers [][]int32
+	}{
+		{
+			Name:                      "direct flights only",
+			Criteria:                  ItineraryCriteria{SourceLocation: "Singapore", DestinationLocation: "Seoul", MaxStops: 0, MinConnectionTime: 1000, RankBy: TotalAirfareRank, Limit: 10},
+			ExpectedFlightIdentifiers: [][]int32{{3}},
+		},
+		{
+			Name:                      "1 stop ranked by airfare",
+			Criteria:                  ItineraryCriteria{SourceLocation: "Singapore", DestinationLocation: "Seoul", MaxStops: 1, MinConnectionTime: 1000, RankBy: TotalAirfareRank, Limit: 10},
+			ExpectedFlightIdentifiers: [][]int32{{1, 2}, {3}},
+		},
+		{
+			Name:                      "2 stops ranked by airfare",
+			Criteria:                  ItineraryCriteria{SourceLocation: "Singapore", DestinationLocation: "Seoul", MaxStops: 2, MinConnectionTime: 1000, RankBy: TotalAirfareRank, Limit: 10},
+			ExpectedFlightIdentifiers: [][]int32{{4, 5, 2}, {1, 2}, {3}},
+		},
+		{
+			Name:                      "2 stops ranked by duration",
+			Criteria:                  ItineraryCriteria{SourceLocation: "Singapore", DestinationLocation: "Seoul", MaxStops: 2, MinConnectionTime: 1000, RankBy: TotalDurationRank, Limit: 10},
+			ExpectedFlightIdentifiers: [][]int32{{3}, {1, 2}, {4, 5, 2}},
+		},
+		{
+			Name:                      "shorter connection time allows the cheaper connection",
+			Criteria:                  ItineraryCriteria{SourceLocation: "Singapore", DestinationLocation: "Seoul", MaxStops: 1, MinConnectionTime: 500, RankBy: TotalAirfareRank, Limit: 2},
+			ExpectedFlightIdentifiers: [][]int32{{1, 6}, {1, 2}},
+		},
+		{
+			Name:                      "no route",
+			Criteria:                  ItineraryCriteria{SourceLocation: "Seoul", DestinationLocation: "Singapore", MaxStops: 3, MinConnectionTime: 0, RankBy: TotalAirfareRank, Limit: 10},
+			ExpectedFlightIdentifiers: [][]int32{},
+		},
+	}
+
+	graph := NewRouteGraph(flights)
+	for _, test := range tests {
+		test := test
+		t.Run(test.Name, func(t *testing.T) {
+			itineraries := graph.FindItineraries(&test.Criteria)
+			flightIdentifiers := make([][]int32, len(itineraries))
+			for i, itinerary := range itineraries {
+				flightIdentifiers[i] = make([]int32, len(itinerary.Legs))
+				for j, leg := range itinerary.Legs {
+					flightIdentifiers[i][j] = leg.FlightIdentifier
+				}
+			}
+			assert.Equal(t, test.ExpectedFlightIdentifiers, flightIdentifiers)
+		})
+	}
+
+	// totals are summed across legs
+	itineraries := graph.FindItineraries(&ItineraryCriteria{SourceLocation: "Singapore", DestinationLocation: "Seoul", MaxStops: 2, MinConnectionTime: 1000, RankBy: TotalAirfareRank, Limit: 1})
+	assert.Equal(t, 400.0, itineraries[0].TotalAirfare)
+	assert.Equal(t, int64(4000), itineraries[0].TotalDuration)
+
+	// connections are measured from the arrival of the previous leg
+	graph = NewRouteGraph([]*dao.Flight{
+		{FlightIdentifier: 1, SourceLocation: "Singapore", DestinationLocation: "Tokyo", DepartureTime: 1000, ArrivalTime: 3000, TotalAvailableSeats: 10},
+		{FlightIdentifier: 2, SourceLocation: "Tokyo", DestinationLocation: "Seoul", DepartureTime: 3500, ArrivalTime: 4500, TotalAvailableSeats: 10},
+		{FlightIdentifier: 3, SourceLocation: "Tokyo", DestinationLocation: "Seoul", DepartureTime: 4000, ArrivalTime: 5000, TotalAvailableSeats: 10},
+	})
+	itineraries = graph.FindItineraries(&ItineraryCriteria{SourceLocation: "Singapore", DestinationLocation: "Seoul", MaxStops: 1, MinConnectionTime: 1000, RankBy: TotalDurationRank, Limit: 10})
+	assert.Len(t, itineraries, 1)
+	assert.Equal(t, int32(3), itineraries[0].Legs[1].FlightIdentifier)
+	assert.Equal(t, int64(4000), itineraries[0].TotalDuration)
+}
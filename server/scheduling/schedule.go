@@ -0,0 +1,118 @@
+package scheduling
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cyiafn/flight_information_system/server/custom_errors"
+	"github.com/cyiafn/flight_information_system/server/dao"
+)
+
+/**
+Schedules describe recurring flights by the local time of day and the days of the week they depart on in the source
+timezone. Departure times are worked out in the source timezone so that a flight departing at 08:30 keeps departing at
+08:30 local time across daylight saving changes.
+*/
+
+const (
+	// DateLayout is the layout of StartDate and EndDate
+	DateLayout = "2006-01-02"
+	// TimeOfDayLayout is the layout of LocalDepartureTime
+	TimeOfDayLayout = "15:04"
+	// maxScheduleDays caps the date range of a schedule as a flight is stored for every departure up front
+	maxScheduleDays = 366
+	// allDaysOfWeek is the bitmask of every day of the week
+	allDaysOfWeek = 1<<7 - 1
+)
+
+// Validate checks that the schedule describes at least 1 departure and that its date range is not too long. The
+// timezones are expected to be validated by the caller.
+func Validate(schedule *dao.Schedule) error {
+	if schedule.DaysOfWeek == 0 || schedule.DaysOfWeek > allDaysOfWeek {
+		return custom_errors.NewInvalidRequestError("days of week must be a bitmask of at least 1 day, bit 0 is Sunday and bit 6 is Saturday")
+	}
+	if _, err := time.Parse(TimeOfDayLayout, schedule.LocalDepartureTime); err != nil {
+		return custom_errors.NewInvalidRequestError(fmt.Sprintf("local departure time must be in the format %s", TimeOfDayLayout))
+	}
+	startDate, startErr := time.Parse(DateLayout, schedule.StartDate)
+	endDate, endErr := time.Parse(DateLayout, schedule.EndDate)
+	if startErr != nil || endErr != nil {
+		return custom_errors.NewInvalidRequestError(fmt.Sprintf("start and end dates must be in the format %s", DateLayout))
+	}
+	if endDate.Before(startDate) || endDate.Sub(startDate) >= maxScheduleDays*24*time.Hour {
+		return custom_errors.NewInvalidRequestError(fmt.Sprintf("end date must not be before start date and the schedule must not be longer than %d days", maxScheduleDays))
+	}
+	if schedule.DurationInSeconds <= 0 || schedule.Airfare < 0 {
+		return custom_errors.NewInvalidRequestError("duration must be more than 0 and airfare must not be negative")
+	}
+	if schedule.TotalSeats <= 0 || schedule.TotalSeats > dao.MaxTotalSeats {
+		return custom_errors.NewInvalidRequestError(fmt.Sprintf("total seats must be from 1 to %d", dao.MaxTotalSeats))
+	}
+	return nil
+}
+
+// DepartureTimes gets the unix time of every departure of a valid schedule in ascending order
+func DepartureTimes(schedule *dao.Schedule) ([]int64, error) {
+	location, err := time.LoadLocation(schedule.SourceTimezone)
+	if err != nil {
+		return nil, err
+	}
+	startDate, err := time.Parse(DateLayout, schedule.StartDate)
+	if err != nil {
+		return nil, err
+	}
+	endDate, err := time.Parse(DateLayout, schedule.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]int64, 0)
+	// dates are iterated in UTC as every day has 24 hours in UTC, the departure itself is in the source timezone
+	for date := startDate; !date.After(endDate); date = date.AddDate(0, 0, 1) {
+		if schedule.DaysOfWeek&(1<<date.Weekday()) == 0 {
+			continue
+		}
+		departureTime, err := departureTimeOn(date, schedule.LocalDepartureTime, location)
+		if err != nil {
+			return nil, err
+		}
+		output = append(output, departureTime)
+	}
+	return output, nil
+}
+
+// Reschedule gets the departure time of a flight departing at departureTime if it departed at the local departure time
+// of the schedule instead, on the same local date
+func Reschedule(departureTime int64, schedule *dao.Schedule) (int64, error) {
+	location, err := time.LoadLocation(schedule.SourceTimezone)
+	if err != nil {
+		return 0, err
+	}
+	return departureTimeOn(time.Unix(departureTime, 0).In(location), schedule.LocalDepartureTime, location)
+}
+
+// NewFlight makes the flight of the schedule departing at departureTime, with all seats available
+func NewFlight(schedule *dao.Schedule, departureTime int64) *dao.Flight {
+	return &dao.Flight{
+		SourceLocation:      schedule.SourceLocation,
+		DestinationLocation: schedule.DestinationLocation,
+		DepartureTime:       departureTime,
+		Airfare:             schedule.Airfare,
+		TotalAvailableSeats: schedule.TotalSeats,
+		ArrivalTime:         departureTime + schedule.DurationInSeconds,
+		SourceTimezone:      schedule.SourceTimezone,
+		DestinationTimezone: schedule.DestinationTimezone,
+		Aircraft:            schedule.Aircraft,
+		ScheduleIdentifier:  schedule.ScheduleIdentifier,
+	}
+}
+
+// departureTimeOn gets the unix time of the local time of day on the date provided, only the year, month and day of
+// date are used
+func departureTimeOn(date time.Time, localDepartureTime string, location *time.Location) (int64, error) {
+	timeOfDay, err := time.Parse(TimeOfDayLayout, localDepartureTime)
+	if err != nil {
+		return 0, err
+	}
+	return time.Date(date.Year(), date.Month(), date.Day(), timeOfDay.Hour(), timeOfDay.Minute(), 0, 0, location).Unix(), nil
+}
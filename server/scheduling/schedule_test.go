@@ -0,0 +1,83 @@
+package scheduling
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cyiafn/flight_information_system/server/custom_errors"
+	"github.com/cyiafn/flight_information_system/server/dao"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestSchedule() *dao.Schedule {
+	return &dao.Schedule{
+		SourceLocation:      "Seattle",
+		DestinationLocation: "Los Angeles",
+		SourceTimezone:      "America/Los_Angeles",
+		DestinationTimezone: "America/Los_Angeles",
+		// Monday and Friday
+		DaysOfWeek:         1<<time.Monday | 1<<time.Friday,
+		LocalDepartureTime: "08:30",
+		DurationInSeconds:  9000,
+		StartDate:          "2023-11-01",
+		EndDate:            "2023-11-10",
+		Airfare:            200,
+		TotalSeats:         120,
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Modify func(schedule *dao.Schedule)
+		Valid  bool
+	}{
+		{Name: "valid", Modify: func(schedule *dao.Schedule) {}, Valid: true},
+		{Name: "no days of week", Modify: func(schedule *dao.Schedule) { schedule.DaysOfWeek = 0 }},
+		{Name: "too many days of week", Modify: func(schedule *dao.Schedule) { schedule.DaysOfWeek = 1 << 7 }},
+		{Name: "bad time of day", Modify: func(schedule *dao.Schedule) { schedule.LocalDepartureTime = "8.30am" }},
+		{Name: "bad date", Modify: func(schedule *dao.Schedule) { schedule.StartDate = "01/11/2023" }},
+		{Name: "end before start", Modify: func(schedule *dao.Schedule) { schedule.EndDate = "2023-10-31" }},
+		{Name: "too long", Modify: func(schedule *dao.Schedule) { schedule.EndDate = "2024-11-01" }},
+		{Name: "no seats", Modify: func(schedule *dao.Schedule) { schedule.TotalSeats = 0 }},
+		{Name: "too many seats", Modify: func(schedule *dao.Schedule) { schedule.TotalSeats = dao.MaxTotalSeats + 1 }},
+		{Name: "no duration", Modify: func(schedule *dao.Schedule) { schedule.DurationInSeconds = 0 }},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.Name, func(t *testing.T) {
+			schedule := newTestSchedule()
+			test.Modify(schedule)
+			err := Validate(schedule)
+			if test.Valid {
+				assert.Nil(t, err)
+			} else {
+				assert.IsType(t, &custom_errors.InvalidRequestError{}, err)
+			}
+		})
+	}
+}
+
+func TestDepartureTimes(t *testing.T) {
+	schedule := newTestSchedule()
+	departureTimes, err := DepartureTimes(schedule)
+	assert.Nil(t, err)
+
+	// daylight saving time ends on 2023-11-05 in Los Angeles, the local departure time stays at 08:30
+	assert.Equal(t, []int64{
+		time.Date(2023, 11, 3, 15, 30, 0, 0, time.UTC).Unix(),
+		time.Date(2023, 11, 6, 16, 30, 0, 0, time.UTC).Unix(),
+		time.Date(2023, 11, 10, 16, 30, 0, 0, time.UTC).Unix(),
+	}, departureTimes)
+
+	flight := NewFlight(schedule, departureTimes[0])
+	assert.Equal(t, departureTimes[0]+9000, flight.ArrivalTime)
+	assert.Equal(t, int32(120), flight.TotalAvailableSeats)
+
+	// rescheduling keeps the local date of the departure
+	schedule.LocalDepartureTime = "23:45"
+	departureTime, err := Reschedule(departureTimes[1], schedule)
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2023, 11, 7, 7, 45, 0, 0, time.UTC).Unix(), departureTime)
+}
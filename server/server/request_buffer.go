@@ -6,9 +6,11 @@ import (
 	"sync"
 	"time"
 
+	"github.com/cyiafn/flight_information_system/server/custom_errors"
 	"github.com/cyiafn/flight_information_system/server/dto"
 	"github.com/cyiafn/flight_information_system/server/logs"
 	"github.com/cyiafn/flight_information_system/server/utils/bytes"
+	"github.com/cyiafn/flight_information_system/server/utils/rpc"
 )
 
 /*
@@ -22,6 +24,8 @@ This fully supports concurrent requests from a single client.
 const (
 	// cleanUpDuration is the timing out of a request
 	cleanUpDuration = 5 * time.Second
+	// maxByteArrayBuffers caps the byte arrays a request can be split into, so a malformed header cannot allocate more
+	maxByteArrayBuffers = 128
 )
 
 // newRequestBuffer instantiates a new requestBuffer
@@ -39,34 +43,38 @@ type requestBuffer struct {
 	Buffer map[string]*request
 }
 
-// ProcessRequest checks if all the byte arrays for a request have arrived, if not, it will not release the request for processing
-func (r *requestBuffer) ProcessRequest(ctx context.Context, payload []byte) (*request, bool) {
+// ProcessRequest checks if all the byte arrays for a request have arrived, if not, it will not release the request for
+// processing. Returns a MarshallerError if the byte array numbers in the header are out of range or the total does not
+// match the earlier byte arrays of the request, in which case the request is dropped.
+func (r *requestBuffer) ProcessRequest(ctx context.Context, payload []byte) (*request, bool, error) {
+	number, total, err := getByteBufferArrayNumbers(payload)
+	if err != nil {
+		return nil, false, custom_errors.NewMarshallerError(err)
+	}
 	// generates the key for the buffer key
 	key := makeBufferKey(GetIPAddr(ctx), string(getRequestID(payload)))
-	r.RLock()
+
+	r.Lock()
+	defer r.Unlock()
 	request, ok := r.Buffer[key]
-	if ok {
-		// if there is such a request, we can simply use the byte array number in the request
-		request.Body[bytes.ToInt64(getCurrentByteBufferArrayNumber(payload))] = getRequestBody(payload)
-		r.RUnlock()
-	} else {
-		r.RUnlock()
-		r.Lock()
-		// creates a new request with that payload
-		r.Buffer[key] = newRequest(ctx, payload)
-		r.Unlock()
+	if !ok {
+		// creates a new request for the first byte array to arrive, which may not be the first one sent
+		request = newRequest(ctx, payload, total)
+		r.Buffer[key] = request
 	}
+	if request.TotalByteArrayBuffer != total {
+		delete(r.Buffer, key)
+		return nil, false, custom_errors.NewMarshallerError(fmt.Errorf("byte array %d of %d does not match the total of %d of the earlier byte arrays", number, total, request.TotalByteArrayBuffer))
+	}
+	// byte array numbers start from 1
+	request.Body[number-1] = getRequestBody(payload)
 
-	if r.Buffer[key].IsComplete() {
-		r.Lock()
-		defer func() {
-			delete(r.Buffer, key)
-			r.Unlock()
-		}()
-		// if all the byteBufferArrays are here, return the request for processing
-		return r.Buffer[key], true
+	if !request.IsComplete() {
+		return nil, false, nil
 	}
-	return nil, false
+	// if all the byteBufferArrays are here, return the request for processing
+	delete(r.Buffer, key)
+	return request, true, nil
 }
 
 // StartCleanUp ticks every 2 seconds to clean up timed out requests
@@ -97,20 +105,32 @@ func (r *requestBuffer) StartCleanUp() {
 	}()
 }
 
-// newRequest creates a new request
-func newRequest(ctx context.Context, payload []byte) *request {
-	currentByteArrayBuffer := bytes.ToInt64(getCurrentByteBufferArrayNumber(payload))
-	totalByteArrayBuffer := bytes.ToInt64(getTotalByteBufferArrayNumber(payload))
-	req := &request{
+// newRequest creates a new request of total byte arrays
+func newRequest(ctx context.Context, payload []byte, total int64) *request {
+	return &request{
 		IPAddr:               GetIPAddr(ctx),
 		RequestID:            string(getRequestID(payload)),
 		Type:                 getRequestType(payload),
+		SchemaVersion:        getSchemaVersion(payload),
 		TimeCreated:          time.Now(),
-		TotalByteArrayBuffer: totalByteArrayBuffer,
-		Body:                 make([][]byte, totalByteArrayBuffer),
+		TotalByteArrayBuffer: total,
+		Body:                 make([][]byte, total),
+	}
+}
+
+// getByteBufferArrayNumbers gets the byte array number and total no. of byte arrays in the header of a datagram, which
+// come from the network so they are checked before they are used to allocate or index the body of a request
+func getByteBufferArrayNumbers(payload []byte) (int64, int64, error) {
+	offset := getRequestIDOffset(payload) + shortIDBytesLength
+	number := bytes.ToInt64(getCurrentByteBufferArrayNumber(payload))
+	total := bytes.ToInt64(getTotalByteBufferArrayNumber(payload))
+	if total < 1 || total > maxByteArrayBuffers {
+		return 0, 0, custom_errors.NewDecodeError(offset+currentByteBufferArrayBytesLength, fmt.Sprintf("total byte arrays %d must be from 1 to %d", total, maxByteArrayBuffers))
+	}
+	if number < 1 || number > total {
+		return 0, 0, custom_errors.NewDecodeError(offset, fmt.Sprintf("byte array number %d must be from 1 to %d", number, total))
 	}
-	req.Body[currentByteArrayBuffer-1] = getRequestBody(payload)
-	return req
+	return number, total, nil
 }
 
 // request represents a request
@@ -118,6 +138,8 @@ type request struct {
 	IPAddr    string
 	RequestID string
 	Type      dto.RequestType
+	// SchemaVersion is the schema version the request was sent in, and which it is replied in
+	SchemaVersion rpc.SchemaVersion
 
 	TimeCreated          time.Time
 	TotalByteArrayBuffer int64
@@ -126,7 +148,7 @@ type request struct {
 
 // TimedOut checks if a request is timed out or not
 func (r *request) TimedOut() bool {
-	return r.TimeCreated.Add(cleanUpDuration).After(time.Now())
+	return time.Now().After(r.TimeCreated.Add(cleanUpDuration))
 }
 
 // IsComplete checks if all the byte arrays are here
@@ -0,0 +1,61 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cyiafn/flight_information_system/server/custom_errors"
+	"github.com/cyiafn/flight_information_system/server/dto"
+	"github.com/cyiafn/flight_information_system/server/utils/rpc"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestDatagram makes a request datagram of byte array number of total, numbers start from 1. Requests have the same
+// headers as responses.
+func newTestDatagram(requestID string, number int64, total int64, body string) []byte {
+	return addHeaders(dto.ResponseType(dto.PingRequestType), rpc.TaggedSchemaVersion, []byte(requestID), number-1, total, []byte(body))
+}
+
+func TestRequestBufferProcessRequest(t *testing.T) {
+	ctx := context.WithValue(context.Background(), "addr", "127.0.0.1:5000")
+	buffer := newRequestBuffer()
+
+	// byte arrays can arrive in any order
+	req, complete, err := buffer.ProcessRequest(ctx, newTestDatagram("abcdefghi", 2, 2, "world"))
+	assert.Nil(t, err)
+	assert.False(t, complete)
+	assert.Nil(t, req)
+	req, complete, err = buffer.ProcessRequest(ctx, newTestDatagram("abcdefghi", 1, 2, "hello "))
+	assert.Nil(t, err)
+	assert.True(t, complete)
+	_, body := req.CompileRequest()
+	assert.Equal(t, "hello world", string(body))
+	assert.Len(t, buffer.Buffer, 0)
+}
+
+func TestRequestBufferMalformedHeaders(t *testing.T) {
+	ctx := context.WithValue(context.Background(), "addr", "127.0.0.1:5000")
+	buffer := newRequestBuffer()
+
+	for name, datagram := range map[string][]byte{
+		"number 0":               newTestDatagram("abcdefghi", 0, 2, "a"),
+		"number more than total": newTestDatagram("abcdefghi", 3, 2, "a"),
+		"negative number":        newTestDatagram("abcdefghi", -1, 2, "a"),
+		"total 0":                newTestDatagram("abcdefghi", 1, 0, "a"),
+		"total more than max":    newTestDatagram("abcdefghi", 1, maxByteArrayBuffers+1, "a"),
+		"negative total":         newTestDatagram("abcdefghi", 1, -1, "a"),
+	} {
+		_, complete, err := buffer.ProcessRequest(ctx, datagram)
+		assert.IsType(t, &custom_errors.MarshallerError{}, err, name)
+		assert.False(t, complete, name)
+	}
+	assert.Len(t, buffer.Buffer, 0)
+
+	// a later byte array of the request with another total drops the request
+	_, _, err := buffer.ProcessRequest(ctx, newTestDatagram("abcdefghi", 1, 3, "a"))
+	assert.Nil(t, err)
+	_, complete, err := buffer.ProcessRequest(ctx, newTestDatagram("abcdefghi", 2, 2, "b"))
+	assert.IsType(t, &custom_errors.MarshallerError{}, err)
+	assert.False(t, complete)
+	assert.Len(t, buffer.Buffer, 0)
+}
@@ -23,6 +23,16 @@ const (
 	atLeastOnceServerMode
 )
 
+// CallbackSenderType is how callbacks are sent to subscribers
+type CallbackSenderType int
+
+const (
+	// ListenerCallbackSender sends callbacks from the listener's socket, so they come from the server's well known port
+	ListenerCallbackSender CallbackSenderType = iota + 1
+	// DialCallbackSender dials a new socket for every callback, so they come from an ephemeral port
+	DialCallbackSender
+)
+
 const (
 	// defaultUDPPort if env var is not set
 	defaultUDPPort = 8080
@@ -31,6 +41,8 @@ const (
 
 	// requestType length in bytes
 	requestTypeBytesLength = 1
+	// schemaVersion length in bytes, only in headers of datagrams after PositionalSchemaVersion
+	schemaVersionBytesLength = 1
 	// requestID length in bytes
 	shortIDBytesLength = 9
 	// currentByteBufferArrayBytesLength no in bytes
@@ -39,6 +51,9 @@ const (
 	totalByteBufferArrayByteLength = 8
 )
 
+// schemaVersionKey is the context key of the schema version of the request being handled
+const schemaVersionKey = "schemaVersion"
+
 // single instance of server. This will be "singleton"
 var instance *server
 
@@ -54,32 +69,51 @@ type server struct {
 	DuplicateRequestFilter *duplicate_request.Filter
 	// RequestBuffer is the request buffer for timing out requests, processing multiple byteArrayBuffers and allowing for concurrent server access
 	RequestBuffer *requestBuffer
+	// CallbackSender sends callbacks to subscribers
+	CallbackSender net.Sender
 }
 
 // Boot initialises the server instance boots up the server
-func Boot(routes map[dto.RequestType]func(ctx context.Context, request any) (any, error), atMostOnceEnabled bool) {
-	instance = &server{
+func Boot(routes map[dto.RequestType]func(ctx context.Context, request any) (any, error), atMostOnceEnabled bool, callbackSender CallbackSenderType) {
+	instance = newServer(routes, atMostOnceEnabled)
+
+	// take note here, that the servers route request function is passed ito the UDPListener such that all byteArrayBuffers will be received by the server, processed, routed, executed,
+	// before the data is passed back the UDPListener to send back
+	instance.UDPListener = net.NewUDPListener(getUDPPort(), instance.RouteRequest)
+	instance.CallbackSender = utils.TernaryOperator[net.Sender](callbackSender == DialCallbackSender, net.DialSender{}, instance.UDPListener.Sender())
+	instance.UDPListener.StartListening()
+
+	// we sleep for 1 second on termination here as StartListening blocks and is the main thread. Upon interception of SIGINT or SIGKILL we need some grace period for all dependencies to close gracefully
+	time.Sleep(1 * time.Second) // grace time period so that closing listeners complete
+}
+
+// newServer instantiates a server with all dependencies but the listener and callback sender
+func newServer(routes map[dto.RequestType]func(ctx context.Context, request any) (any, error), atMostOnceEnabled bool) *server {
+	s := &server{
 		Routes: routes,
 		Mode:   utils.TernaryOperator(atMostOnceEnabled, atMostOnceServerMode, atLeastOnceServerMode),
 	}
 
 	// If at most once is enabled, we need the duplicate request filter to prevent duplicate requests from running multiple times
 	if atMostOnceEnabled {
-		instance.Mode = atMostOnceServerMode
-		instance.DuplicateRequestFilter = duplicate_request.NewFilter()
-	} else {
-		instance.Mode = atLeastOnceServerMode
+		s.DuplicateRequestFilter = duplicate_request.NewFilter()
 	}
 
 	// instantiating all dependencies
-	instance.RequestBuffer = newRequestBuffer()
-	// take note here, that the servers route request function is passed ito the UDPListener such that all byteArrayBuffers will be received by the server, processed, routed, executed,
-	// before the data is passed back the UDPListener to send back
-	instance.UDPListener = net.NewUDPListener(getUDPPort(), instance.RouteRequest)
-	instance.UDPListener.StartListening()
+	s.RequestBuffer = newRequestBuffer()
+	return s
+}
 
-	// we sleep for 1 second on termination here as StartListening blocks and is the main thread. Upon interception of SIGINT or SIGKILL we need some grace period for all dependencies to close gracefully
-	time.Sleep(1 * time.Second) // grace time period so that closing listeners complete
+// NewRequestRouter instantiates a server that does not listen and returns its RouteRequest, so that requests received
+// on another socket (e.g. in tests of the clients) are handled exactly as the booted server handles them. The function
+// returned along with it closes its dependencies.
+func NewRequestRouter(routes map[dto.RequestType]func(ctx context.Context, request any) (any, error), atMostOnceEnabled bool) (func(ctx context.Context, request []byte) ([][]byte, bool), func()) {
+	s := newServer(routes, atMostOnceEnabled)
+	return s.RouteRequest, func() {
+		if s.Mode == atMostOnceServerMode {
+			s.DuplicateRequestFilter.Close()
+		}
+	}
 }
 
 // SpinDown kills the instance and closes their deps.
@@ -96,8 +130,27 @@ func SpinDown() {
 
 // RouteRequest is the callback function passed into the UDPListener to intercept all received data and process it accordingly
 func (s *server) RouteRequest(ctx context.Context, request []byte) ([][]byte, bool) {
+	// a datagram too short for a header cannot be replied to, as it has no requestID
+	if len(request) <= requestTypeBytesLength || len(request) < getTotalBytesInHeader(getSchemaVersion(request)) {
+		logs.Warn("[%s] datagram of %d bytes is too short for a header, ignoring request", GetIPAddr(ctx), len(request))
+		return nil, false
+	}
+	// we cannot unmarshal, or reply in, a schema version newer than ours
+	if version := getSchemaVersion(request); version > rpc.CurrentSchemaVersion {
+		logs.Warn("[%s] unknown schema version: %v, ignoring request", GetIPAddr(ctx), version)
+		return nil, false
+	}
+
 	// Sends the request to the request buffer to check if all byteArrayBuffers have arrived or not and whether we should process this right now.
-	req, complete := s.RequestBuffer.ProcessRequest(ctx, request)
+	req, complete, err := s.RequestBuffer.ProcessRequest(ctx, request)
+	if err != nil {
+		// the request cannot be put together, so it is replied to with the error without being executed
+		logs.Warn("[%s] Unable to buffer request ID: %s, err: %v", GetIPAddr(ctx), string(getRequestID(request)), err)
+		if dto.IsOneWay(getRequestType(request)) {
+			return nil, false
+		}
+		return s.marshalResponse(ctx, getRequestType(request), getSchemaVersion(request), getRequestID(request), nil, err), true
+	}
 	if !complete {
 		// if not all byte arrays have arrived, we do not process it
 		logs.Info("Request is not complete, waiting for all byte arrays: %s", utils.DumpJSON(req))
@@ -114,20 +167,23 @@ func (s *server) RouteRequest(ctx context.Context, request []byte) ([][]byte, bo
 
 	// We take the request object and compile it into the necessary information
 	requestType, requestBody := req.CompileRequest()
+	// we reply in the schema version of the request, so handlers that send later (e.g. callbacks) need it too
+	ctx = context.WithValue(ctx, schemaVersionKey, req.SchemaVersion)
 
 	// we generate the requestDTO object based on the requestType
 	requestDTO := dto.NewRequestDTO(requestType)
 	if requestDTO != nil {
-		// unmarshal the request body into the DTO
-		err := rpc.Unmarshal(requestBody, requestDTO)
-		if err != nil {
-			logs.Error("Unable to marshal request, err: %v", err)
-			return nil, false
+		// unmarshal the request body into the DTO, a request that cannot be unmarshalled (e.g. a truncated one) is replied
+		// to with a MarshallerError instead of being executed
+		if err = rpc.UnmarshalSchema(requestBody, requestDTO, req.SchemaVersion); err != nil {
+			logs.Warn("[%s] Unable to unmarshal request type: %v, err: %v", GetIPAddr(ctx), requestType, err)
+			err = custom_errors.NewMarshallerError(err)
 		}
 	}
-	logs.Info("[%s] Received Request Type: %v, Request ID: %s, Request No: %v, Total Byte Array Buffers for Request %v, Marshalled Request: %s",
+	logs.Info("[%s] Received Request Type: %v, Schema Version: %v, Request ID: %s, Request No: %v, Total Byte Array Buffers for Request %v, Marshalled Request: %s",
 		GetIPAddr(ctx),
 		requestType,
+		req.SchemaVersion,
 		string(getRequestID(request)),
 		bytes.ToInt64(getCurrentByteBufferArrayNumber(request)),
 		bytes.ToInt64(getTotalByteBufferArrayNumber(request)),
@@ -142,31 +198,55 @@ func (s *server) RouteRequest(ctx context.Context, request []byte) ([][]byte, bo
 	}
 
 	// we execute the RPC call with the proper handler/biz logic
-	response, err := handler(ctx, requestDTO)
+	var response any
+	if err == nil {
+		response, err = handler(ctx, requestDTO)
+	}
 
+	// one way requests such as acks are not replied to
+	if dto.IsOneWay(requestType) {
+		if err != nil {
+			logs.Warn("[%s] one way request type: %v failed, err: %v", GetIPAddr(ctx), requestType, err)
+		}
+		return nil, false
+	}
+
+	res := s.marshalResponse(ctx, requestType, req.SchemaVersion, []byte(req.RequestID), response, err)
+
+	if s.Mode == atMostOnceServerMode {
+		s.DuplicateRequestFilter.RegisterResponse(req.RequestID, res)
+	}
+
+	// returns the response data to the user to the UDPListener to send back
+	return res, true
+}
+
+// marshalResponse wraps the response, or the error, of a request in the response DTO and splits it into byte arrays to
+// send back in the schema version of the request
+func (s *server) marshalResponse(ctx context.Context, requestType dto.RequestType, version rpc.SchemaVersion, requestID []byte, response any, err error) [][]byte {
 	// we wrap the response in the response DTO wrapper such that we can properly send proper error messages to the user
 	wrappedResp := &dto.Response{
 		StatusCode: status_code.GetStatusCode(err),
 		Data:       response,
 	}
+	// some errors carry details for the user, e.g. which seats are unavailable
+	if detailedErr, ok := err.(custom_errors.DetailedError); ok {
+		wrappedResp.Data = detailedErr.Details()
+	}
 
 	// we marshal the wrapped response
-	resp, err := rpc.Marshal(wrappedResp)
+	resp, err := rpc.MarshalSchema(wrappedResp, version)
 	if err != nil {
 		logs.Warn("error when marshalling, err: %v", err)
 		// we throw a generic marshaller error if we can't marshal for some reason
-		resp, _ = rpc.Marshal(&dto.Response{
+		resp, _ = rpc.MarshalSchema(&dto.Response{
 			StatusCode: status_code.GetStatusCode(custom_errors.NewMarshallerError(err)),
 			Data:       nil,
-		})
+		}, version)
 	}
 
 	// our payload might be more than 512 bytes, so we might need to split it into multiple byte arrays. This will not happen in this presentation but the functionality is there
-	res := s.splitPayloadForSending(requestType, []byte(req.RequestID), resp)
-
-	if s.Mode == atMostOnceServerMode {
-		s.DuplicateRequestFilter.RegisterResponse(req.RequestID, res)
-	}
+	res := s.splitPayloadForSending(requestType, version, requestID, resp)
 
 	for i, payload := range res {
 		logs.Info("[%s] Response Payload #%v out of %v: Request Type: %v, Request ID: %s, Request No: %v, Total Byte Arrays for Request %v, Marshalled Request: %s",
@@ -180,62 +260,81 @@ func (s *server) RouteRequest(ctx context.Context, request []byte) ([][]byte, bo
 			utils.DumpJSON(wrappedResp),
 		)
 	}
-
-	// returns the response data to the user to the UDPListener to send back
-	return res, true
+	return res
 }
 
 // splitPayloadForSending splits the payload into multiple byte array buffers to send
-func (s *server) splitPayloadForSending(requestType dto.RequestType, requestID []byte, payload []byte) [][]byte {
+func (s *server) splitPayloadForSending(requestType dto.RequestType, version rpc.SchemaVersion, requestID []byte, payload []byte) [][]byte {
+	return SplitPayload(dto.GetResponseType(requestType), version, requestID, payload)
+}
+
+// SplitPayload splits the payload into multiple byte array buffers with the headers of the responseType, schema version
+// and requestID, so that payloads of any size can be sent and reassembled by the byte array number. Callbacks are split
+// the same way.
+func SplitPayload(responseType dto.ResponseType, version rpc.SchemaVersion, requestID []byte, payload []byte) [][]byte {
 	// if the payload length == 0 we can hardcode this
 	if len(payload) == 0 {
 		output := make([][]byte, 1)
 		output[0] = make([]byte, 0)
-		output[0] = s.addHeaders(requestType, requestID, 1, 1, output[0])
+		output[0] = addHeaders(responseType, version, requestID, 1, 1, output[0])
 		return output
 	}
+	headerSize := getTotalBytesInHeader(version)
 	output := make([][]byte, 0)
 	// we split it up into array of byte arrays
-	for i := 0; i < len(payload); i += net.DefaultByteBufferSize - s.getTotalBytesInHeader() {
-		mxSize := utils.TernaryOperator(len(payload) < i+net.DefaultByteBufferSize-s.getTotalBytesInHeader(), len(payload), i+net.DefaultByteBufferSize-s.getTotalBytesInHeader())
+	for i := 0; i < len(payload); i += net.DefaultByteBufferSize - headerSize {
+		mxSize := utils.TernaryOperator(len(payload) < i+net.DefaultByteBufferSize-headerSize, len(payload), i+net.DefaultByteBufferSize-headerSize)
 		output = append(output, payload[i:mxSize])
 	}
 
 	for i := range output {
 		// we add headers for each byte array
-		output[i] = s.addHeaders(requestType, requestID, int64(i), int64(len(output)), output[i])
+		output[i] = addHeaders(responseType, version, requestID, int64(i), int64(len(output)), output[i])
 	}
 
 	return output
 }
 
-// getTotalBytesInHeader returns header size
-func (s *server) getTotalBytesInHeader() int {
-	return requestTypeBytesLength + shortIDBytesLength + currentByteBufferArrayBytesLength + totalByteBufferArrayByteLength
+// getTotalBytesInHeader returns header size of the schema version
+func getTotalBytesInHeader(version rpc.SchemaVersion) int {
+	return requestTypeBytesLength + getSchemaVersionBytesLength(version) + shortIDBytesLength + currentByteBufferArrayBytesLength + totalByteBufferArrayByteLength
+}
+
+// getSchemaVersionBytesLength returns the length of the schema version in the header, PositionalSchemaVersion headers do not have one
+func getSchemaVersionBytesLength(version rpc.SchemaVersion) int {
+	return utils.TernaryOperator(version == rpc.PositionalSchemaVersion, 0, schemaVersionBytesLength)
 }
 
 // addHeaders adds headers to a payload
-func (s *server) addHeaders(requestType dto.RequestType, requestID []byte, byteArrayBufferNo int64, totalByteArrayBuffer int64, response []byte) []byte {
-	response = s.addTotalByteArraysToHeader(response, totalByteArrayBuffer)
-	response = s.addByteArrayBufferNoToHeader(response, byteArrayBufferNo)
-	response = s.addRequestIDToHeader(response, requestID)
-	response = s.addResponseTypeHeader(response, dto.GetResponseType(requestType))
+func addHeaders(responseType dto.ResponseType, version rpc.SchemaVersion, requestID []byte, byteArrayBufferNo int64, totalByteArrayBuffer int64, response []byte) []byte {
+	response = addTotalByteArraysToHeader(response, totalByteArrayBuffer)
+	response = addByteArrayBufferNoToHeader(response, byteArrayBufferNo)
+	response = addRequestIDToHeader(response, requestID)
+	response = addSchemaVersionToHeader(response, version)
+	response = addResponseTypeHeader(response, responseType)
 	return response
 }
 
-func (s *server) addByteArrayBufferNoToHeader(response []byte, byteArrayBufferNo int64) []byte {
+func addByteArrayBufferNoToHeader(response []byte, byteArrayBufferNo int64) []byte {
 	return append(bytes.Int64ToBytes(byteArrayBufferNo+1), response...)
 }
 
-func (s *server) addTotalByteArraysToHeader(response []byte, totalByteArrayBuffers int64) []byte {
+func addTotalByteArraysToHeader(response []byte, totalByteArrayBuffers int64) []byte {
 	return append(bytes.Int64ToBytes(totalByteArrayBuffers), response...)
 }
 
-func (s *server) addRequestIDToHeader(response []byte, requestID []byte) []byte {
+func addRequestIDToHeader(response []byte, requestID []byte) []byte {
 	return append(requestID, response...)
 }
 
-func (s *server) addResponseTypeHeader(response []byte, responseType dto.ResponseType) []byte {
+func addSchemaVersionToHeader(response []byte, version rpc.SchemaVersion) []byte {
+	if version == rpc.PositionalSchemaVersion {
+		return response
+	}
+	return append([]byte{uint8(version)}, response...)
+}
+
+func addResponseTypeHeader(response []byte, responseType dto.ResponseType) []byte {
 	return append([]byte{uint8(responseType)}, response...)
 }
 
@@ -252,24 +351,58 @@ func getRequestType(request []byte) dto.RequestType {
 	return dto.RequestType(request[0])
 }
 
+// getSchemaVersion gets the schema version of the datagram, datagrams without a schema version byte are PositionalSchemaVersion
+func getSchemaVersion(request []byte) rpc.SchemaVersion {
+	if !rpc.IsSchemaVersionByte(request[requestTypeBytesLength]) {
+		return rpc.PositionalSchemaVersion
+	}
+	return rpc.SchemaVersion(request[requestTypeBytesLength])
+}
+
+// getRequestIDOffset gets where the requestID starts, after the request type and schema version (if any)
+func getRequestIDOffset(request []byte) int {
+	return requestTypeBytesLength + getSchemaVersionBytesLength(getSchemaVersion(request))
+}
+
 func getRequestID(request []byte) []byte {
+	offset := getRequestIDOffset(request)
 	dest := make([]byte, shortIDBytesLength)
-	copy(dest, request[requestTypeBytesLength:requestTypeBytesLength+shortIDBytesLength])
+	copy(dest, request[offset:offset+shortIDBytesLength])
 	return dest
 }
 
 func getCurrentByteBufferArrayNumber(request []byte) []byte {
-	return request[requestTypeBytesLength+shortIDBytesLength : requestTypeBytesLength+shortIDBytesLength+currentByteBufferArrayBytesLength]
+	offset := getRequestIDOffset(request) + shortIDBytesLength
+	return request[offset : offset+currentByteBufferArrayBytesLength]
 }
 
 func getTotalByteBufferArrayNumber(request []byte) []byte {
-	return request[requestTypeBytesLength+shortIDBytesLength+currentByteBufferArrayBytesLength : requestTypeBytesLength+shortIDBytesLength+currentByteBufferArrayBytesLength+totalByteBufferArrayByteLength]
+	offset := getRequestIDOffset(request) + shortIDBytesLength + currentByteBufferArrayBytesLength
+	return request[offset : offset+totalByteBufferArrayByteLength]
 }
 
 func getRequestBody(request []byte) []byte {
-	return request[requestTypeBytesLength+shortIDBytesLength+totalByteBufferArrayByteLength+currentByteBufferArrayBytesLength:]
+	return request[getRequestIDOffset(request)+shortIDBytesLength+currentByteBufferArrayBytesLength+totalByteBufferArrayByteLength:]
 }
 
 func GetIPAddr(ctx context.Context) string {
 	return ctx.Value("addr").(string)
 }
+
+// GetSchemaVersion gets the schema version of the request being handled, which the requester expects replies and
+// callbacks in. Defaults to PositionalSchemaVersion if not set.
+func GetSchemaVersion(ctx context.Context) rpc.SchemaVersion {
+	version, ok := ctx.Value(schemaVersionKey).(rpc.SchemaVersion)
+	if !ok {
+		return rpc.PositionalSchemaVersion
+	}
+	return version
+}
+
+// GetCallbackSender gets the sender for callbacks to subscribers, callbacks are sent by dialing if the server has not booted
+func GetCallbackSender() net.Sender {
+	if instance == nil || instance.CallbackSender == nil {
+		return net.DialSender{}
+	}
+	return instance.CallbackSender
+}
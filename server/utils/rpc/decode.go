@@ -0,0 +1,64 @@
+package rpc
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cyiafn/flight_information_system/server/custom_errors"
+	"github.com/cyiafn/flight_information_system/server/utils/bytes"
+)
+
+/**
+Payloads come from the network, so every read of a payload is bounds-checked and a payload that is truncated or malformed
+returns a *custom_errors.DecodeError instead of panicking. Decode errors are made where the read fails, with the offset
+from the start of the bytes being read, and each value it is nested in adds its field to the path (and the offset of its
+bytes for TaggedSchemaVersion) as the error is returned.
+*/
+
+// checkRemaining checks that there are size bytes left in the request from ptr
+func checkRemaining(request []byte, ptr int, size int) error {
+	if size > len(request)-ptr {
+		return custom_errors.NewDecodeError(ptr, fmt.Sprintf("expected %d bytes, %d left", size, len(request)-ptr))
+	}
+	return nil
+}
+
+// readLength reads the int64 no. of elements of a slice or map at ptr. It must fit in the rest of the request, where
+// every element takes up at least elementSize bytes, which bounds the memory allocated for a malformed length.
+func readLength(request []byte, ptr int, elementSize int) (int, error) {
+	if err := checkRemaining(request, ptr, int64Size); err != nil {
+		return 0, err
+	}
+	length := bytes.ToInt64(request[ptr : ptr+int64Size])
+	if elementSize < 1 {
+		elementSize = 1
+	}
+	if length < 0 || length > int64((len(request)-ptr-int64Size)/elementSize) {
+		return 0, custom_errors.NewDecodeError(ptr, fmt.Sprintf("length %d does not fit in the %d bytes left", length, len(request)-ptr-int64Size))
+	}
+	return int(length), nil
+}
+
+// wrapDecodeError adds the field the error occurred in to the front of the field path of a decode error, such as a field
+// name or [index] of an element, and offset to its offset. Other errors are returned as they are.
+func wrapDecodeError(err error, field string, offset int) error {
+	decodeErr, ok := err.(*custom_errors.DecodeError)
+	if !ok {
+		return err
+	}
+	switch {
+	case decodeErr.FieldPath == "":
+		decodeErr.FieldPath = field
+	case field == "" || strings.HasPrefix(decodeErr.FieldPath, "["):
+		decodeErr.FieldPath = field + decodeErr.FieldPath
+	default:
+		decodeErr.FieldPath = field + "." + decodeErr.FieldPath
+	}
+	decodeErr.Offset += offset
+	return decodeErr
+}
+
+// getElementPath is the field of an element of a slice or map in a field path
+func getElementPath(i int) string {
+	return fmt.Sprintf("[%d]", i)
+}
@@ -0,0 +1,130 @@
+package rpc
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cyiafn/flight_information_system/server/custom_errors"
+	"github.com/cyiafn/flight_information_system/server/utils/bytes"
+	"github.com/stretchr/testify/assert"
+)
+
+type decodeTestItem struct {
+	ID    int32
+	Label string
+}
+
+type decodeTestStruct struct {
+	Name  string
+	Items []decodeTestItem
+}
+
+// decodeTestStruct with a Label of another wire type
+type otherDecodeTestStruct struct {
+	Name  string
+	Items []struct {
+		ID    int32
+		Label int32
+	}
+}
+
+func newDecodeTestStruct() *decodeTestStruct {
+	return &decodeTestStruct{Name: "a", Items: []decodeTestItem{{ID: 1, Label: "x"}, {ID: 2, Label: "y"}}}
+}
+
+func TestUnmarshalTruncated(t *testing.T) {
+	for _, v := range newTestDTOs() {
+		for _, version := range []SchemaVersion{PositionalSchemaVersion, TaggedSchemaVersion} {
+			payload, err := MarshalSchema(v, version)
+			assert.Nil(t, err)
+			for i := 0; i < len(payload); i++ {
+				err := UnmarshalSchema(payload[:i], reflect.New(reflect.TypeOf(v).Elem()).Interface(), version)
+				// a tagged payload cut between fields is still valid, every field is needed in a positional one
+				if version == PositionalSchemaVersion || err != nil {
+					assert.IsType(t, &custom_errors.DecodeError{}, err, "%T truncated to %d bytes", v, i)
+				}
+			}
+		}
+	}
+}
+
+func TestDecodeErrorFieldPath(t *testing.T) {
+	payload, err := Marshal(newDecodeTestStruct())
+	assert.Nil(t, err)
+	// cut off the string terminator of the last label
+	err = Unmarshal(payload[:len(payload)-1], &decodeTestStruct{})
+	assert.Equal(t, &custom_errors.DecodeError{FieldPath: "Items[1].Label", Offset: 20, Reason: "string is not terminated"}, err)
+
+	payload, err = MarshalTagged(newDecodeTestStruct())
+	assert.Nil(t, err)
+	err = UnmarshalTagged(payload, &otherDecodeTestStruct{})
+	assert.IsType(t, &custom_errors.DecodeError{}, err)
+	assert.Equal(t, "Items[0].Label", err.(*custom_errors.DecodeError).FieldPath)
+	assert.Equal(t, 13, err.(*custom_errors.DecodeError).Offset)
+}
+
+func TestUnmarshalMalformedLength(t *testing.T) {
+	// a slice of 2^40 elements in a payload of a few bytes
+	payload := append([]byte("a\000"), bytes.Int64ToBytes(1<<40)...)
+	err := Unmarshal(payload, &decodeTestStruct{})
+	assert.Equal(t, &custom_errors.DecodeError{FieldPath: "Items", Offset: 2, Reason: "length 1099511627776 does not fit in the 0 bytes left"}, err)
+
+	payload = append([]byte("a\000"), bytes.Int64ToBytes(-1)...)
+	err = Unmarshal(payload, &decodeTestStruct{})
+	assert.IsType(t, &custom_errors.DecodeError{}, err)
+}
+
+func TestUnmarshalEmptyStrings(t *testing.T) {
+	v := &struct{ Strings []string }{Strings: []string{"", "a", ""}}
+	payload, err := Marshal(v)
+	assert.Nil(t, err)
+
+	newValue := &struct{ Strings []string }{}
+	err = Unmarshal(payload, newValue)
+	assert.Nil(t, err)
+	assert.Equal(t, v, newValue)
+}
+
+// FuzzUnmarshal unmarshals random payloads into every DTO in every schema version, which must either fail with a
+// DecodeError or unmarshal to a value that is marshalled and unmarshalled to the same value again
+func FuzzUnmarshal(f *testing.F) {
+	dtos := newTestDTOs()
+	for i, v := range dtos {
+		for _, version := range []SchemaVersion{PositionalSchemaVersion, TaggedSchemaVersion} {
+			payload, err := MarshalSchema(v, version)
+			if err != nil {
+				f.Fatal(err)
+			}
+			f.Add(uint8(i), uint8(version), payload)
+		}
+	}
+
+	f.Fuzz(func(t *testing.T, dtoIndex uint8, version uint8, request []byte) {
+		reflectType := reflect.TypeOf(dtos[int(dtoIndex)%len(dtos)]).Elem()
+		schemaVersion := SchemaVersion(version%uint8(CurrentSchemaVersion) + 1)
+
+		v := reflect.New(reflectType).Interface()
+		if err := UnmarshalSchema(request, v, schemaVersion); err != nil {
+			if _, ok := err.(*custom_errors.DecodeError); !ok {
+				t.Fatalf("expected a DecodeError, got %v", err)
+			}
+			return
+		}
+
+		// values such as bools and padding are not always unmarshalled from the bytes they are marshalled to, so the
+		// value unmarshalled is compared after it is marshalled again
+		payload, err := MarshalSchema(v, schemaVersion)
+		if err != nil {
+			t.Fatalf("unable to marshal %v, err: %v", v, err)
+		}
+		newValue := reflect.New(reflectType).Interface()
+		if err := UnmarshalSchema(payload, newValue, schemaVersion); err != nil {
+			t.Fatalf("unable to unmarshal %v, err: %v", payload, err)
+		}
+		again, err := MarshalSchema(newValue, schemaVersion)
+		if err != nil {
+			t.Fatalf("unable to marshal %v, err: %v", newValue, err)
+		}
+		assert.Equal(t, payload, again)
+	})
+}
@@ -0,0 +1,372 @@
+package rpc
+
+import (
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/cyiafn/flight_information_system/server/custom_errors"
+	"github.com/cyiafn/flight_information_system/server/logs"
+	"github.com/pkg/errors"
+)
+
+/**
+Marshal and Unmarshal compile a plan for each type the first time it is marshalled or unmarshalled, which is the codec of
+the type: how to append a value of it to a payload and how to read one from a payload. The codec of a structure is the
+codec of each of its fields in order, so the fields of a type are only looked up with reflection once instead of for every
+request. Plans are cached in codecs by reflect.Type.
+*/
+
+// codec marshals and unmarshals values of a single type in PositionalSchemaVersion
+type codec struct {
+	// marshal appends the value to the end of the payload
+	marshal func(response []byte, value reflect.Value) ([]byte, error)
+	// unmarshal sets value to the value at ptr of the payload and returns the index of the byte after it
+	unmarshal func(request []byte, value reflect.Value, ptr int) (int, error)
+	// size is the least number of bytes a value takes up, so that a payload is allocated once in most cases
+	size int
+}
+
+// fieldCodec is the codec of a field of a structure
+type fieldCodec struct {
+	index int
+	name  string
+	*codec
+}
+
+// codecs caches the *codec of each reflect.Type compiled. This is CONCURRENT-SAFE.
+var codecs sync.Map
+
+// getCodec gets the codec of the type, compiling it if it is the first time the type is seen
+func getCodec(reflectType reflect.Type) (*codec, error) {
+	if c, ok := codecs.Load(reflectType); ok {
+		return c.(*codec), nil
+	}
+	c, err := compileCodec(reflectType, make(map[reflect.Type]*codec))
+	if err != nil {
+		return nil, err
+	}
+	// another goroutine may have compiled the same type at the same time, we use whichever was stored first
+	res, _ := codecs.LoadOrStore(reflectType, c)
+	return res.(*codec), nil
+}
+
+// compileCodec compiles the codec of the type. compiling are the structures being compiled, so that a structure containing
+// a slice of itself refers to its own codec instead of compiling forever.
+func compileCodec(reflectType reflect.Type, compiling map[reflect.Type]*codec) (*codec, error) {
+	if size, ok := fixedSizes[reflectType.Kind()]; ok {
+		return newFixedCodec(size), nil
+	}
+	switch reflectType.Kind() {
+	case reflect.String:
+		return stringCodec, nil
+	case reflect.Interface:
+		// the codec of the value an interface holds is only known when marshalling
+		return &codec{marshal: marshalInterface, unmarshal: unmarshalInterface}, nil
+	case reflect.Slice:
+		return compileSliceCodec(reflectType, compiling)
+	case reflect.Map:
+		return compileMapCodec(reflectType, compiling)
+	case reflect.Ptr:
+		return compilePointerCodec(reflectType, compiling)
+	case reflect.Struct:
+		if reflectType == timeType {
+			return timeCodec, nil
+		}
+		if c, ok := compiling[reflectType]; ok {
+			return c, nil
+		}
+		return compileStructCodec(reflectType, compiling)
+	}
+	logs.Error("unimplemented type: %v", reflectType)
+	return nil, custom_errors.NewMarshallerError(errors.Errorf("unimplemented type, type: %v", reflectType))
+}
+
+// compileStructCodec compiles the codec of every exported field of the structure, which are marshalled one after another
+func compileStructCodec(reflectType reflect.Type, compiling map[reflect.Type]*codec) (*codec, error) {
+	res := &codec{}
+	compiling[reflectType] = res
+
+	fields := make([]fieldCodec, 0, reflectType.NumField())
+	for i := 0; i < reflectType.NumField(); i++ {
+		// unexported fields cannot be set, so they are not marshalled either
+		if !reflectType.Field(i).IsExported() {
+			continue
+		}
+		c, err := compileCodec(reflectType.Field(i).Type, compiling)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, fieldCodec{index: i, name: reflectType.Field(i).Name, codec: c})
+		res.size += c.size
+	}
+
+	res.marshal = func(response []byte, value reflect.Value) ([]byte, error) {
+		var err error
+		for _, field := range fields {
+			if response, err = field.marshal(response, value.Field(field.index)); err != nil {
+				return nil, err
+			}
+		}
+		return response, nil
+	}
+	res.unmarshal = func(request []byte, value reflect.Value, ptr int) (int, error) {
+		var err error
+		for _, field := range fields {
+			if ptr, err = field.unmarshal(request, value.Field(field.index), ptr); err != nil {
+				return 0, wrapDecodeError(err, field.name, 0)
+			}
+		}
+		return ptr, nil
+	}
+	return res, nil
+}
+
+// compileSliceCodec compiles the codec of a slice, which is marshalled as | int64: no. of elements | elements |
+func compileSliceCodec(reflectType reflect.Type, compiling map[reflect.Type]*codec) (*codec, error) {
+	element, err := compileCodec(reflectType.Elem(), compiling)
+	if err != nil {
+		return nil, err
+	}
+	return &codec{
+		marshal: func(response []byte, value reflect.Value) ([]byte, error) {
+			response = binary.LittleEndian.AppendUint64(response, uint64(value.Len()))
+			var err error
+			for i := 0; i < value.Len(); i++ {
+				if response, err = element.marshal(response, value.Index(i)); err != nil {
+					return nil, err
+				}
+			}
+			return response, nil
+		},
+		unmarshal: func(request []byte, value reflect.Value, ptr int) (int, error) {
+			sizeOfSlice, err := readLength(request, ptr, element.size)
+			if err != nil {
+				return 0, err
+			}
+			ptr += int64Size
+
+			slice := reflect.MakeSlice(reflectType, sizeOfSlice, sizeOfSlice)
+			for i := 0; i < sizeOfSlice; i++ {
+				if ptr, err = element.unmarshal(request, slice.Index(i), ptr); err != nil {
+					return 0, wrapDecodeError(err, getElementPath(i), 0)
+				}
+			}
+			value.Set(slice)
+			return ptr, nil
+		},
+		size: int64Size,
+	}, nil
+}
+
+// compileMapCodec compiles the codec of a map, which is marshalled as | int64: no. of entries | key | value | ... |
+func compileMapCodec(reflectType reflect.Type, compiling map[reflect.Type]*codec) (*codec, error) {
+	key, err := compileCodec(reflectType.Key(), compiling)
+	if err != nil {
+		return nil, err
+	}
+	element, err := compileCodec(reflectType.Elem(), compiling)
+	if err != nil {
+		return nil, err
+	}
+	return &codec{
+		marshal: func(response []byte, value reflect.Value) ([]byte, error) {
+			response = binary.LittleEndian.AppendUint64(response, uint64(value.Len()))
+			entries, err := getSortedMapEntries(value, key)
+			if err != nil {
+				return nil, err
+			}
+			for _, entry := range entries {
+				response = append(response, entry.key...)
+				if response, err = element.marshal(response, value.MapIndex(entry.value)); err != nil {
+					return nil, err
+				}
+			}
+			return response, nil
+		},
+		unmarshal: func(request []byte, value reflect.Value, ptr int) (int, error) {
+			sizeOfMap, err := readLength(request, ptr, key.size+element.size)
+			if err != nil {
+				return 0, err
+			}
+			ptr += int64Size
+
+			m := reflect.MakeMapWithSize(reflectType, sizeOfMap)
+			for i := 0; i < sizeOfMap; i++ {
+				k := reflect.New(reflectType.Key()).Elem()
+				if ptr, err = key.unmarshal(request, k, ptr); err != nil {
+					return 0, wrapDecodeError(err, getElementPath(i), 0)
+				}
+				v := reflect.New(reflectType.Elem()).Elem()
+				if ptr, err = element.unmarshal(request, v, ptr); err != nil {
+					return 0, wrapDecodeError(err, getElementPath(i), 0)
+				}
+				m.SetMapIndex(k, v)
+			}
+			value.Set(m)
+			return ptr, nil
+		},
+		size: int64Size,
+	}, nil
+}
+
+// mapEntry is the key of a map entry and the key marshalled, to sort entries by
+type mapEntry struct {
+	key   []byte
+	value reflect.Value
+}
+
+// getSortedMapEntries marshals every key of the map with the codec of its keys and sorts them, as the order maps are
+// iterated in is random
+func getSortedMapEntries(value reflect.Value, key *codec) ([]mapEntry, error) {
+	entries := make([]mapEntry, 0, value.Len())
+	iter := value.MapRange()
+	for iter.Next() {
+		b, err := key.marshal(make([]byte, 0, key.size), iter.Key())
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, mapEntry{key: b, value: iter.Key()})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return string(entries[i].key) < string(entries[j].key)
+	})
+	return entries, nil
+}
+
+// compilePointerCodec compiles the codec of a pointer, which is marshalled as | uint8: 0 if nil, else 1 | value |
+func compilePointerCodec(reflectType reflect.Type, compiling map[reflect.Type]*codec) (*codec, error) {
+	element, err := compileCodec(reflectType.Elem(), compiling)
+	if err != nil {
+		return nil, err
+	}
+	return &codec{
+		marshal: func(response []byte, value reflect.Value) ([]byte, error) {
+			if value.IsNil() {
+				return append(response, 0), nil
+			}
+			return element.marshal(append(response, 1), value.Elem())
+		},
+		unmarshal: func(request []byte, value reflect.Value, ptr int) (int, error) {
+			if err := checkRemaining(request, ptr, uint8Size); err != nil {
+				return 0, err
+			}
+			if request[ptr] > 1 {
+				return 0, custom_errors.NewDecodeError(ptr, fmt.Sprintf("pointer must be 0 if nil or 1, got %d", request[ptr]))
+			}
+			isNil := request[ptr] == 0
+			ptr += uint8Size
+			if isNil {
+				value.Set(reflect.Zero(reflectType))
+				return ptr, nil
+			}
+			v := reflect.New(reflectType.Elem())
+			ptr, err := element.unmarshal(request, v.Elem(), ptr)
+			if err != nil {
+				return 0, err
+			}
+			value.Set(v)
+			return ptr, nil
+		},
+		size: uint8Size,
+	}, nil
+}
+
+// newFixedCodec makes the codec of the kinds that always take up size bytes
+func newFixedCodec(size int) *codec {
+	return &codec{
+		marshal: func(response []byte, value reflect.Value) ([]byte, error) {
+			return appendFixed(response, getFixedBits(value), size), nil
+		},
+		unmarshal: func(request []byte, value reflect.Value, ptr int) (int, error) {
+			if err := checkRemaining(request, ptr, size); err != nil {
+				return 0, err
+			}
+			setFixedBits(value, readFixed(request[ptr:ptr+size]))
+			return ptr + size, nil
+		},
+		size: size,
+	}
+}
+
+var (
+	// timeCodec marshals a time.Time as its unix seconds and nanoseconds
+	timeCodec = &codec{
+		marshal: func(response []byte, value reflect.Value) ([]byte, error) {
+			return appendTime(response, value), nil
+		},
+		unmarshal: func(request []byte, value reflect.Value, ptr int) (int, error) {
+			if err := checkRemaining(request, ptr, timeSize); err != nil {
+				return 0, err
+			}
+			setTime(value, request[ptr:ptr+timeSize])
+			return ptr + timeSize, nil
+		},
+		size: timeSize,
+	}
+	// stringCodec marshals strings with a string terminator (\0) at the end so that we know its the end of the string
+	stringCodec = &codec{
+		marshal: func(response []byte, value reflect.Value) ([]byte, error) {
+			response = append(response, value.String()...)
+			return append(response, stringTerminator), nil
+		},
+		unmarshal: func(request []byte, value reflect.Value, ptr int) (int, error) {
+			endString := ptr
+			for ; endString < len(request); endString++ {
+				if request[endString] == stringTerminator {
+					break
+				}
+			}
+			if endString == len(request) {
+				return 0, custom_errors.NewDecodeError(ptr, "string is not terminated")
+			}
+			value.SetString(string(request[ptr:endString]))
+			return endString + 1, nil
+		},
+		size: 1,
+	}
+)
+
+// marshalInterface marshals the value an interface holds with the codec of its type, all interfaces in golang are
+// pointers. Nil interfaces are skipped.
+func marshalInterface(response []byte, value reflect.Value) ([]byte, error) {
+	value, ok := getInterfaceValue(value)
+	if !ok {
+		return response, nil
+	}
+	c, err := getCodec(value.Type())
+	if err != nil {
+		return nil, err
+	}
+	return c.marshal(response, value)
+}
+
+// unmarshalInterface unmarshals into the value an interface holds, interfaces are only unmarshalled into if they already
+// hold a pointer to the type expected
+func unmarshalInterface(request []byte, value reflect.Value, ptr int) (int, error) {
+	if value.IsNil() || value.Elem().Kind() != reflect.Ptr {
+		return ptr, nil
+	}
+	value, ok := getInterfaceValue(value)
+	if !ok {
+		return ptr, nil
+	}
+	c, err := getCodec(value.Type())
+	if err != nil {
+		return 0, err
+	}
+	return c.unmarshal(request, value, ptr)
+}
+
+// getInterfaceValue gets the value the interface points to, false if it is nil
+func getInterfaceValue(value reflect.Value) (reflect.Value, bool) {
+	for value.Kind() == reflect.Interface || value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return reflect.Value{}, false
+		}
+		value = value.Elem()
+	}
+	return value, true
+}
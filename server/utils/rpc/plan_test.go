@@ -0,0 +1,183 @@
+package rpc
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/cyiafn/flight_information_system/server/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestDTOs makes every DTO with every field set, so that the benchmarks are of the largest DTOs of each type
+func newTestDTOs() []any {
+	res := []any{
+		&dto.GetFlightIdentifiersRequest{},
+		&dto.GetFlightIdentifiersResponse{},
+		&dto.GetFlightInformationRequest{},
+		&dto.GetFlightInformationResponse{},
+		&dto.MakeSeatReservationRequest{},
+		&dto.MakeSeatReservationResponse{},
+		&dto.MonitorSeatUpdatesCallbackRequest{},
+		&dto.MonitorSeatUpdatesCallbackResponse{},
+		&dto.UpdateFlightPriceRequest{},
+		&dto.UpdateFlightPriceResponse{},
+		&dto.CreateFlightRequest{},
+		&dto.CreateFlightResponse{},
+		&dto.CancelReservationRequest{},
+		&dto.CancelReservationResponse{},
+		&dto.GetReservationRequest{},
+		&dto.GetReservationResponse{},
+		&dto.GetSeatMapRequest{},
+		&dto.GetSeatMapResponse{},
+		&dto.SeatInformation{},
+		&dto.ReserveSpecificSeatsRequest{},
+		&dto.ReserveSpecificSeatsResponse{},
+		&dto.HoldSeatsRequest{},
+		&dto.HoldSeatsResponse{},
+		&dto.ConfirmHoldRequest{},
+		&dto.ConfirmHoldResponse{},
+		&dto.SearchFlightsRequest{},
+		&dto.SearchFlightsResponse{},
+		&dto.FlightInformation{},
+		&dto.FindItinerariesRequest{},
+		&dto.FindItinerariesResponse{},
+		&dto.Itinerary{},
+		&dto.CreateScheduleRequest{},
+		&dto.CreateScheduleResponse{},
+		&dto.UpdateScheduleRequest{},
+		&dto.UpdateScheduleResponse{},
+		&dto.CancelScheduleRequest{},
+		&dto.CancelScheduleResponse{},
+		&dto.UpdateFlightStatusRequest{},
+		&dto.UpdateFlightStatusResponse{},
+		&dto.CancelFlightRequest{},
+		&dto.MonitorFlightStatusCallbackRequest{},
+		&dto.MonitorFlightStatusCallbackResponse{},
+		&dto.MonitorPriceUpdatesCallbackRequest{},
+		&dto.MonitorPriceUpdatesCallbackResponse{},
+		&dto.MonitorNewFlightsCallbackRequest{},
+		&dto.MonitorNewFlightsCallbackResponse{},
+		&dto.AckCallbackRequest{},
+		&dto.GetUpdatesSinceRequest{},
+		&dto.GetUpdatesSinceResponse{},
+		&dto.FlightUpdate{},
+		&dto.MonitorSeatUpdatesResponse{},
+		&dto.MonitorFlightStatusResponse{},
+		&dto.MonitorPriceUpdatesResponse{},
+		&dto.MonitorNewFlightsResponse{},
+		&dto.UnsubscribeRequest{},
+		&dto.RenewSubscriptionRequest{},
+		&dto.RenewSubscriptionResponse{},
+		&dto.ListMySubscriptionsResponse{},
+		&dto.SubscriptionInformation{},
+	}
+	for _, v := range res {
+		fillTestValue(reflect.ValueOf(v).Elem(), 1)
+	}
+	return res
+}
+
+// fillTestValue sets every field to a value other than the zero value, slices have 3 elements. n is used to make the
+// values different from each other.
+func fillTestValue(value reflect.Value, n int) {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		value.SetInt(int64(n) * 1000003)
+	case reflect.Uint8:
+		value.SetUint(uint64(n%3 + 1))
+	case reflect.Float64:
+		value.SetFloat(float64(n) * 1.5)
+	case reflect.String:
+		// strings in slices cannot be empty for reflectUnmarshal
+		value.SetString("value " + strconv.Itoa(n))
+	case reflect.Slice:
+		value.Set(reflect.MakeSlice(value.Type(), 3, 3))
+		for i := 0; i < value.Len(); i++ {
+			fillTestValue(value.Index(i), n*10+i)
+		}
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			fillTestValue(value.Field(i), n*10+i)
+		}
+	}
+}
+
+func TestMarshalSameAsReflection(t *testing.T) {
+	for _, v := range append(newTestDTOs(), newTestStruct()) {
+		t.Run(reflect.TypeOf(v).Elem().Name(), func(t *testing.T) {
+			expected, err := reflectMarshal(v)
+			assert.Nil(t, err)
+			result, err := Marshal(v)
+			assert.Nil(t, err)
+			assert.Equal(t, expected, result)
+
+			newValue := reflect.New(reflect.TypeOf(v).Elem()).Interface()
+			err = Unmarshal(result, newValue)
+			assert.Nil(t, err)
+			assert.Equal(t, v, newValue)
+		})
+	}
+}
+
+func TestMarshalInterface(t *testing.T) {
+	result, err := Marshal(&dto.Response{StatusCode: 1, Data: &dto.CreateFlightResponse{FlightIdentifier: 5}})
+	assert.Nil(t, err)
+
+	res := &dto.CreateFlightResponse{}
+	err = Unmarshal(result, &dto.Response{Data: res})
+	assert.Nil(t, err)
+	assert.Equal(t, int32(5), res.FlightIdentifier)
+
+	// nil interfaces are skipped
+	result, err = Marshal(&dto.Response{StatusCode: 1})
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{1}, result)
+}
+
+func TestMarshalUnimplementedType(t *testing.T) {
+	_, err := Marshal(&struct{ Channel chan int }{})
+	assert.NotNil(t, err)
+	err = Unmarshal([]byte{}, &struct{ Complex complex128 }{})
+	assert.NotNil(t, err)
+	_, err = Marshal(&[]int{})
+	assert.NotNil(t, err)
+}
+
+func BenchmarkMarshal(b *testing.B) {
+	for _, v := range newTestDTOs() {
+		v := v
+		name := reflect.TypeOf(v).Elem().Name()
+		b.Run(name+"/Plan", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_, _ = Marshal(v)
+			}
+		})
+		b.Run(name+"/Reflection", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_, _ = reflectMarshal(v)
+			}
+		})
+	}
+}
+
+func BenchmarkUnmarshal(b *testing.B) {
+	for _, v := range newTestDTOs() {
+		reflectType := reflect.TypeOf(v).Elem()
+		payload, _ := Marshal(v)
+		b.Run(reflectType.Name()+"/Plan", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = Unmarshal(payload, reflect.New(reflectType).Interface())
+			}
+		})
+		b.Run(reflectType.Name()+"/Reflection", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = reflectUnmarshal(payload, reflect.New(reflectType).Interface())
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package rpc
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// the property tests marshal random values of random nested structures, which must unmarshal to the same value in every
+// schema version
+
+const (
+	// propertyTestRuns is the number of random structures tested
+	propertyTestRuns = 500
+	// maxTestDepth is the most structures, slices, maps and pointers nested in a random structure
+	maxTestDepth = 3
+)
+
+// randomPrimitiveTypes are the types that random structures are made of
+var randomPrimitiveTypes = []reflect.Type{
+	reflect.TypeOf(false),
+	reflect.TypeOf(int8(0)),
+	reflect.TypeOf(int16(0)),
+	reflect.TypeOf(int32(0)),
+	reflect.TypeOf(int64(0)),
+	reflect.TypeOf(0),
+	reflect.TypeOf(uint8(0)),
+	reflect.TypeOf(uint16(0)),
+	reflect.TypeOf(uint32(0)),
+	reflect.TypeOf(uint64(0)),
+	reflect.TypeOf(uint(0)),
+	reflect.TypeOf(float32(0)),
+	reflect.TypeOf(float64(0)),
+	reflect.TypeOf(""),
+	timeType,
+}
+
+// randomStructType makes a structure of 1 to 6 fields of random types
+func randomStructType(r *rand.Rand, depth int) reflect.Type {
+	fields := make([]reflect.StructField, 1+r.Intn(6))
+	for i := range fields {
+		fields[i] = reflect.StructField{Name: fmt.Sprintf("Field%d", i), Type: randomType(r, depth)}
+	}
+	return reflect.StructOf(fields)
+}
+
+// randomType makes a random type, nesting types until depth is 0
+func randomType(r *rand.Rand, depth int) reflect.Type {
+	if depth == 0 {
+		return randomPrimitiveTypes[r.Intn(len(randomPrimitiveTypes))]
+	}
+	switch r.Intn(5) {
+	case 0:
+		return reflect.SliceOf(randomType(r, depth-1))
+	case 1:
+		// time.Time is left out of keys, as the location of keys would not be the same
+		key := randomPrimitiveTypes[r.Intn(len(randomPrimitiveTypes)-1)]
+		return reflect.MapOf(key, randomType(r, depth-1))
+	case 2:
+		// a pointer to a pointer cannot be told apart from a nil pointer when the pointer it points to is nil
+		element := randomType(r, depth-1)
+		if element.Kind() == reflect.Ptr {
+			return element
+		}
+		return reflect.PtrTo(element)
+	case 3:
+		return randomStructType(r, depth-1)
+	}
+	return randomPrimitiveTypes[r.Intn(len(randomPrimitiveTypes))]
+}
+
+// setRandomValue sets the value to a random value of its type. Slices and maps are never nil, as they are unmarshalled
+// as empty ones.
+func setRandomValue(r *rand.Rand, value reflect.Value) {
+	if value.Type() == timeType {
+		value.Set(reflect.ValueOf(time.Unix(r.Int63n(1<<34)-1<<33, r.Int63n(int64(time.Second))).UTC()))
+		return
+	}
+	switch value.Kind() {
+	case reflect.Bool:
+		value.SetBool(r.Intn(2) == 1)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value.SetInt(int64(r.Uint64()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		value.SetUint(r.Uint64())
+	case reflect.Float32, reflect.Float64:
+		value.SetFloat(r.NormFloat64() * 1e6)
+	case reflect.String:
+		// strings cannot contain \0 in PositionalSchemaVersion
+		b := make([]byte, r.Intn(8))
+		for i := range b {
+			b[i] = byte('a' + r.Intn(26))
+		}
+		value.SetString(string(b))
+	case reflect.Slice:
+		length := r.Intn(4)
+		value.Set(reflect.MakeSlice(value.Type(), length, length))
+		for i := 0; i < length; i++ {
+			setRandomValue(r, value.Index(i))
+		}
+	case reflect.Map:
+		value.Set(reflect.MakeMap(value.Type()))
+		for i := r.Intn(4); i > 0; i-- {
+			key := reflect.New(value.Type().Key()).Elem()
+			setRandomValue(r, key)
+			element := reflect.New(value.Type().Elem()).Elem()
+			setRandomValue(r, element)
+			value.SetMapIndex(key, element)
+		}
+	case reflect.Ptr:
+		if r.Intn(3) == 0 {
+			return
+		}
+		value.Set(reflect.New(value.Type().Elem()))
+		setRandomValue(r, value.Elem())
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			setRandomValue(r, value.Field(i))
+		}
+	}
+}
+
+func TestMarshalRandomStructures(t *testing.T) {
+	seed := time.Now().UnixNano()
+	r := rand.New(rand.NewSource(seed))
+	for i := 0; i < propertyTestRuns; i++ {
+		reflectType := randomStructType(r, maxTestDepth)
+		v := reflect.New(reflectType)
+		setRandomValue(r, v.Elem())
+
+		for _, version := range []SchemaVersion{PositionalSchemaVersion, TaggedSchemaVersion} {
+			result, err := MarshalSchema(v.Interface(), version)
+			if !assert.Nil(t, err, "seed: %v, type: %v", seed, reflectType) {
+				return
+			}
+			newValue := reflect.New(reflectType)
+			err = UnmarshalSchema(result, newValue.Interface(), version)
+			if !assert.Nil(t, err, "seed: %v, type: %v", seed, reflectType) {
+				return
+			}
+			if !assert.Equal(t, v.Interface(), newValue.Interface(), "seed: %v, schema version: %v", seed, version) {
+				return
+			}
+
+			// maps are sorted, so the same value is always marshalled to the same bytes
+			again, err := MarshalSchema(newValue.Interface(), version)
+			assert.Nil(t, err)
+			if !assert.Equal(t, result, again, "seed: %v, schema version: %v", seed, version) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package rpc
+
+import (
+	"github.com/cyiafn/flight_information_system/server/custom_errors"
+	"github.com/pkg/errors"
+)
+
+/**
+Payloads are marshalled in one of two schema versions:
+ 1. PositionalSchemaVersion writes the fields of a structure one after another with no field IDs (Marshal/Unmarshal). Both
+    sides must have the exact same structure, so adding a field breaks older peers. Datagrams in this version do not have a
+    schema version byte in the header, as they predate it.
+ 2. TaggedSchemaVersion writes every field with its field number and wire type (MarshalTagged/UnmarshalTagged), so fields
+    unknown to the receiver are skipped and fields missing from the payload are left as zero values. Datagrams in this
+    version have the schema version byte right after the request/response type.
+
+The schema version byte is a control character (below 0x20), which a requestID (alphanumeric) never starts with, so a
+receiver can tell if the header of a datagram has a schema version byte or not. The server replies to each request (and
+sends callbacks to each subscriber) in the schema version it was sent in.
+*/
+
+// SchemaVersion is the version of the wire format of a payload
+type SchemaVersion uint8
+
+const (
+	// PositionalSchemaVersion is the original wire format, fields are written in order with no field IDs
+	PositionalSchemaVersion SchemaVersion = iota + 1
+	// TaggedSchemaVersion writes fields with their field number and wire type
+	TaggedSchemaVersion
+)
+
+const (
+	// CurrentSchemaVersion is the schema version new clients should send in
+	CurrentSchemaVersion = TaggedSchemaVersion
+	// maxSchemaVersionByte is the exclusive upper bound of a schema version byte in a header, the first printable character
+	maxSchemaVersionByte = 0x20
+)
+
+// IsSchemaVersionByte checks if the byte after the request/response type in a header is a schema version, else the header
+// does not have one and the payload is in PositionalSchemaVersion
+func IsSchemaVersionByte(b byte) bool {
+	return b > 0 && b < maxSchemaVersionByte
+}
+
+// MarshalSchema marshals the structure in the schema version provided
+func MarshalSchema(v any, version SchemaVersion) ([]byte, error) {
+	switch version {
+	case PositionalSchemaVersion:
+		return Marshal(v)
+	case TaggedSchemaVersion:
+		return MarshalTagged(v)
+	}
+	return nil, custom_errors.NewMarshallerError(errors.Errorf("unknown schema version: %v", version))
+}
+
+// UnmarshalSchema unmarshals the payload in the schema version provided into the structure
+func UnmarshalSchema(request []byte, v any, version SchemaVersion) error {
+	switch version {
+	case PositionalSchemaVersion:
+		return Unmarshal(request, v)
+	case TaggedSchemaVersion:
+		return UnmarshalTagged(request, v)
+	}
+	return custom_errors.NewMarshallerError(errors.Errorf("unknown schema version: %v", version))
+}
@@ -0,0 +1,149 @@
+package rpc
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/cyiafn/flight_information_system/server/dto"
+	"github.com/cyiafn/flight_information_system/server/dto/status_code"
+	"github.com/stretchr/testify/assert"
+)
+
+// previous versions of DTOs, before fields were appended to them, to check compatibility between old and new peers
+
+type oldGetFlightInformationResponse struct {
+	DepartureTime       int64
+	Airfare             float64
+	TotalAvailableSeats int32
+}
+
+type oldMonitorSeatUpdatesCallbackResponse struct {
+	TotalAvailableSeats int32
+}
+
+type oldFlightInformation struct {
+	FlightIdentifier    int32
+	SourceLocation      string
+	DestinationLocation string
+}
+
+type oldMonitorNewFlightsCallbackResponse struct {
+	Flight oldFlightInformation
+}
+
+func TestMarshalTagged(t *testing.T) {
+	result, err := MarshalTagged(newTestStruct())
+	assert.Nil(t, err)
+
+	newStruct := &testStruct{}
+	err = UnmarshalTagged(result, newStruct)
+	assert.Nil(t, err)
+	assert.Equal(t, *newTestStruct(), *newStruct)
+}
+
+func TestTaggedCompatibility(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Value    any
+		Target   any
+		Expected any
+	}{
+		{
+			Name:     "new peer to old peer skips unknown fields",
+			Value:    &dto.GetFlightInformationResponse{DepartureTime: 1, Airfare: 2.5, TotalAvailableSeats: 3, ArrivalTime: 4, SourceTimezone: "Asia/Singapore", Status: 1},
+			Target:   &oldGetFlightInformationResponse{},
+			Expected: &oldGetFlightInformationResponse{DepartureTime: 1, Airfare: 2.5, TotalAvailableSeats: 3},
+		},
+		{
+			Name:     "old peer to new peer defaults missing fields",
+			Value:    &oldGetFlightInformationResponse{DepartureTime: 1, Airfare: 2.5, TotalAvailableSeats: 3},
+			Target:   &dto.GetFlightInformationResponse{},
+			Expected: &dto.GetFlightInformationResponse{DepartureTime: 1, Airfare: 2.5, TotalAvailableSeats: 3},
+		},
+		{
+			Name:     "new callback to old subscriber",
+			Value:    &dto.Response{StatusCode: status_code.Success, Data: &dto.MonitorSeatUpdatesCallbackResponse{TotalAvailableSeats: 5, SequenceNumber: 7}},
+			Target:   &dto.Response{Data: &oldMonitorSeatUpdatesCallbackResponse{}},
+			Expected: &dto.Response{StatusCode: status_code.Success, Data: &oldMonitorSeatUpdatesCallbackResponse{TotalAvailableSeats: 5}},
+		},
+		{
+			Name:     "old callback to new subscriber",
+			Value:    &dto.Response{StatusCode: status_code.Success, Data: &oldMonitorSeatUpdatesCallbackResponse{TotalAvailableSeats: 5}},
+			Target:   &dto.Response{Data: &dto.MonitorSeatUpdatesCallbackResponse{}},
+			Expected: &dto.Response{StatusCode: status_code.Success, Data: &dto.MonitorSeatUpdatesCallbackResponse{TotalAvailableSeats: 5}},
+		},
+		{
+			Name:   "nested structure with appended fields",
+			Value:  &dto.MonitorNewFlightsCallbackResponse{Flight: dto.FlightInformation{FlightIdentifier: 1, SourceLocation: "Singapore", DestinationLocation: "Tokyo", Airfare: 300, Status: 2}},
+			Target: &oldMonitorNewFlightsCallbackResponse{},
+			Expected: &oldMonitorNewFlightsCallbackResponse{
+				Flight: oldFlightInformation{FlightIdentifier: 1, SourceLocation: "Singapore", DestinationLocation: "Tokyo"},
+			},
+		},
+		{
+			Name:     "error without data",
+			Value:    &dto.Response{StatusCode: status_code.NoSuchFlightIdentifier},
+			Target:   &dto.Response{Data: &dto.GetFlightInformationResponse{}},
+			Expected: &dto.Response{StatusCode: status_code.NoSuchFlightIdentifier, Data: &dto.GetFlightInformationResponse{}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			result, err := MarshalTagged(test.Value)
+			assert.Nil(t, err)
+
+			err = UnmarshalTagged(result, test.Target)
+			assert.Nil(t, err)
+			assert.Equal(t, test.Expected, test.Target)
+		})
+	}
+}
+
+// TestTaggedGolden freezes the wire format, a change here breaks every deployed peer
+func TestTaggedGolden(t *testing.T) {
+	golden := "0c0261621301000000000000001c090202000000050000002403020d0e"
+	value := &struct {
+		ABC          string
+		Int64        int64
+		Int32s       []int32
+		UnsignedInts []uint8
+	}{ABC: "ab", Int64: 1, Int32s: []int32{2, 5}, UnsignedInts: []uint8{13, 14}}
+
+	result, err := MarshalTagged(value)
+	assert.Nil(t, err)
+	assert.Equal(t, golden, hex.EncodeToString(result))
+}
+
+func TestUnmarshalTaggedPadding(t *testing.T) {
+	result, err := MarshalTagged(&nestedStruct{ABC: "abc", Int64: 5})
+	assert.Nil(t, err)
+
+	// byte array buffers are zero padded after the payload
+	padded := append(result, make([]byte, 100)...)
+	newStruct := &nestedStruct{}
+	assert.Nil(t, UnmarshalTagged(padded, newStruct))
+	assert.Equal(t, nestedStruct{ABC: "abc", Int64: 5}, *newStruct)
+}
+
+func TestUnmarshalTaggedMalformed(t *testing.T) {
+	result, err := MarshalTagged(newTestStruct())
+	assert.Nil(t, err)
+
+	t.Run("truncated", func(t *testing.T) {
+		for i := 1; i < len(result); i++ {
+			// must not panic, may succeed if cut between fields
+			_ = UnmarshalTagged(result[:i], &testStruct{})
+		}
+		assert.NotNil(t, UnmarshalTagged(result[:len(result)-1], &testStruct{}))
+	})
+
+	t.Run("wire type does not match", func(t *testing.T) {
+		// field 1 is a uint8, but is sent as a string
+		assert.NotNil(t, UnmarshalTagged([]byte{0x0c, 0x01, 0x61}, &testStruct{}))
+	})
+
+	t.Run("unknown wire type", func(t *testing.T) {
+		assert.NotNil(t, UnmarshalTagged([]byte{0x0f, 0x01}, &testStruct{}))
+	})
+}
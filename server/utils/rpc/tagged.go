@@ -0,0 +1,429 @@
+package rpc
+
+import (
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/cyiafn/flight_information_system/server/custom_errors"
+	"github.com/pkg/errors"
+)
+
+/*
+TaggedSchemaVersion wire format. A structure is a sequence of fields, each written as
+	| uvarint: field number << 3 | wire type | value |
+Field numbers are the position of the field in the structure starting from 1, so fields must only be appended to DTOs,
+never reordered or removed. A field that is no longer used can be renamed to _ to keep the numbering. Nil interfaces are
+not written at all.
+
+Values are written based on the wire type:
+	fixed8: bool, int8, uint8 (1 byte)
+	fixed32: int16, uint16, int32, uint32, float32 (4 bytes, little endian)
+	fixed64: int, int64, uint, uint64, float64 (8 bytes, little endian)
+	bytes: | uvarint: length | content |, where the content of a string is its bytes (no terminator), the content of a
+	structure is its fields, the content of a slice is | uvarint: no. of elements | elements |, the content of a map is
+	| uvarint: no. of entries | key | value | ... | and the content of a time.Time is its 12 bytes (see types.go).
+	Elements, keys and values are written as values without the field key. Pointers in slices and maps are bytes values
+	of the value they point to, empty if nil.
+
+A field key of 0 ends the structure, so the zero padding of a byte array buffer after a payload is ignored.
+*/
+
+// wireType is how the value of a field is written, so that fields unknown to the receiver can be skipped
+type wireType uint8
+
+// wire types start from 1 so that no field key is 0, as a field key of 0 ends the structure
+const (
+	fixed8WireType wireType = iota + 1
+	fixed32WireType
+	fixed64WireType
+	bytesWireType
+)
+
+const (
+	// wireTypeBits are the number of low bits of the field key used for the wire type
+	wireTypeBits = 3
+	// wireTypeMask masks the wire type of a field key
+	wireTypeMask = 1<<wireTypeBits - 1
+)
+
+// fixedWireTypeSizes are the sizes of the values of the fixed size wire types
+var fixedWireTypeSizes = map[wireType]int{
+	fixed8WireType:  uint8Size,
+	fixed32WireType: int32Size,
+	fixed64WireType: int64Size,
+}
+
+// MarshalTagged marshals any structure in TaggedSchemaVersion
+func MarshalTagged(v any) ([]byte, error) {
+	// if it is a nil pointer, we just return
+	if v == nil {
+		return nil, nil
+	}
+	reflectValue := reflect.ValueOf(v)
+	for reflectValue.Kind() == reflect.Ptr || reflectValue.Kind() == reflect.Interface {
+		reflectValue = reflectValue.Elem()
+	}
+	// we only allow marshalling of structures
+	if reflectValue.Kind() != reflect.Struct {
+		return nil, custom_errors.NewMarshallerError(errors.Errorf("value passed in is not of structure type"))
+	}
+	return appendTaggedStruct(make([]byte, 0), reflectValue)
+}
+
+// appendTaggedStruct appends every field of the structure with its field key
+func appendTaggedStruct(response []byte, reflectValue reflect.Value) ([]byte, error) {
+	reflectType := reflectValue.Type()
+	for i := 0; i < reflectValue.NumField(); i++ {
+		// unexported fields, including _, are not written but still take up their field number
+		if !reflectType.Field(i).IsExported() {
+			continue
+		}
+		field := reflectValue.Field(i)
+		// interfaces and pointers are written as the value they point to, nil ones are not written
+		for field.Kind() == reflect.Interface || field.Kind() == reflect.Ptr {
+			if field.IsNil() {
+				break
+			}
+			field = field.Elem()
+		}
+		if field.Kind() == reflect.Interface || field.Kind() == reflect.Ptr {
+			continue
+		}
+
+		fieldWireType, err := getWireType(field.Type())
+		if err != nil {
+			return nil, err
+		}
+		response = binary.AppendUvarint(response, uint64(i+1)<<wireTypeBits|uint64(fieldWireType))
+		response, err = appendTaggedValue(response, field, fieldWireType)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return response, nil
+}
+
+// appendTaggedValue appends the value of a field or slice element based on its wire type
+func appendTaggedValue(response []byte, value reflect.Value, valueWireType wireType) ([]byte, error) {
+	if size, ok := fixedWireTypeSizes[valueWireType]; ok {
+		return appendFixed(response, getFixedBits(value), size), nil
+	}
+
+	var content []byte
+	var err error
+	switch value.Kind() {
+	case reflect.String:
+		content = []byte(value.String())
+	case reflect.Struct:
+		if value.Type() == timeType {
+			content = appendTime(make([]byte, 0, timeSize), value)
+		} else if content, err = appendTaggedStruct(make([]byte, 0), value); err != nil {
+			return nil, err
+		}
+	case reflect.Slice:
+		elementWireType, err := getWireType(value.Type().Elem())
+		if err != nil {
+			return nil, err
+		}
+		content = binary.AppendUvarint(make([]byte, 0), uint64(value.Len()))
+		for i := 0; i < value.Len(); i++ {
+			if content, err = appendTaggedValue(content, value.Index(i), elementWireType); err != nil {
+				return nil, err
+			}
+		}
+	case reflect.Map:
+		if content, err = appendTaggedMap(value); err != nil {
+			return nil, err
+		}
+	case reflect.Ptr:
+		if !value.IsNil() {
+			elementWireType, err := getWireType(value.Type().Elem())
+			if err != nil {
+				return nil, err
+			}
+			if content, err = appendTaggedValue(make([]byte, 0), value.Elem(), elementWireType); err != nil {
+				return nil, err
+			}
+		}
+	}
+	response = binary.AppendUvarint(response, uint64(len(content)))
+	return append(response, content...), nil
+}
+
+// appendTaggedMap gets the content of a map, entries are sorted by their key so that a map is always marshalled to the same bytes
+func appendTaggedMap(value reflect.Value) ([]byte, error) {
+	keyWireType, err := getWireType(value.Type().Key())
+	if err != nil {
+		return nil, err
+	}
+	elementWireType, err := getWireType(value.Type().Elem())
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([][]byte, 0, value.Len())
+	elements := make(map[string]reflect.Value, value.Len())
+	iter := value.MapRange()
+	for iter.Next() {
+		key, err := appendTaggedValue(make([]byte, 0), iter.Key(), keyWireType)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+		elements[string(key)] = iter.Value()
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return string(keys[i]) < string(keys[j])
+	})
+
+	content := binary.AppendUvarint(make([]byte, 0), uint64(value.Len()))
+	for _, key := range keys {
+		content = append(content, key...)
+		if content, err = appendTaggedValue(content, elements[string(key)], elementWireType); err != nil {
+			return nil, err
+		}
+	}
+	return content, nil
+}
+
+// UnmarshalTagged unmarshals a payload in TaggedSchemaVersion into the structure v points to. Fields unknown to the
+// structure are skipped and fields missing from the payload are left as they are, the zero value for a new structure.
+// Interface fields are only unmarshalled into if they already hold a pointer to the type expected, like Unmarshal.
+func UnmarshalTagged(request []byte, v any) error {
+	reflectValue := reflect.ValueOf(v)
+	if reflectValue.Kind() != reflect.Ptr || reflectValue.Elem().Kind() != reflect.Struct {
+		return custom_errors.NewMarshallerError(errors.Errorf("value passed in is not a pointer to a structure"))
+	}
+	return unmarshalTaggedStruct(request, reflectValue.Elem())
+}
+
+// unmarshalTaggedStruct unmarshals every field in the payload into the structure
+func unmarshalTaggedStruct(request []byte, reflectValue reflect.Value) error {
+	ptr := 0
+	for ptr < len(request) {
+		key, n := binary.Uvarint(request[ptr:])
+		if n <= 0 {
+			return custom_errors.NewDecodeError(ptr, "malformed field key")
+		}
+		ptr += n
+		// the rest is padding
+		if key == 0 {
+			return nil
+		}
+
+		fieldWireType := wireType(key & wireTypeMask)
+		size, err := getTaggedValueSize(request[ptr:], fieldWireType)
+		if err != nil {
+			return wrapDecodeError(err, "", ptr)
+		}
+		value := request[ptr : ptr+size]
+		valueOffset := ptr
+		ptr += size
+
+		field, ok := getTaggedField(reflectValue, key>>wireTypeBits)
+		if !ok {
+			// a field added after the structure we are unmarshalling into, or one we cannot set
+			continue
+		}
+		if err := setTaggedValue(field, value, fieldWireType); err != nil {
+			return wrapDecodeError(err, reflectValue.Type().Field(int(key>>wireTypeBits-1)).Name, valueOffset)
+		}
+	}
+	return nil
+}
+
+// getTaggedField gets the field of the field number if it can be set
+func getTaggedField(reflectValue reflect.Value, fieldNumber uint64) (reflect.Value, bool) {
+	if fieldNumber == 0 || fieldNumber > uint64(reflectValue.NumField()) || !reflectValue.Type().Field(int(fieldNumber-1)).IsExported() {
+		return reflect.Value{}, false
+	}
+	field := reflectValue.Field(int(fieldNumber - 1))
+	// interfaces must already hold a pointer to the type to unmarshal into
+	if field.Kind() == reflect.Interface {
+		if field.IsNil() || field.Elem().Kind() != reflect.Ptr || field.Elem().IsNil() {
+			return reflect.Value{}, false
+		}
+		field = field.Elem()
+	}
+	for field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			field.Set(reflect.New(field.Type().Elem()))
+		}
+		field = field.Elem()
+	}
+	return field, field.CanSet()
+}
+
+// getTaggedValueSize gets the number of bytes of the value at the start of the payload, including the length of bytes values
+func getTaggedValueSize(request []byte, valueWireType wireType) (int, error) {
+	size := 0
+	switch valueWireType {
+	case fixed8WireType:
+		size = uint8Size
+	case fixed32WireType:
+		size = int32Size
+	case fixed64WireType:
+		size = int64Size
+	case bytesWireType:
+		length, n := binary.Uvarint(request)
+		if n <= 0 || length > uint64(len(request)-n) {
+			return 0, custom_errors.NewDecodeError(0, "malformed length of bytes value")
+		}
+		size = n + int(length)
+	default:
+		return 0, custom_errors.NewDecodeError(0, fmt.Sprintf("unknown wire type: %v", valueWireType))
+	}
+	if size > len(request) {
+		return 0, custom_errors.NewDecodeError(0, "payload ends in the middle of a value")
+	}
+	return size, nil
+}
+
+// setTaggedValue sets the field or slice element to the value, which must be of the wire type of the field
+func setTaggedValue(field reflect.Value, value []byte, valueWireType wireType) error {
+	expectedWireType, err := getWireType(field.Type())
+	if err != nil {
+		return err
+	}
+	if expectedWireType != valueWireType {
+		return custom_errors.NewDecodeError(0, fmt.Sprintf("wire type %v does not match %v of type %v", valueWireType, expectedWireType, field.Type()))
+	}
+
+	if _, ok := fixedWireTypeSizes[valueWireType]; ok {
+		setFixedBits(field, readFixed(value))
+		return nil
+	}
+
+	// the size of the value has already been checked
+	_, n := binary.Uvarint(value)
+	content := value[n:]
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(string(content))
+	case reflect.Struct:
+		if field.Type() == timeType {
+			if len(content) != timeSize {
+				return custom_errors.NewDecodeError(n, fmt.Sprintf("time must be %v bytes, got %v", timeSize, len(content)))
+			}
+			setTime(field, content)
+			return nil
+		}
+		return wrapDecodeError(unmarshalTaggedStruct(content, field), "", n)
+	case reflect.Slice:
+		return wrapDecodeError(setTaggedSlice(field, content), "", n)
+	case reflect.Map:
+		return wrapDecodeError(setTaggedMap(field, content), "", n)
+	case reflect.Ptr:
+		return wrapDecodeError(setTaggedPointer(field, content), "", n)
+	}
+	return nil
+}
+
+// setTaggedSlice sets the field to a slice of the elements in the content
+func setTaggedSlice(field reflect.Value, content []byte) error {
+	length, ptr := binary.Uvarint(content)
+	// every element takes up at least 1 byte, which bounds the memory allocated for a malformed length
+	if ptr <= 0 || length > uint64(len(content)-ptr) {
+		return custom_errors.NewDecodeError(0, "malformed length of slice")
+	}
+	elementWireType, err := getWireType(field.Type().Elem())
+	if err != nil {
+		return err
+	}
+
+	slice := reflect.MakeSlice(field.Type(), int(length), int(length))
+	for i := 0; i < int(length); i++ {
+		if ptr, err = setTaggedElement(slice.Index(i), content, ptr, elementWireType); err != nil {
+			return wrapDecodeError(err, getElementPath(i), 0)
+		}
+	}
+	field.Set(slice)
+	return nil
+}
+
+// setTaggedMap sets the field to a map of the entries in the content
+func setTaggedMap(field reflect.Value, content []byte) error {
+	length, ptr := binary.Uvarint(content)
+	// every entry takes up at least 2 bytes, which bounds the memory allocated for a malformed length
+	if ptr <= 0 || length > uint64(len(content)-ptr)/2 {
+		return custom_errors.NewDecodeError(0, "malformed length of map")
+	}
+	keyWireType, err := getWireType(field.Type().Key())
+	if err != nil {
+		return err
+	}
+	elementWireType, err := getWireType(field.Type().Elem())
+	if err != nil {
+		return err
+	}
+
+	m := reflect.MakeMapWithSize(field.Type(), int(length))
+	for i := 0; i < int(length); i++ {
+		key := reflect.New(field.Type().Key()).Elem()
+		if ptr, err = setTaggedElement(key, content, ptr, keyWireType); err != nil {
+			return wrapDecodeError(err, getElementPath(i), 0)
+		}
+		element := reflect.New(field.Type().Elem()).Elem()
+		if ptr, err = setTaggedElement(element, content, ptr, elementWireType); err != nil {
+			return wrapDecodeError(err, getElementPath(i), 0)
+		}
+		m.SetMapIndex(key, element)
+	}
+	field.Set(m)
+	return nil
+}
+
+// setTaggedElement sets an element, key or value of a map to the value at ptr of the content and returns the index of the
+// byte after it
+func setTaggedElement(element reflect.Value, content []byte, ptr int, elementWireType wireType) (int, error) {
+	size, err := getTaggedValueSize(content[ptr:], elementWireType)
+	if err != nil {
+		return 0, wrapDecodeError(err, "", ptr)
+	}
+	if err := setTaggedValue(element, content[ptr:ptr+size], elementWireType); err != nil {
+		return 0, wrapDecodeError(err, "", ptr)
+	}
+	return ptr + size, nil
+}
+
+// setTaggedPointer sets the pointer to a new value of the content, nil if the content is empty
+func setTaggedPointer(field reflect.Value, content []byte) error {
+	if len(content) == 0 {
+		field.Set(reflect.Zero(field.Type()))
+		return nil
+	}
+	elementWireType, err := getWireType(field.Type().Elem())
+	if err != nil {
+		return err
+	}
+	size, err := getTaggedValueSize(content, elementWireType)
+	if err != nil {
+		return err
+	}
+	if size != len(content) {
+		return custom_errors.NewDecodeError(0, "malformed pointer value")
+	}
+	element := reflect.New(field.Type().Elem())
+	if err := setTaggedValue(element.Elem(), content, elementWireType); err != nil {
+		return err
+	}
+	field.Set(element)
+	return nil
+}
+
+// getWireType gets the wire type of the type of a field
+func getWireType(reflectType reflect.Type) (wireType, error) {
+	switch reflectType.Kind() {
+	case reflect.Bool, reflect.Int8, reflect.Uint8:
+		return fixed8WireType, nil
+	case reflect.Int16, reflect.Uint16, reflect.Int32, reflect.Uint32, reflect.Float32:
+		return fixed32WireType, nil
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64, reflect.Float64:
+		return fixed64WireType, nil
+	case reflect.String, reflect.Slice, reflect.Struct, reflect.Map, reflect.Ptr:
+		return bytesWireType, nil
+	}
+	return 0, custom_errors.NewMarshallerError(errors.Errorf("unimplemented type, type: %v", reflectType))
+}
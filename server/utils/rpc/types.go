@@ -0,0 +1,136 @@
+package rpc
+
+import (
+	"encoding/binary"
+	"math"
+	"reflect"
+	"time"
+)
+
+/**
+Types supported by both schema versions and how they are marshalled in PositionalSchemaVersion. Integers and floats (IEEE
+754) are little endian.
+	bool, int8, uint8: 1 byte, a bool is 1 if true, else 0
+	int16, uint16: 2 bytes
+	int32, uint32, float32: 4 bytes
+	int, int64, uint, uint64, float64: 8 bytes
+	string: | bytes | \0 |, so strings cannot contain \0
+	slice: | int64: no. of elements | elements |
+	map: | int64: no. of entries | key | value | ... |, entries are sorted by their marshalled key so that a map is always
+	     marshalled to the same bytes
+	pointer: | uint8: 0 if nil, else 1 | value it points to |
+	time.Time: | int64: unix seconds | int32: nanoseconds |, unmarshalled in UTC as the location is not marshalled
+	structure: | fields |, unexported fields are skipped
+	interface: the value it holds, nil interfaces are skipped
+
+In TaggedSchemaVersion, fixed size types are written as the smallest wire type they fit in (int16 as fixed32), a time.Time
+is a bytes value of the same 12 bytes, a map is a bytes value of | uvarint: no. of entries | key | value | ... | and a
+pointer is the value it points to, nil ones are not written. Pointers in slices and maps cannot be left out, so they are
+bytes values that are empty if nil.
+*/
+
+const (
+	int16Size = 2
+	// timeSize is the size of a time.Time, unix seconds and nanoseconds
+	timeSize = int64Size + int32Size
+)
+
+// timeType is marshalled as a time instead of a structure, as its fields are unexported
+var timeType = reflect.TypeOf(time.Time{})
+
+// fixedSizes are the sizes of the kinds that always take up the same number of bytes in PositionalSchemaVersion
+var fixedSizes = map[reflect.Kind]int{
+	reflect.Bool:    uint8Size,
+	reflect.Int8:    uint8Size,
+	reflect.Uint8:   uint8Size,
+	reflect.Int16:   int16Size,
+	reflect.Uint16:  int16Size,
+	reflect.Int32:   int32Size,
+	reflect.Uint32:  int32Size,
+	reflect.Float32: int32Size,
+	reflect.Int:     int64Size,
+	reflect.Int64:   int64Size,
+	reflect.Uint:    int64Size,
+	reflect.Uint64:  int64Size,
+	reflect.Float64: float64Size,
+}
+
+// getFixedBits gets the bits of a value of a fixed size kind, which are written in the number of bytes of its size
+func getFixedBits(value reflect.Value) uint64 {
+	switch value.Kind() {
+	case reflect.Bool:
+		if value.Bool() {
+			return 1
+		}
+		return 0
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return uint64(value.Int())
+	case reflect.Float32:
+		return uint64(math.Float32bits(float32(value.Float())))
+	case reflect.Float64:
+		return math.Float64bits(value.Float())
+	}
+	return value.Uint()
+}
+
+// setFixedBits sets a value of a fixed size kind to the bits read, integers are sign extended from their size
+func setFixedBits(value reflect.Value, bits uint64) {
+	switch value.Kind() {
+	case reflect.Bool:
+		value.SetBool(bits != 0)
+	case reflect.Int8:
+		value.SetInt(int64(int8(bits)))
+	case reflect.Int16:
+		value.SetInt(int64(int16(bits)))
+	case reflect.Int32:
+		value.SetInt(int64(int32(bits)))
+	case reflect.Int, reflect.Int64:
+		value.SetInt(int64(bits))
+	case reflect.Float32:
+		value.SetFloat(float64(math.Float32frombits(uint32(bits))))
+	case reflect.Float64:
+		value.SetFloat(math.Float64frombits(bits))
+	default:
+		value.SetUint(bits)
+	}
+}
+
+// appendFixed appends the bits in size bytes
+func appendFixed(response []byte, bits uint64, size int) []byte {
+	switch size {
+	case uint8Size:
+		return append(response, uint8(bits))
+	case int16Size:
+		return binary.LittleEndian.AppendUint16(response, uint16(bits))
+	case int32Size:
+		return binary.LittleEndian.AppendUint32(response, uint32(bits))
+	}
+	return binary.LittleEndian.AppendUint64(response, bits)
+}
+
+// readFixed reads the bits of the size of value
+func readFixed(value []byte) uint64 {
+	switch len(value) {
+	case uint8Size:
+		return uint64(value[0])
+	case int16Size:
+		return uint64(binary.LittleEndian.Uint16(value))
+	case int32Size:
+		return uint64(binary.LittleEndian.Uint32(value))
+	}
+	return binary.LittleEndian.Uint64(value)
+}
+
+// appendTime appends the unix seconds and nanoseconds of a time.Time
+func appendTime(response []byte, value reflect.Value) []byte {
+	t := value.Interface().(time.Time)
+	response = binary.LittleEndian.AppendUint64(response, uint64(t.Unix()))
+	return binary.LittleEndian.AppendUint32(response, uint32(t.Nanosecond()))
+}
+
+// setTime sets a time.Time to the unix seconds and nanoseconds of the timeSize bytes of value, in UTC
+func setTime(value reflect.Value, b []byte) {
+	seconds := int64(binary.LittleEndian.Uint64(b))
+	nanoseconds := int64(binary.LittleEndian.Uint32(b[int64Size:]))
+	value.Set(reflect.ValueOf(time.Unix(seconds, nanoseconds).UTC()))
+}